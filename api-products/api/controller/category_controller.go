@@ -0,0 +1,8 @@
+package controller
+
+import "github.com/gin-gonic/gin"
+
+type CategoryController interface {
+	GetAll(ctx *gin.Context)
+	GetProducts(ctx *gin.Context)
+}
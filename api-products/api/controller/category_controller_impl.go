@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"github.com/dieg0code/serverles-api-scraper/api/data/request"
+	"github.com/dieg0code/serverles-api-scraper/api/service"
+	"github.com/dieg0code/shared/json/response"
+	"github.com/dieg0code/shared/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type CategoryControllerImpl struct {
+	CategoryService service.CategoryService
+}
+
+// GetAll implements CategoryController.
+func (c *CategoryControllerImpl) GetAll(ctx *gin.Context) {
+	categoriesResponse, err := c.CategoryService.GetAll(ctx.Request.Context())
+	if err != nil {
+		logrus.WithError(err).Error("[CategoryControllerImpl.GetAll] Error getting all categories")
+		problem.Render(ctx, err, "Error getting all categories")
+		return
+	}
+
+	successResponse := response.BaseResponse{
+		Code:    200,
+		Status:  "OK",
+		Message: "Success getting all categories",
+		Data:    categoriesResponse,
+	}
+
+	ctx.JSON(200, successResponse)
+}
+
+// GetProducts implements CategoryController.
+func (c *CategoryControllerImpl) GetProducts(ctx *gin.Context) {
+	slug := ctx.Param("slug")
+	if slug == "" {
+		problem.BadRequest(ctx, nil, "Category slug is required")
+		return
+	}
+
+	filter := request.ProductFilterRequest{}
+	err := ctx.ShouldBindQuery(&filter)
+	if err != nil {
+		logrus.WithError(err).Error("[CategoryControllerImpl.GetProducts] Error binding query")
+		problem.BadRequest(ctx, err, "Invalid product filters")
+		return
+	}
+
+	productResponse, pagination, err := c.CategoryService.GetProducts(ctx.Request.Context(), slug, filter)
+	if err != nil {
+		logrus.WithError(err).Error("[CategoryControllerImpl.GetProducts] Error getting products by category")
+		problem.Render(ctx, err, "Error getting products by category")
+		return
+	}
+
+	successResponse := response.BaseResponse{
+		Code:       200,
+		Status:     "OK",
+		Message:    "Success getting products by category",
+		Data:       productResponse,
+		Pagination: &pagination,
+	}
+
+	ctx.JSON(200, successResponse)
+}
+
+func NewCategoryControllerImpl(categoryService service.CategoryService) CategoryController {
+	return &CategoryControllerImpl{CategoryService: categoryService}
+}
@@ -0,0 +1,158 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dieg0code/serverles-api-scraper/api/data/request"
+	"github.com/dieg0code/serverles-api-scraper/api/repository"
+	"github.com/dieg0code/shared/json/response"
+	"github.com/dieg0code/shared/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCategoryController_GetAll(t *testing.T) {
+	t.Run("GetAll_Success", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		mockService := new(mocks.MockCategoryService)
+		categoryController := NewCategoryControllerImpl(mockService)
+
+		router := gin.Default()
+		router.GET("/categories", categoryController.GetAll)
+
+		mockService.On("GetAll").Return([]response.CategoryResponse{
+			{Store: "cugat.cl", Slug: "despensa", Name: "Despensa", ProductCount: 42, LastUpdated: "2024-08-20T10:05:00Z"},
+		}, nil)
+
+		req, err := http.NewRequest(http.MethodGet, "/categories", nil)
+		assert.NoError(t, err, "Expected no error creating request")
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code, "Expected status code 200")
+
+		var response response.BaseResponse
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		assert.NoError(t, err, "Expected no error unmarshalling response")
+		assert.Equal(t, 200, response.Code, "Response code should be 200")
+		assert.Equal(t, "OK", response.Status, "Response status should be OK")
+		assert.Len(t, response.Data, 1, "Response data should contain one category")
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("GetAll_Error", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		mockService := new(mocks.MockCategoryService)
+		categoryController := NewCategoryControllerImpl(mockService)
+
+		router := gin.Default()
+		router.GET("/categories", categoryController.GetAll)
+
+		mockService.On("GetAll").Return([]response.CategoryResponse{}, assert.AnError)
+
+		req, err := http.NewRequest(http.MethodGet, "/categories", nil)
+		assert.NoError(t, err, "Expected no error creating request")
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code, "Expected status code 500")
+
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestCategoryController_GetProducts(t *testing.T) {
+	t.Run("GetProducts_Success", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		mockService := new(mocks.MockCategoryService)
+		categoryController := NewCategoryControllerImpl(mockService)
+
+		router := gin.Default()
+		router.GET("/categories/:slug/products", categoryController.GetProducts)
+
+		mockService.On("GetProducts", "despensa", request.ProductFilterRequest{Store: "cugat.cl", PageSize: 1}).Return([]response.ProductResponse{
+			{ProductID: "test-id", Name: "Arroz", CategorySlug: "despensa"},
+		}, response.PaginationResponse{PageSize: 1, Count: 1, NextCursor: "next", HasMore: true}, nil)
+
+		req, err := http.NewRequest(http.MethodGet, "/categories/despensa/products?store=cugat.cl&page_size=1", nil)
+		assert.NoError(t, err, "Expected no error creating request")
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code, "Expected status code 200")
+
+		var body response.BaseResponse
+		err = json.Unmarshal(rec.Body.Bytes(), &body)
+		assert.NoError(t, err, "Expected no error unmarshalling response")
+		assert.Len(t, body.Data, 1, "Response data should contain one product")
+		if assert.NotNil(t, body.Pagination, "Expected pagination metadata") {
+			assert.Equal(t, "next", body.Pagination.NextCursor, "Expected the next cursor")
+		}
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("GetProducts_InvalidFilters", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		mockService := new(mocks.MockCategoryService)
+		categoryController := NewCategoryControllerImpl(mockService)
+
+		router := gin.Default()
+		router.GET("/categories/:slug/products", categoryController.GetProducts)
+
+		req, err := http.NewRequest(http.MethodGet, "/categories/despensa/products?sort=stock", nil)
+		assert.NoError(t, err, "Expected no error creating request")
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code, "Expected status code 400")
+		mockService.AssertNotCalled(t, "GetProducts", mock.Anything, mock.Anything)
+	})
+
+	t.Run("GetProducts_InvalidCursor", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		mockService := new(mocks.MockCategoryService)
+		categoryController := NewCategoryControllerImpl(mockService)
+
+		router := gin.Default()
+		router.GET("/categories/:slug/products", categoryController.GetProducts)
+
+		mockService.On("GetProducts", "despensa", request.ProductFilterRequest{Cursor: "bad"}).Return([]response.ProductResponse(nil), response.PaginationResponse{}, repository.ErrInvalidCursor)
+
+		req, err := http.NewRequest(http.MethodGet, "/categories/despensa/products?cursor=bad", nil)
+		assert.NoError(t, err, "Expected no error creating request")
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code, "Expected status code 400")
+	})
+
+	t.Run("GetProducts_Error", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		mockService := new(mocks.MockCategoryService)
+		categoryController := NewCategoryControllerImpl(mockService)
+
+		router := gin.Default()
+		router.GET("/categories/:slug/products", categoryController.GetProducts)
+
+		mockService.On("GetProducts", "despensa", request.ProductFilterRequest{}).Return([]response.ProductResponse{}, response.PaginationResponse{}, assert.AnError)
+
+		req, err := http.NewRequest(http.MethodGet, "/categories/despensa/products", nil)
+		assert.NoError(t, err, "Expected no error creating request")
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code, "Expected status code 500")
+	})
+}
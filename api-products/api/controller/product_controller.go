@@ -5,5 +5,7 @@ import "github.com/gin-gonic/gin"
 type ProductController interface {
 	GetAll(ctx *gin.Context)
 	GetByID(ctx *gin.Context)
+	Search(ctx *gin.Context)
 	UpdateData(ctx *gin.Context)
+	GetPriceHistory(ctx *gin.Context)
 }
@@ -1,9 +1,11 @@
 package controller
 
 import (
+	"github.com/awslabs/aws-lambda-go-api-proxy/core"
 	"github.com/dieg0code/serverles-api-scraper/api/data/request"
 	"github.com/dieg0code/serverles-api-scraper/api/service"
 	"github.com/dieg0code/shared/json/response"
+	"github.com/dieg0code/shared/problem"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
@@ -14,25 +16,27 @@ type ProductControllerImpl struct {
 
 // GetAll implements ProductController.
 func (p *ProductControllerImpl) GetAll(ctx *gin.Context) {
-	productResponse, err := p.ProductService.GetAll()
+	filter := request.ProductFilterRequest{}
+	err := ctx.ShouldBindQuery(&filter)
+	if err != nil {
+		logrus.WithError(err).Error("[ProductControllerImpl.GetAll] Error binding query")
+		problem.BadRequest(ctx, err, "Invalid product filters")
+		return
+	}
+
+	productResponse, pagination, err := p.ProductService.GetAll(ctx.Request.Context(), filter)
 	if err != nil {
 		logrus.WithError(err).Error("[ProductControllerImpl.GetAll] Error getting all products")
-		errorResponse := response.BaseResponse{
-			Code:    500,
-			Status:  "Internal Server Error",
-			Message: "Error getting all products",
-			Data:    nil,
-		}
-
-		ctx.JSON(500, errorResponse)
+		problem.Render(ctx, err, "Error getting all products")
 		return
 	}
 
 	successResponse := response.BaseResponse{
-		Code:    200,
-		Status:  "OK",
-		Message: "Success getting all products",
-		Data:    productResponse,
+		Code:       200,
+		Status:     "OK",
+		Message:    "Success getting all products",
+		Data:       productResponse,
+		Pagination: &pagination,
 	}
 
 	ctx.JSON(200, successResponse)
@@ -42,28 +46,14 @@ func (p *ProductControllerImpl) GetAll(ctx *gin.Context) {
 func (p *ProductControllerImpl) GetByID(ctx *gin.Context) {
 	productId := ctx.Param("productId")
 	if productId == "" {
-		errorResponse := response.BaseResponse{
-			Code:    400,
-			Status:  "Bad Request",
-			Message: "Product ID is required",
-			Data:    nil,
-		}
-
-		ctx.JSON(400, errorResponse)
+		problem.BadRequest(ctx, nil, "Product ID is required")
 		return
 	}
 
-	productResponse, err := p.ProductService.GetByID(productId)
+	productResponse, err := p.ProductService.GetByID(ctx.Request.Context(), productId)
 	if err != nil {
 		logrus.WithError(err).Error("[ProductControllerImpl.GetByID] Error getting product by ID")
-		errorResponse := response.BaseResponse{
-			Code:    500,
-			Status:  "Internal Server Error",
-			Message: "Error getting product by ID",
-			Data:    nil,
-		}
-
-		ctx.JSON(500, errorResponse)
+		problem.Render(ctx, err, "Error getting product by ID")
 		return
 	}
 
@@ -77,46 +67,85 @@ func (p *ProductControllerImpl) GetByID(ctx *gin.Context) {
 	ctx.JSON(200, successResponse)
 }
 
+// Search implements ProductController.
+func (p *ProductControllerImpl) Search(ctx *gin.Context) {
+	searchReq := request.SearchRequest{}
+	err := ctx.ShouldBindQuery(&searchReq)
+	if err != nil {
+		logrus.WithError(err).Error("[ProductControllerImpl.Search] Error binding query")
+		problem.BadRequest(ctx, err, "Invalid search parameters")
+		return
+	}
+
+	productResponse, err := p.ProductService.Search(ctx.Request.Context(), searchReq)
+	if err != nil {
+		logrus.WithError(err).Error("[ProductControllerImpl.Search] Error searching products")
+		problem.Render(ctx, err, "Error searching products")
+		return
+	}
+
+	successResponse := response.BaseResponse{
+		Code:    200,
+		Status:  "OK",
+		Message: "Success searching products",
+		Data:    productResponse,
+	}
+
+	ctx.JSON(200, successResponse)
+}
+
+// GetPriceHistory implements ProductController.
+func (p *ProductControllerImpl) GetPriceHistory(ctx *gin.Context) {
+	productId := ctx.Param("productId")
+	if productId == "" {
+		problem.BadRequest(ctx, nil, "Product ID is required")
+		return
+	}
+
+	historyReq := request.PriceHistoryRequest{}
+	err := ctx.ShouldBindQuery(&historyReq)
+	if err != nil {
+		logrus.WithError(err).Error("[ProductControllerImpl.GetPriceHistory] Error binding query")
+		problem.BadRequest(ctx, err, "Dates must use the YYYY-MM-DD format")
+		return
+	}
+
+	historyResponse, err := p.ProductService.GetPriceHistory(ctx.Request.Context(), productId, historyReq)
+	if err != nil {
+		logrus.WithError(err).Error("[ProductControllerImpl.GetPriceHistory] Error getting price history")
+		problem.Render(ctx, err, "Error getting price history")
+		return
+	}
+
+	successResponse := response.BaseResponse{
+		Code:    200,
+		Status:  "OK",
+		Message: "Success getting price history",
+		Data:    historyResponse,
+	}
+
+	ctx.JSON(200, successResponse)
+}
+
 // UpdateData implements ProductController.
 func (p *ProductControllerImpl) UpdateData(ctx *gin.Context) {
 	updateReq := request.UpdateDataRequest{}
 	err := ctx.BindJSON(&updateReq)
 	if err != nil {
 		logrus.WithError(err).Error("[ProductControllerImpl.UpdateData] Error binding request")
-		errorResponse := response.BaseResponse{
-			Code:    400,
-			Status:  "Bad Request",
-			Message: "Error binding request",
-			Data:    nil,
-		}
-
-		ctx.JSON(400, errorResponse)
+		problem.BadRequest(ctx, err, "Error binding request")
 		return
 	}
 
-	success, err := p.ProductService.UpdateData(updateReq)
+	runID, err := p.ProductService.UpdateData(ctx.Request.Context(), updateReq, triggeredBy(ctx))
 	if err != nil {
 		logrus.WithError(err).Error("[ProductControllerImpl.UpdateData] Error updating data")
-		errorResponse := response.BaseResponse{
-			Code:    500,
-			Status:  "Internal Server Error",
-			Message: "Error updating data",
-			Data:    nil,
-		}
-
-		ctx.JSON(500, errorResponse)
+		problem.Render(ctx, err, "Error updating data")
 		return
 	}
 
-	if !success {
-		errorResponse := response.BaseResponse{
-			Code:    400,
-			Status:  "Bad Request",
-			Message: "Error updating data",
-			Data:    nil,
-		}
-
-		ctx.JSON(400, errorResponse)
+	if runID == "" {
+		problem.BadRequest(ctx, nil, "Error updating data")
 		return
 	}
 
@@ -124,12 +153,27 @@ func (p *ProductControllerImpl) UpdateData(ctx *gin.Context) {
 		Code:    200,
 		Status:  "OK",
 		Message: "Data scraping started",
-		Data:    nil,
+		Data:    response.UpdateDataResponse{RunID: runID},
 	}
 
 	ctx.JSON(200, successResponse)
 }
 
+// triggeredBy obtiene el usuario autenticado desde el contexto del authorizer de API Gateway
+func triggeredBy(ctx *gin.Context) string {
+	apiGwContext, ok := core.GetAPIGatewayContextFromContext(ctx.Request.Context())
+	if !ok {
+		return ""
+	}
+
+	userID, ok := apiGwContext.Authorizer["user_id"].(string)
+	if !ok {
+		return ""
+	}
+
+	return userID
+}
+
 func NewProductControllerImpl(productService service.ProductService) ProductController {
 	return &ProductControllerImpl{ProductService: productService}
 }
@@ -2,18 +2,37 @@ package controller
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/awslabs/aws-lambda-go-api-proxy/core"
 	"github.com/dieg0code/serverles-api-scraper/api/data/request"
+	"github.com/dieg0code/serverles-api-scraper/api/repository"
+	"github.com/dieg0code/shared/apperror"
 	"github.com/dieg0code/shared/json/response"
 	"github.com/dieg0code/shared/mocks"
+	"github.com/dieg0code/shared/models"
+	"github.com/dieg0code/shared/problem"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
+// Como en el router, los errores de validacion nombran los campos por su tag
+// form. Tiene que ser antes del primer binding porque validator guarda en
+// cache los nombres de cada struct.
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		problem.RegisterTagNames(v)
+	}
+}
+
 func TestProductController_GetAll(t *testing.T) {
 	t.Run("GetAll_Success", func(t *testing.T) {
 		gin.SetMode(gin.TestMode)
@@ -23,7 +42,7 @@ func TestProductController_GetAll(t *testing.T) {
 		router := gin.Default()
 		router.GET("/products", productController.GetAll)
 
-		mockService.On("GetAll").Return([]response.ProductResponse{
+		mockService.On("GetAll", request.ProductFilterRequest{}).Return([]response.ProductResponse{
 			{
 				ProductID:       "test-id",
 				Name:            "Test Product",
@@ -38,7 +57,7 @@ func TestProductController_GetAll(t *testing.T) {
 				OriginalPrice:   200,
 				DiscountedPrice: 180,
 			},
-		}, nil)
+		}, response.PaginationResponse{PageSize: 20, Count: 2, NextCursor: "next", HasMore: true}, nil)
 
 		req, err := http.NewRequest(http.MethodGet, "/products", nil)
 		assert.NoError(t, err, "Expected no error creating request")
@@ -53,6 +72,186 @@ func TestProductController_GetAll(t *testing.T) {
 		assert.NoError(t, err, "Expected no error unmarshalling response")
 		assert.Equal(t, 200, response.Code, "Response code should be 200")
 		assert.Equal(t, "OK", response.Status, "Response status should be Success")
+		if assert.NotNil(t, response.Pagination, "Expected pagination metadata") {
+			assert.Equal(t, 2, response.Pagination.Count, "Expected the page count")
+			assert.Equal(t, "next", response.Pagination.NextCursor, "Expected the next cursor")
+			assert.True(t, response.Pagination.HasMore, "Expected more pages")
+		}
+	})
+
+	t.Run("GetAll_FilterByStore", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		mockService := new(mocks.MockProductService)
+		productController := NewProductControllerImpl(mockService)
+
+		router := gin.Default()
+		router.GET("/products", productController.GetAll)
+
+		mockService.On("GetAll", request.ProductFilterRequest{Store: "cugat.cl"}).Return([]response.ProductResponse{
+			{
+				ProductID: "test-id",
+				Store:     "cugat.cl",
+				Name:      "Test Product",
+			},
+		}, response.PaginationResponse{PageSize: 20, Count: 1}, nil)
+
+		req, err := http.NewRequest(http.MethodGet, "/products?store=cugat.cl", nil)
+		assert.NoError(t, err, "Expected no error creating request")
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code, "Expected status code 200")
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("GetAll_FilterOnPromo", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		mockService := new(mocks.MockProductService)
+		productController := NewProductControllerImpl(mockService)
+
+		router := gin.Default()
+		router.GET("/products", productController.GetAll)
+
+		mockService.On("GetAll", request.ProductFilterRequest{OnPromo: true}).Return([]response.ProductResponse{
+			{
+				ProductID: "test-id",
+				Name:      "Test Product",
+				Promotion: &response.PromotionResponse{Kind: models.PromotionBuyXPayY, Quantity: 3, PayQuantity: 2, UnitPrice: 600},
+			},
+		}, response.PaginationResponse{PageSize: 20, Count: 1}, nil)
+
+		req, err := http.NewRequest(http.MethodGet, "/products?on_promo=true", nil)
+		assert.NoError(t, err, "Expected no error creating request")
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code, "Expected status code 200")
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("GetAll_InvalidFilter", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		mockService := new(mocks.MockProductService)
+		productController := NewProductControllerImpl(mockService)
+
+		router := gin.Default()
+		router.GET("/products", productController.GetAll)
+
+		req, err := http.NewRequest(http.MethodGet, "/products?on_promo=maybe", nil)
+		assert.NoError(t, err, "Expected no error creating request")
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code, "Expected status code 400")
+		mockService.AssertNotCalled(t, "GetAll", mock.Anything)
+	})
+
+	t.Run("GetAll_PaginationAndSort", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		mockService := new(mocks.MockProductService)
+		productController := NewProductControllerImpl(mockService)
+
+		router := gin.Default()
+		router.GET("/products", productController.GetAll)
+
+		mockService.On("GetAll", request.ProductFilterRequest{
+			Category:   "despensa",
+			Discounted: true,
+			MinPrice:   1000,
+			MaxPrice:   5000,
+			Sort:       "price",
+			Order:      "desc",
+			PageSize:   10,
+			Cursor:     "abc",
+		}).Return([]response.ProductResponse{}, response.PaginationResponse{PageSize: 10}, nil)
+
+		req, err := http.NewRequest(http.MethodGet, "/products?category=despensa&discounted=true&min_price=1000&max_price=5000&sort=price&order=desc&page_size=10&cursor=abc", nil)
+		assert.NoError(t, err, "Expected no error creating request")
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code, "Expected status code 200")
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("GetAll_InvalidPagination", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		mockService := new(mocks.MockProductService)
+		productController := NewProductControllerImpl(mockService)
+
+		router := gin.Default()
+		router.GET("/products", productController.GetAll)
+
+		for _, query := range []string{"sort=rating", "order=up", "page_size=101", "min_price=5000&max_price=1000", "min_price=-1"} {
+			req, err := http.NewRequest(http.MethodGet, "/products?"+query, nil)
+			assert.NoError(t, err, "Expected no error creating request")
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code, "Expected status code 400 for %s", query)
+		}
+		mockService.AssertNotCalled(t, "GetAll", mock.Anything)
+	})
+
+	t.Run("GetAll_FieldErrors", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		mockService := new(mocks.MockProductService)
+		productController := NewProductControllerImpl(mockService)
+
+		router := gin.Default()
+		router.GET("/products", productController.GetAll)
+
+		req, err := http.NewRequest(http.MethodGet, "/products?sort=rating&page_size=101", nil)
+		assert.NoError(t, err, "Expected no error creating request")
+		req.Header.Set(problem.RequestIDHeader, "request-id")
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code, "Expected status code 400")
+		assert.Equal(t, problem.ContentType, rec.Header().Get("Content-Type"), "Expected a problem+json response")
+
+		var body problem.Problem
+		err = json.Unmarshal(rec.Body.Bytes(), &body)
+		assert.NoError(t, err, "Expected no error unmarshalling response")
+		assert.Equal(t, "Invalid product filters", body.Detail, "Expected the binding error detail")
+		assert.Equal(t, "VALIDATION_ERROR", body.Code, "Expected error code to be VALIDATION_ERROR")
+		assert.Equal(t, "request-id", body.Instance, "Expected the request id as instance")
+		assert.Equal(t, []problem.FieldError{
+			{Field: "sort", Rule: "oneof", Message: "must be one of: name, price, discount"},
+			{Field: "page_size", Rule: "max", Message: "must be at most 100"},
+		}, body.Errors, "Expected one error per invalid field")
+		mockService.AssertNotCalled(t, "GetAll", mock.Anything)
+	})
+
+	t.Run("GetAll_InvalidCursor", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		mockService := new(mocks.MockProductService)
+		productController := NewProductControllerImpl(mockService)
+
+		router := gin.Default()
+		router.GET("/products", productController.GetAll)
+
+		mockService.On("GetAll", request.ProductFilterRequest{Cursor: "bad"}).Return([]response.ProductResponse(nil), response.PaginationResponse{}, repository.ErrInvalidCursor)
+
+		req, err := http.NewRequest(http.MethodGet, "/products?cursor=bad", nil)
+		assert.NoError(t, err, "Expected no error creating request")
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code, "Expected status code 400")
+
+		var body problem.Problem
+		err = json.Unmarshal(rec.Body.Bytes(), &body)
+		assert.NoError(t, err, "Expected no error unmarshalling response")
+		assert.Equal(t, "VALIDATION_ERROR", body.Code, "Expected error code to be VALIDATION_ERROR")
+		assert.Equal(t, "invalid cursor", body.Detail, "Expected the cursor error message")
 	})
 
 	t.Run("GetAll_Error", func(t *testing.T) {
@@ -63,7 +262,7 @@ func TestProductController_GetAll(t *testing.T) {
 		router := gin.Default()
 		router.GET("/products", productController.GetAll)
 
-		mockService.On("GetAll").Return([]response.ProductResponse{}, assert.AnError)
+		mockService.On("GetAll", request.ProductFilterRequest{}).Return([]response.ProductResponse{}, response.PaginationResponse{}, assert.AnError)
 
 		req, err := http.NewRequest(http.MethodGet, "/products", nil)
 		assert.NoError(t, err, "Expected no error creating request")
@@ -73,11 +272,11 @@ func TestProductController_GetAll(t *testing.T) {
 
 		assert.Equal(t, http.StatusInternalServerError, rec.Code, "Expected status code 500")
 
-		var response response.BaseResponse
-		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		var body problem.Problem
+		err = json.Unmarshal(rec.Body.Bytes(), &body)
 		assert.NoError(t, err, "Expected no error unmarshalling response")
-		assert.Equal(t, 500, response.Code, "Response code should be 500")
-		assert.Equal(t, "Internal Server Error", response.Status, "Response status should be Internal Server Error")
+		assert.Equal(t, 500, body.Status, "Response code should be 500")
+		assert.Equal(t, "Internal Server Error", body.Title, "Response status should be Internal Server Error")
 
 		mockService.AssertExpectations(t)
 	})
@@ -133,11 +332,126 @@ func TestProductController_GetByID(t *testing.T) {
 
 		assert.Equal(t, http.StatusInternalServerError, rec.Code, "Expected status code 500")
 
+		var body problem.Problem
+		err = json.Unmarshal(rec.Body.Bytes(), &body)
+		assert.NoError(t, err, "Expected no error unmarshalling response")
+		assert.Equal(t, 500, body.Status, "Response code should be 500")
+		assert.Equal(t, "Internal Server Error", body.Title, "Response status should be Internal Server Error")
+		assert.Equal(t, "INTERNAL_ERROR", body.Code, "Expected error code to be INTERNAL_ERROR")
+		assert.Equal(t, "Error getting product by ID", body.Detail, "Expected the error detail to be hidden")
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("GetByID_NotFound", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		mockService := new(mocks.MockProductService)
+		productController := NewProductControllerImpl(mockService)
+
+		router := gin.Default()
+		router.GET("/products/:productId", productController.GetByID)
+
+		mockService.On("GetByID", "missing-id").Return(response.ProductResponse{}, apperror.NotFound("product not found"))
+
+		req, err := http.NewRequest(http.MethodGet, "/products/missing-id", nil)
+		assert.NoError(t, err, "Expected no error creating request")
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code, "Expected status code 404")
+
+		var body problem.Problem
+		err = json.Unmarshal(rec.Body.Bytes(), &body)
+		assert.NoError(t, err, "Expected no error unmarshalling response")
+		assert.Equal(t, 404, body.Status, "Response code should be 404")
+		assert.Equal(t, "Not Found", body.Title, "Response status should be Not Found")
+		assert.Equal(t, "NOT_FOUND", body.Code, "Expected error code to be NOT_FOUND")
+		assert.Equal(t, "product not found", body.Detail, "Expected the not found message")
+
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestProductController_Search(t *testing.T) {
+	t.Run("Search_Success", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		mockService := new(mocks.MockProductService)
+		productController := NewProductControllerImpl(mockService)
+
+		// /search convive con /:productId como en el router
+		router := gin.Default()
+		router.GET("/products/search", productController.Search)
+		router.GET("/products/:productId", productController.GetByID)
+
+		mockService.On("Search", request.SearchRequest{Query: "cafe nescafe", Limit: 5}).Return([]response.ProductResponse{
+			{
+				ProductID: "test-id",
+				Name:      "Café Nescafé",
+			},
+		}, nil)
+
+		req, err := http.NewRequest(http.MethodGet, "/products/search?q=cafe+nescafe&limit=5", nil)
+		assert.NoError(t, err, "Expected no error creating request")
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code, "Expected status code 200")
+
 		var response response.BaseResponse
 		err = json.Unmarshal(rec.Body.Bytes(), &response)
 		assert.NoError(t, err, "Expected no error unmarshalling response")
-		assert.Equal(t, 500, response.Code, "Response code should be 500")
-		assert.Equal(t, "Internal Server Error", response.Status, "Response status should be Internal Server Error")
+		assert.Equal(t, 200, response.Code, "Response code should be 200")
+		assert.Equal(t, "Success searching products", response.Message, "Expected the search message")
+		assert.Len(t, response.Data, 1, "Expected one product")
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Search_InvalidQuery", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		mockService := new(mocks.MockProductService)
+		productController := NewProductControllerImpl(mockService)
+
+		router := gin.Default()
+		router.GET("/products/search", productController.Search)
+
+		for _, query := range []string{"", "?q=cafe&limit=abc", "?q=cafe&limit=51"} {
+			req, err := http.NewRequest(http.MethodGet, "/products/search"+query, nil)
+			assert.NoError(t, err, "Expected no error creating request")
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code, "Expected status code 400 for %q", query)
+		}
+
+		mockService.AssertNotCalled(t, "Search", mock.Anything)
+	})
+
+	t.Run("Search_Error", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		mockService := new(mocks.MockProductService)
+		productController := NewProductControllerImpl(mockService)
+
+		router := gin.Default()
+		router.GET("/products/search", productController.Search)
+
+		mockService.On("Search", request.SearchRequest{Query: "cafe"}).Return([]response.ProductResponse{}, assert.AnError)
+
+		req, err := http.NewRequest(http.MethodGet, "/products/search?q=cafe", nil)
+		assert.NoError(t, err, "Expected no error creating request")
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code, "Expected status code 500")
+
+		var body problem.Problem
+		err = json.Unmarshal(rec.Body.Bytes(), &body)
+		assert.NoError(t, err, "Expected no error unmarshalling response")
+		assert.Equal(t, 500, body.Status, "Response code should be 500")
 
 		mockService.AssertExpectations(t)
 	})
@@ -154,7 +468,7 @@ func TestProductController_UpdateData(t *testing.T) {
 
 		mockService.On("UpdateData", request.UpdateDataRequest{
 			UpdateData: true,
-		}).Return(true, nil)
+		}, "").Return("run-id", nil)
 
 		reqBody, err := json.Marshal(request.UpdateDataRequest{
 			UpdateData: true,
@@ -174,6 +488,44 @@ func TestProductController_UpdateData(t *testing.T) {
 		assert.NoError(t, err, "Expected no error unmarshalling response")
 		assert.Equal(t, 200, response.Code, "Response code should be 200")
 		assert.Equal(t, "OK", response.Status, "Response status should be Success")
+		assert.Equal(t, map[string]interface{}{"run_id": "run-id"}, response.Data, "Response data should contain the run ID")
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("UpdateData_TriggeredByAuthorizerUser", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		mockService := new(mocks.MockProductService)
+		productController := NewProductControllerImpl(mockService)
+
+		router := gin.Default()
+		router.POST("/products", productController.UpdateData)
+
+		mockService.On("UpdateData", request.UpdateDataRequest{
+			UpdateData: true,
+		}, "user-id").Return("run-id", nil)
+
+		reqBody, err := json.Marshal(request.UpdateDataRequest{
+			UpdateData: true,
+		})
+		assert.NoError(t, err, "Expected no error marshalling request")
+
+		accessor := core.RequestAccessor{}
+		req, err := accessor.EventToRequestWithContext(context.Background(), events.APIGatewayProxyRequest{
+			HTTPMethod: http.MethodPost,
+			Path:       "/products",
+			Body:       string(reqBody),
+			RequestContext: events.APIGatewayProxyRequestContext{
+				Authorizer: map[string]interface{}{"user_id": "user-id"},
+			},
+		})
+		assert.NoError(t, err, "Expected no error creating request")
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code, "Expected status code 200")
+		mockService.AssertExpectations(t)
 	})
 
 	t.Run("UpdateData_Failure", func(t *testing.T) {
@@ -186,7 +538,7 @@ func TestProductController_UpdateData(t *testing.T) {
 
 		mockService.On("UpdateData", request.UpdateDataRequest{
 			UpdateData: false,
-		}).Return(false, nil)
+		}, "").Return("", nil)
 
 		reqBody, err := json.Marshal(request.UpdateDataRequest{
 			UpdateData: false,
@@ -201,12 +553,12 @@ func TestProductController_UpdateData(t *testing.T) {
 
 		assert.Equal(t, http.StatusBadRequest, rec.Code, "Expected status code 400")
 
-		var response response.BaseResponse
-		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		var body problem.Problem
+		err = json.Unmarshal(rec.Body.Bytes(), &body)
 		assert.NoError(t, err, "Expected no error unmarshalling response")
-		assert.Equal(t, 400, response.Code, "Response code should be 400")
-		assert.Equal(t, "Bad Request", response.Status, "Response status should be Bad Request")
-		assert.Equal(t, "Error updating data", response.Message, "Response message should be UpdateData is required")
+		assert.Equal(t, 400, body.Status, "Response code should be 400")
+		assert.Equal(t, "Bad Request", body.Title, "Response status should be Bad Request")
+		assert.Equal(t, "Error updating data", body.Detail, "Response message should be UpdateData is required")
 
 		mockService.AssertExpectations(t)
 	})
@@ -221,7 +573,7 @@ func TestProductController_UpdateData(t *testing.T) {
 
 		mockService.On("UpdateData", request.UpdateDataRequest{
 			UpdateData: true,
-		}).Return(false, assert.AnError)
+		}, "").Return("", assert.AnError)
 
 		reqBody, err := json.Marshal(request.UpdateDataRequest{
 			UpdateData: true,
@@ -236,11 +588,97 @@ func TestProductController_UpdateData(t *testing.T) {
 
 		assert.Equal(t, http.StatusInternalServerError, rec.Code, "Expected status code 500")
 
+		var body problem.Problem
+		err = json.Unmarshal(rec.Body.Bytes(), &body)
+		assert.NoError(t, err, "Expected no error unmarshalling response")
+		assert.Equal(t, 500, body.Status, "Response code should be 500")
+		assert.Equal(t, "Internal Server Error", body.Title, "Response status should be Internal Server Error")
+
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestProductController_GetPriceHistory(t *testing.T) {
+	t.Run("GetPriceHistory_Success", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		mockService := new(mocks.MockProductService)
+		productController := NewProductControllerImpl(mockService)
+
+		router := gin.Default()
+		router.GET("/products/:productId/history", productController.GetPriceHistory)
+
+		mockService.On("GetPriceHistory", "test-id", request.PriceHistoryRequest{
+			From: "2024-08-01",
+			To:   "2024-08-31",
+		}).Return([]response.PriceHistoryResponse{
+			{Date: "2024-08-01", OriginalPrice: 100, DiscountedPrice: 0},
+		}, nil)
+
+		req, err := http.NewRequest(http.MethodGet, "/products/test-id/history?from=2024-08-01&to=2024-08-31", nil)
+		assert.NoError(t, err, "Expected no error creating request")
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code, "Expected status code 200")
+
 		var response response.BaseResponse
 		err = json.Unmarshal(rec.Body.Bytes(), &response)
 		assert.NoError(t, err, "Expected no error unmarshalling response")
-		assert.Equal(t, 500, response.Code, "Response code should be 500")
-		assert.Equal(t, "Internal Server Error", response.Status, "Response status should be Internal Server Error")
+		assert.Equal(t, 200, response.Code, "Response code should be 200")
+		assert.Equal(t, "OK", response.Status, "Response status should be Success")
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("GetPriceHistory_InvalidDate", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		mockService := new(mocks.MockProductService)
+		productController := NewProductControllerImpl(mockService)
+
+		router := gin.Default()
+		router.GET("/products/:productId/history", productController.GetPriceHistory)
+
+		req, err := http.NewRequest(http.MethodGet, "/products/test-id/history?from=01-08-2024", nil)
+		assert.NoError(t, err, "Expected no error creating request")
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code, "Expected status code 400")
+
+		var body problem.Problem
+		err = json.Unmarshal(rec.Body.Bytes(), &body)
+		assert.NoError(t, err, "Expected no error unmarshalling response")
+		assert.Equal(t, 400, body.Status, "Response code should be 400")
+		assert.Equal(t, "Bad Request", body.Title, "Response status should be Bad Request")
+
+		mockService.AssertNotCalled(t, "GetPriceHistory", mock.Anything, mock.Anything)
+	})
+
+	t.Run("GetPriceHistory_Error", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		mockService := new(mocks.MockProductService)
+		productController := NewProductControllerImpl(mockService)
+
+		router := gin.Default()
+		router.GET("/products/:productId/history", productController.GetPriceHistory)
+
+		mockService.On("GetPriceHistory", "test-id", request.PriceHistoryRequest{}).Return([]response.PriceHistoryResponse{}, assert.AnError)
+
+		req, err := http.NewRequest(http.MethodGet, "/products/test-id/history", nil)
+		assert.NoError(t, err, "Expected no error creating request")
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code, "Expected status code 500")
+
+		var body problem.Problem
+		err = json.Unmarshal(rec.Body.Bytes(), &body)
+		assert.NoError(t, err, "Expected no error unmarshalling response")
+		assert.Equal(t, 500, body.Status, "Response code should be 500")
+		assert.Equal(t, "Internal Server Error", body.Title, "Response status should be Internal Server Error")
 
 		mockService.AssertExpectations(t)
 	})
@@ -0,0 +1,8 @@
+package controller
+
+import "github.com/gin-gonic/gin"
+
+type ScrapeRunController interface {
+	GetAll(ctx *gin.Context)
+	GetByID(ctx *gin.Context)
+}
@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"github.com/dieg0code/serverles-api-scraper/api/service"
+	"github.com/dieg0code/shared/json/response"
+	"github.com/dieg0code/shared/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type ScrapeRunControllerImpl struct {
+	ScrapeRunService service.ScrapeRunService
+}
+
+// GetAll implements ScrapeRunController.
+func (s *ScrapeRunControllerImpl) GetAll(ctx *gin.Context) {
+	runsResponse, err := s.ScrapeRunService.GetAll(ctx.Request.Context())
+	if err != nil {
+		logrus.WithError(err).Error("[ScrapeRunControllerImpl.GetAll] Error getting all scrape runs")
+		problem.Render(ctx, err, "Error getting all scrape runs")
+		return
+	}
+
+	successResponse := response.BaseResponse{
+		Code:    200,
+		Status:  "OK",
+		Message: "Success getting all scrape runs",
+		Data:    runsResponse,
+	}
+
+	ctx.JSON(200, successResponse)
+}
+
+// GetByID implements ScrapeRunController.
+func (s *ScrapeRunControllerImpl) GetByID(ctx *gin.Context) {
+	runId := ctx.Param("runId")
+	if runId == "" {
+		problem.BadRequest(ctx, nil, "Run ID is required")
+		return
+	}
+
+	runResponse, err := s.ScrapeRunService.GetByID(ctx.Request.Context(), runId)
+	if err != nil {
+		logrus.WithError(err).Error("[ScrapeRunControllerImpl.GetByID] Error getting scrape run by ID")
+		problem.Render(ctx, err, "Error getting scrape run by ID")
+		return
+	}
+
+	successResponse := response.BaseResponse{
+		Code:    200,
+		Status:  "OK",
+		Message: "Success getting scrape run by ID",
+		Data:    runResponse,
+	}
+
+	ctx.JSON(200, successResponse)
+}
+
+func NewScrapeRunControllerImpl(scrapeRunService service.ScrapeRunService) ScrapeRunController {
+	return &ScrapeRunControllerImpl{ScrapeRunService: scrapeRunService}
+}
@@ -0,0 +1,166 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dieg0code/shared/apperror"
+	"github.com/dieg0code/shared/json/response"
+	"github.com/dieg0code/shared/mocks"
+	"github.com/dieg0code/shared/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScrapeRunController_GetAll(t *testing.T) {
+	t.Run("GetAll_Success", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		mockService := new(mocks.MockScrapeRunService)
+		scrapeRunController := NewScrapeRunControllerImpl(mockService)
+
+		router := gin.Default()
+		router.GET("/scrapes", scrapeRunController.GetAll)
+
+		mockService.On("GetAll").Return([]response.ScrapeRunResponse{
+			{
+				RunID:      "run-id",
+				StartedAt:  "2024-08-20T10:00:00Z",
+				Status:     "succeeded",
+				Categories: []response.CategoryRunResponse{{Category: "despensa", Products: 120}},
+			},
+		}, nil)
+
+		req, err := http.NewRequest(http.MethodGet, "/scrapes", nil)
+		assert.NoError(t, err, "Expected no error creating request")
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code, "Expected status code 200")
+
+		var response response.BaseResponse
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		assert.NoError(t, err, "Expected no error unmarshalling response")
+		assert.Equal(t, 200, response.Code, "Response code should be 200")
+		assert.Equal(t, "OK", response.Status, "Response status should be OK")
+		assert.Len(t, response.Data, 1, "Response data should contain one run")
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("GetAll_Error", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		mockService := new(mocks.MockScrapeRunService)
+		scrapeRunController := NewScrapeRunControllerImpl(mockService)
+
+		router := gin.Default()
+		router.GET("/scrapes", scrapeRunController.GetAll)
+
+		mockService.On("GetAll").Return([]response.ScrapeRunResponse{}, assert.AnError)
+
+		req, err := http.NewRequest(http.MethodGet, "/scrapes", nil)
+		assert.NoError(t, err, "Expected no error creating request")
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code, "Expected status code 500")
+
+		var body problem.Problem
+		err = json.Unmarshal(rec.Body.Bytes(), &body)
+		assert.NoError(t, err, "Expected no error unmarshalling response")
+		assert.Equal(t, 500, body.Status, "Response code should be 500")
+		assert.Equal(t, "Internal Server Error", body.Title, "Response status should be Internal Server Error")
+
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestScrapeRunController_GetByID(t *testing.T) {
+	t.Run("GetByID_Success", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		mockService := new(mocks.MockScrapeRunService)
+		scrapeRunController := NewScrapeRunControllerImpl(mockService)
+
+		router := gin.Default()
+		router.GET("/scrapes/:runId", scrapeRunController.GetByID)
+
+		mockService.On("GetByID", "run-id").Return(response.ScrapeRunResponse{
+			RunID:      "run-id",
+			StartedAt:  "2024-08-20T10:00:00Z",
+			Status:     "running",
+			Categories: []response.CategoryRunResponse{},
+		}, nil)
+
+		req, err := http.NewRequest(http.MethodGet, "/scrapes/run-id", nil)
+		assert.NoError(t, err, "Expected no error creating request")
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code, "Expected status code 200")
+
+		var response response.BaseResponse
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		assert.NoError(t, err, "Expected no error unmarshalling response")
+		assert.Equal(t, 200, response.Code, "Response code should be 200")
+		assert.Equal(t, "OK", response.Status, "Response status should be OK")
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("GetByID_Error", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		mockService := new(mocks.MockScrapeRunService)
+		scrapeRunController := NewScrapeRunControllerImpl(mockService)
+
+		router := gin.Default()
+		router.GET("/scrapes/:runId", scrapeRunController.GetByID)
+
+		mockService.On("GetByID", "run-id").Return(response.ScrapeRunResponse{}, assert.AnError)
+
+		req, err := http.NewRequest(http.MethodGet, "/scrapes/run-id", nil)
+		assert.NoError(t, err, "Expected no error creating request")
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code, "Expected status code 500")
+
+		var body problem.Problem
+		err = json.Unmarshal(rec.Body.Bytes(), &body)
+		assert.NoError(t, err, "Expected no error unmarshalling response")
+		assert.Equal(t, 500, body.Status, "Response code should be 500")
+		assert.Equal(t, "Internal Server Error", body.Title, "Response status should be Internal Server Error")
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("GetByID_NotFound", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		mockService := new(mocks.MockScrapeRunService)
+		scrapeRunController := NewScrapeRunControllerImpl(mockService)
+
+		router := gin.Default()
+		router.GET("/scrapes/:runId", scrapeRunController.GetByID)
+
+		mockService.On("GetByID", "missing-id").Return(response.ScrapeRunResponse{}, apperror.NotFound("scrape run not found"))
+
+		req, err := http.NewRequest(http.MethodGet, "/scrapes/missing-id", nil)
+		assert.NoError(t, err, "Expected no error creating request")
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code, "Expected status code 404")
+
+		var body problem.Problem
+		err = json.Unmarshal(rec.Body.Bytes(), &body)
+		assert.NoError(t, err, "Expected no error unmarshalling response")
+		assert.Equal(t, "NOT_FOUND", body.Code, "Expected error code to be NOT_FOUND")
+
+		mockService.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,6 @@
+package request
+
+type PriceHistoryRequest struct {
+	From string `form:"from" binding:"omitempty,datetime=2006-01-02"`
+	To   string `form:"to" binding:"omitempty,datetime=2006-01-02"`
+}
@@ -0,0 +1,14 @@
+package request
+
+type ProductFilterRequest struct {
+	Store      string `form:"store"`
+	OnPromo    bool   `form:"on_promo"`
+	Category   string `form:"category"`
+	Discounted bool   `form:"discounted"`
+	MinPrice   int    `form:"min_price" binding:"omitempty,min=0"`
+	MaxPrice   int    `form:"max_price" binding:"omitempty,min=0,gtefield=MinPrice"`
+	Sort       string `form:"sort" binding:"omitempty,oneof=name price discount"`
+	Order      string `form:"order" binding:"omitempty,oneof=asc desc"`
+	PageSize   int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Cursor     string `form:"cursor"`
+}
@@ -0,0 +1,6 @@
+package request
+
+type SearchRequest struct {
+	Query string `form:"q" binding:"required"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=50"`
+}
@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/dieg0code/shared/models"
+)
+
+type CategoryRepository interface {
+	GetAll(ctx context.Context) ([]models.Category, error)
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/dieg0code/shared/apperror"
+	"github.com/dieg0code/shared/db"
+	"github.com/dieg0code/shared/models"
+	"github.com/sirupsen/logrus"
+)
+
+type CategoryRepositoryImpl struct {
+	db        dynamodbiface.DynamoDBAPI
+	tableName string
+}
+
+// GetAll implements CategoryRepository.
+func (c *CategoryRepositoryImpl) GetAll(ctx context.Context) ([]models.Category, error) {
+	input := &dynamodb.ScanInput{
+		TableName: &c.tableName,
+	}
+
+	items, err := db.ScanAll(ctx, c.db, input)
+	if err != nil {
+		logrus.WithError(err).Error("[CategoryRepositoryImpl.GetAll] error getting categories")
+		return nil, apperror.Internal("error getting categories")
+	}
+
+	var categories []models.Category
+	err = dynamodbattribute.UnmarshalListOfMaps(items, &categories)
+	if err != nil {
+		logrus.WithError(err).Error("[CategoryRepositoryImpl.GetAll] error unmarshalling categories")
+		return nil, apperror.Internal("error getting categories")
+	}
+
+	return categories, nil
+}
+
+func NewCategoryRepositoryImpl(db dynamodbiface.DynamoDBAPI, tableName string) CategoryRepository {
+	return &CategoryRepositoryImpl{
+		db:        db,
+		tableName: tableName,
+	}
+}
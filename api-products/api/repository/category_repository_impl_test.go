@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/dieg0code/shared/mocks"
+	"github.com/dieg0code/shared/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCategoryRepositoryImpl_GetAll(t *testing.T) {
+	t.Run("GetAll_Success", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewCategoryRepositoryImpl(mockDB, "test-table")
+
+		category := models.Category{
+			Store:        "cugat.cl",
+			Slug:         "despensa",
+			Name:         "Despensa",
+			LastSeen:     "2024-08-20T10:00:00Z",
+			ProductCount: 42,
+			LastUpdated:  "2024-08-20T10:05:00Z",
+		}
+		item, err := dynamodbattribute.MarshalMap(category)
+		assert.NoError(t, err, "Expected no error marshalling map")
+
+		mockDB.On("Scan", mock.Anything).Return(&dynamodb.ScanOutput{
+			Items: []map[string]*dynamodb.AttributeValue{item},
+		}, nil)
+
+		categories, err := repo.GetAll(context.Background())
+
+		assert.NoError(t, err, "Expected no error, GetAll() returned an error")
+		assert.Equal(t, []models.Category{category}, categories, "Expected categories to match")
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("GetAll_Error", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewCategoryRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("Scan", mock.Anything).Return(&dynamodb.ScanOutput{}, errors.New("error scanning"))
+
+		categories, err := repo.GetAll(context.Background())
+
+		assert.Error(t, err, "Expected an error, GetAll() did not return an error")
+		assert.Nil(t, categories, "Expected categories to be nil")
+		mockDB.AssertExpectations(t)
+	})
+}
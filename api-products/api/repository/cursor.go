@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// cursor es el contenido de los cursores del listado: el LastEvaluatedKey del
+// indice y el orden con que se obtuvo, para rechazar cursores de otro orden
+type cursor struct {
+	SortBy string                 `json:"sort"`
+	Key    map[string]interface{} `json:"key"`
+}
+
+// encodeCursor convierte un LastEvaluatedKey en un cursor opaco
+func encodeCursor(sortBy string, key map[string]*dynamodb.AttributeValue) (string, error) {
+	if len(key) == 0 {
+		return "", nil
+	}
+
+	var values map[string]interface{}
+	err := dynamodbattribute.UnmarshalMap(key, &values)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(cursor{SortBy: sortBy, Key: values})
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor devuelve el ExclusiveStartKey de un cursor. El cursor tiene que
+// ser del mismo orden y de la misma particion, y traer exactamente las claves
+// de index con sus tipos.
+func decodeCursor(value string, sortBy string, index listingIndex, partition string) (map[string]*dynamodb.AttributeValue, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var decoded cursor
+	err = json.Unmarshal(data, &decoded)
+	if err != nil || decoded.SortBy != sortBy || len(decoded.Key) != 3 {
+		return nil, ErrInvalidCursor
+	}
+
+	productID, productIDOk := decoded.Key["ProductID"].(string)
+	hashKey, hashKeyOk := decoded.Key[index.hashKey].(string)
+	if !productIDOk || !hashKeyOk || productID == "" || hashKey != partition {
+		return nil, ErrInvalidCursor
+	}
+
+	switch decoded.Key[index.sortKey].(type) {
+	case float64:
+		if !index.numeric {
+			return nil, ErrInvalidCursor
+		}
+	case string:
+		if index.numeric {
+			return nil, ErrInvalidCursor
+		}
+	default:
+		return nil, ErrInvalidCursor
+	}
+
+	startKey, err := dynamodbattribute.MarshalMap(decoded.Key)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return startKey, nil
+}
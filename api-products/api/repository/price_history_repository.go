@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/dieg0code/shared/models"
+)
+
+type PriceHistoryRepository interface {
+	GetByProductID(ctx context.Context, productID string, from string, to string) ([]models.PriceObservation, error)
+}
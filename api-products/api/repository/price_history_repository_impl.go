@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/dieg0code/shared/apperror"
+	"github.com/dieg0code/shared/models"
+	"github.com/sirupsen/logrus"
+)
+
+type PriceHistoryRepositoryImpl struct {
+	db        dynamodbiface.DynamoDBAPI
+	tableName string
+}
+
+// GetByProductID implements PriceHistoryRepository.
+func (p *PriceHistoryRepositoryImpl) GetByProductID(ctx context.Context, productID string, from string, to string) ([]models.PriceObservation, error) {
+	// "Date" es palabra reservada en DynamoDB, por eso se usa #date
+	keyCondition := "ProductID = :productId"
+	values := map[string]*dynamodb.AttributeValue{
+		":productId": {
+			S: aws.String(productID),
+		},
+	}
+
+	switch {
+	case from != "" && to != "":
+		keyCondition += " AND #date BETWEEN :from AND :to"
+		values[":from"] = &dynamodb.AttributeValue{S: aws.String(from)}
+		values[":to"] = &dynamodb.AttributeValue{S: aws.String(to)}
+	case from != "":
+		keyCondition += " AND #date >= :from"
+		values[":from"] = &dynamodb.AttributeValue{S: aws.String(from)}
+	case to != "":
+		keyCondition += " AND #date <= :to"
+		values[":to"] = &dynamodb.AttributeValue{S: aws.String(to)}
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:                 &p.tableName,
+		KeyConditionExpression:    aws.String(keyCondition),
+		ExpressionAttributeValues: values,
+		ScanIndexForward:          aws.Bool(true),
+	}
+	if from != "" || to != "" {
+		input.ExpressionAttributeNames = map[string]*string{
+			"#date": aws.String("Date"),
+		}
+	}
+
+	var items []map[string]*dynamodb.AttributeValue
+	for {
+		result, err := p.db.QueryWithContext(ctx, input)
+		if err != nil {
+			logrus.WithError(err).Error("[PriceHistoryRepositoryImpl.GetByProductID] error getting price history")
+			return nil, apperror.Internal("error getting price history")
+		}
+
+		items = append(items, result.Items...)
+		if len(result.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+
+	var history []models.PriceObservation
+	err := dynamodbattribute.UnmarshalListOfMaps(items, &history)
+	if err != nil {
+		logrus.WithError(err).Error("[PriceHistoryRepositoryImpl.GetByProductID] error unmarshalling price history")
+		return nil, apperror.Internal("error getting price history")
+	}
+
+	return history, nil
+}
+
+func NewPriceHistoryRepositoryImpl(db dynamodbiface.DynamoDBAPI, tableName string) PriceHistoryRepository {
+	return &PriceHistoryRepositoryImpl{
+		db:        db,
+		tableName: tableName,
+	}
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/dieg0code/shared/mocks"
+	"github.com/dieg0code/shared/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPriceHistoryRepositoryImpl_GetByProductID(t *testing.T) {
+	t.Run("GetByProductID_Success", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewPriceHistoryRepositoryImpl(mockDB, "test-table")
+
+		expectedHistory := []models.PriceObservation{
+			{ProductID: "test-id", Date: "2024-08-01", OriginalPrice: 100, DiscountedPrice: 0},
+			{ProductID: "test-id", Date: "2024-08-02", OriginalPrice: 110, DiscountedPrice: 90},
+		}
+
+		var items []map[string]*dynamodb.AttributeValue
+		for _, observation := range expectedHistory {
+			item, err := dynamodbattribute.MarshalMap(observation)
+			assert.NoError(t, err, "Expected no error marshalling map")
+			items = append(items, item)
+		}
+
+		mockDB.On("Query", mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+			return *input.KeyConditionExpression == "ProductID = :productId" &&
+				input.ExpressionAttributeNames == nil
+		})).Return(&dynamodb.QueryOutput{Items: items}, nil)
+
+		history, err := repo.GetByProductID(context.Background(), "test-id", "", "")
+
+		assert.NoError(t, err, "Expected no error, GetByProductID() returned an error")
+		assert.Equal(t, expectedHistory, history, "Expected history to be equal to the expected history")
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("GetByProductID_DateRange", func(t *testing.T) {
+		tests := []struct {
+			name         string
+			from         string
+			to           string
+			keyCondition string
+		}{
+			{"Between", "2024-08-01", "2024-08-31", "ProductID = :productId AND #date BETWEEN :from AND :to"},
+			{"From", "2024-08-01", "", "ProductID = :productId AND #date >= :from"},
+			{"To", "", "2024-08-31", "ProductID = :productId AND #date <= :to"},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				mockDB := new(mocks.MockDynamoDB)
+				repo := NewPriceHistoryRepositoryImpl(mockDB, "test-table")
+
+				mockDB.On("Query", mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+					return *input.KeyConditionExpression == test.keyCondition &&
+						*input.ExpressionAttributeNames["#date"] == "Date"
+				})).Return(&dynamodb.QueryOutput{}, nil)
+
+				_, err := repo.GetByProductID(context.Background(), "test-id", test.from, test.to)
+
+				assert.NoError(t, err, "Expected no error, GetByProductID() returned an error")
+				mockDB.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("GetByProductID_FollowsPages", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewPriceHistoryRepositoryImpl(mockDB, "test-table")
+
+		firstObservation := models.PriceObservation{ProductID: "test-id", Date: "2024-08-01", OriginalPrice: 100}
+		secondObservation := models.PriceObservation{ProductID: "test-id", Date: "2024-08-02", OriginalPrice: 110}
+		first, err := dynamodbattribute.MarshalMap(firstObservation)
+		assert.NoError(t, err, "Expected no error marshalling map")
+		second, err := dynamodbattribute.MarshalMap(secondObservation)
+		assert.NoError(t, err, "Expected no error marshalling map")
+
+		mockDB.On("Query", mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+			return input.ExclusiveStartKey == nil
+		})).Return(&dynamodb.QueryOutput{
+			Items:            []map[string]*dynamodb.AttributeValue{first},
+			LastEvaluatedKey: first,
+		}, nil).Once()
+		mockDB.On("Query", mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+			return input.ExclusiveStartKey != nil
+		})).Return(&dynamodb.QueryOutput{
+			Items: []map[string]*dynamodb.AttributeValue{second},
+		}, nil).Once()
+
+		history, err := repo.GetByProductID(context.Background(), "test-id", "", "")
+
+		assert.NoError(t, err, "Expected no error, GetByProductID() returned an error")
+		assert.Equal(t, []models.PriceObservation{firstObservation, secondObservation}, history, "Expected the observations of both pages")
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("GetByProductID_Error", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewPriceHistoryRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("Query", mock.Anything).Return(&dynamodb.QueryOutput{}, errors.New("error querying"))
+
+		history, err := repo.GetByProductID(context.Background(), "test-id", "", "")
+
+		assert.Error(t, err, "Expected an error, GetByProductID() did not return an error")
+		assert.Nil(t, history, "Expected history to be nil")
+		mockDB.AssertExpectations(t)
+	})
+}
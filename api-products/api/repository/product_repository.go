@@ -1,8 +1,20 @@
 package repository
 
-import "github.com/dieg0code/shared/models"
+import (
+	"context"
+
+	"github.com/dieg0code/shared/apperror"
+	"github.com/dieg0code/shared/models"
+)
+
+// ErrInvalidCursor es el error de un cursor mal formado o de otro orden
+var ErrInvalidCursor = apperror.Validation("invalid cursor")
 
 type ProductRepository interface {
-	GetAll() ([]models.Product, error)
-	GetByID(id string) (models.Product, error)
+	List(ctx context.Context, query models.ProductQuery) (models.ProductPage, error)
+	// ListByCategory lista los productos activos de una categoria con el
+	// orden de query.SortBy; query.Category no se usa
+	ListByCategory(ctx context.Context, slug string, query models.ProductQuery) (models.ProductPage, error)
+	GetByID(ctx context.Context, id string) (models.Product, error)
+	GetByIDs(ctx context.Context, ids []string) ([]models.Product, error)
 }
@@ -1,12 +1,16 @@
 package repository
 
 import (
+	"context"
 	"errors"
+	"strconv"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/dieg0code/shared/apperror"
 	"github.com/dieg0code/shared/models"
 	"github.com/sirupsen/logrus"
 )
@@ -16,30 +20,191 @@ type ProductRepositoryImpl struct {
 	tableName string
 }
 
-// GetAll implements ProductRepository.
-func (p *ProductRepositoryImpl) GetAll() ([]models.Product, error) {
-	input := &dynamodb.ScanInput{
-		TableName: &p.tableName,
+// listingIndex es un GSI de listado con hashKey como partition key y sortKey
+// como sort key. Solo los productos activos tienen las partition keys.
+type listingIndex struct {
+	name    string
+	hashKey string
+	sortKey string
+	numeric bool
+}
+
+var listingIndexes = map[string]listingIndex{
+	models.SortByName:     {name: "ListingNameIndex", hashKey: "Listing", sortKey: "SortName"},
+	models.SortByPrice:    {name: "ListingPriceIndex", hashKey: "Listing", sortKey: "SortPrice", numeric: true},
+	models.SortByDiscount: {name: "ListingDiscountIndex", hashKey: "Listing", sortKey: "DiscountPercent", numeric: true},
+}
+
+// categoryIndexes listan los productos activos de una categoria. La partition
+// key es el slug de la categoria.
+var categoryIndexes = map[string]listingIndex{
+	models.SortByName:     {name: "CategoryIndex", hashKey: "ListingCategory", sortKey: "SortName"},
+	models.SortByPrice:    {name: "CategoryPriceIndex", hashKey: "ListingCategory", sortKey: "SortPrice", numeric: true},
+	models.SortByDiscount: {name: "CategoryDiscountIndex", hashKey: "ListingCategory", sortKey: "DiscountPercent", numeric: true},
+}
+
+// categoryCursor es el orden de los cursores de categoryIndexes, distinto al
+// del listado general con el mismo orden
+func categoryCursor(sortBy string) string {
+	return "category-" + sortBy
+}
+
+// maxQueriesPerPage es el maximo de queries para llenar una pagina. Con
+// filtros muy selectivos la pagina puede quedar corta, pero con NextCursor.
+const maxQueriesPerPage = 5
+
+// List implements ProductRepository. Con query.Category se lista desde los
+// indices de categoria en vez de filtrar el listado general.
+func (p *ProductRepositoryImpl) List(ctx context.Context, query models.ProductQuery) (models.ProductPage, error) {
+	if query.Category != "" {
+		return p.ListByCategory(ctx, query.Category, query)
+	}
+
+	index, ok := listingIndexes[query.SortBy]
+	if !ok || query.Limit < 1 {
+		logrus.WithFields(logrus.Fields{
+			"sort":  query.SortBy,
+			"limit": query.Limit,
+		}).Error("[ProductRepositoryImpl.List] invalid query")
+		return models.ProductPage{}, apperror.Internal("error listing products")
+	}
+
+	page, err := p.queryIndex(ctx, index, models.ListingActive, query.SortBy, query)
+	if err != nil && !errors.Is(err, ErrInvalidCursor) {
+		logrus.WithError(err).Error("[ProductRepositoryImpl.List] error listing products")
+		return models.ProductPage{}, apperror.Internal("error listing products")
+	}
+
+	return page, err
+}
+
+// ListByCategory implements ProductRepository.
+func (p *ProductRepositoryImpl) ListByCategory(ctx context.Context, slug string, query models.ProductQuery) (models.ProductPage, error) {
+	index, ok := categoryIndexes[query.SortBy]
+	if !ok || slug == "" || query.Limit < 1 {
+		logrus.WithFields(logrus.Fields{
+			"category": slug,
+			"sort":     query.SortBy,
+			"limit":    query.Limit,
+		}).Error("[ProductRepositoryImpl.ListByCategory] invalid query")
+		return models.ProductPage{}, apperror.Internal("error listing products")
+	}
+
+	page, err := p.queryIndex(ctx, index, slug, categoryCursor(query.SortBy), query)
+	if err != nil && !errors.Is(err, ErrInvalidCursor) {
+		logrus.WithError(err).Error("[ProductRepositoryImpl.ListByCategory] error listing products")
+		return models.ProductPage{}, apperror.Internal("error listing products")
+	}
+
+	return page, err
+}
+
+// queryIndex devuelve una pagina de la particion partition de index aplicando
+// los filtros de query
+func (p *ProductRepositoryImpl) queryIndex(ctx context.Context, index listingIndex, partition string, cursorSort string, query models.ProductQuery) (models.ProductPage, error) {
+	startKey, err := decodeCursor(query.Cursor, cursorSort, index, partition)
+	if err != nil {
+		return models.ProductPage{}, err
+	}
+
+	var conditions []string
+	keyConditions := []string{index.hashKey + " = :partition"}
+	values := map[string]*dynamodb.AttributeValue{
+		":partition": {S: aws.String(partition)},
+	}
+
+	// El rango de precio es parte de la key condition cuando el indice esta
+	// ordenado por precio; con otro orden se filtra
+	priceConditions := &conditions
+	if index.sortKey == "SortPrice" {
+		priceConditions = &keyConditions
 	}
+	switch {
+	case query.MinPrice > 0 && query.MaxPrice > 0:
+		*priceConditions = append(*priceConditions, "SortPrice BETWEEN :minPrice AND :maxPrice")
+	case query.MinPrice > 0:
+		*priceConditions = append(*priceConditions, "SortPrice >= :minPrice")
+	case query.MaxPrice > 0:
+		*priceConditions = append(*priceConditions, "SortPrice <= :maxPrice")
+	}
+	if query.MinPrice > 0 {
+		values[":minPrice"] = &dynamodb.AttributeValue{N: aws.String(strconv.Itoa(query.MinPrice))}
+	}
+	if query.MaxPrice > 0 {
+		values[":maxPrice"] = &dynamodb.AttributeValue{N: aws.String(strconv.Itoa(query.MaxPrice))}
+	}
+
+	// "Store" es palabra reservada en DynamoDB, por eso se usa #store
+	names := map[string]*string{}
+	if query.Store != "" {
+		conditions = append(conditions, "#store = :store")
+		names["#store"] = aws.String("Store")
+		values[":store"] = &dynamodb.AttributeValue{S: aws.String(query.Store)}
+	}
+
+	// En promocion: con una promocion estructurada o con precio de oferta
+	if query.OnPromo {
+		conditions = append(conditions, "(attribute_exists(Promotion) OR DiscountedPrice > :zero)")
+		values[":zero"] = &dynamodb.AttributeValue{N: aws.String("0")}
+	}
+
+	if query.Discounted {
+		conditions = append(conditions, "DiscountPercent > :noDiscount")
+		values[":noDiscount"] = &dynamodb.AttributeValue{N: aws.String("0")}
+	}
+
+	input := dynamodb.QueryInput{
+		TableName:                 &p.tableName,
+		IndexName:                 aws.String(index.name),
+		KeyConditionExpression:    aws.String(strings.Join(keyConditions, " AND ")),
+		ExpressionAttributeValues: values,
+		ScanIndexForward:          aws.Bool(!query.Descending),
+	}
+	if len(conditions) > 0 {
+		input.FilterExpression = aws.String(strings.Join(conditions, " AND "))
+	}
+	if len(names) > 0 {
+		input.ExpressionAttributeNames = names
+	}
+
+	// Limit se aplica antes del filtro, asi que una pagina puede necesitar
+	// varias queries, hasta maxQueriesPerPage. Cada query evalua solo los
+	// items que faltan, por lo que LastEvaluatedKey nunca queda despues del
+	// ultimo producto de la pagina.
+	var items []map[string]*dynamodb.AttributeValue
+	for queries := 1; ; queries++ {
+		page := input
+		page.ExclusiveStartKey = startKey
+		page.Limit = aws.Int64(int64(query.Limit - len(items)))
+
+		result, err := p.db.QueryWithContext(ctx, &page)
+		if err != nil {
+			return models.ProductPage{}, err
+		}
 
-	result, err := p.db.Scan(input)
+		items = append(items, result.Items...)
+		startKey = result.LastEvaluatedKey
+		if len(items) >= query.Limit || len(startKey) == 0 || queries == maxQueriesPerPage {
+			break
+		}
+	}
+
+	products := []models.Product{}
+	err = dynamodbattribute.UnmarshalListOfMaps(items, &products)
 	if err != nil {
-		logrus.WithError(err).Error("[ProductRepositoryImpl.GetAll] error getting products")
-		return nil, errors.New("error getting products")
+		return models.ProductPage{}, err
 	}
 
-	var products []models.Product
-	err = dynamodbattribute.UnmarshalListOfMaps(result.Items, &products)
+	nextCursor, err := encodeCursor(cursorSort, startKey)
 	if err != nil {
-		logrus.WithError(err).Error("[ProductRepositoryImpl.GetAll] error unmarshalling products")
-		return nil, errors.New("error getting products")
+		return models.ProductPage{}, err
 	}
 
-	return products, nil
+	return models.ProductPage{Products: products, NextCursor: nextCursor}, nil
 }
 
 // GetByID implements ProductRepository.
-func (p *ProductRepositoryImpl) GetByID(id string) (models.Product, error) {
+func (p *ProductRepositoryImpl) GetByID(ctx context.Context, id string) (models.Product, error) {
 	input := &dynamodb.GetItemInput{
 		TableName: &p.tableName,
 		Key: map[string]*dynamodb.AttributeValue{
@@ -49,26 +214,79 @@ func (p *ProductRepositoryImpl) GetByID(id string) (models.Product, error) {
 		},
 	}
 
-	result, err := p.db.GetItem(input)
+	result, err := p.db.GetItemWithContext(ctx, input)
 	if err != nil {
 		logrus.WithError(err).Error("[ProductRepositoryImpl.GetByID] error getting product")
-		return models.Product{}, errors.New("error getting product")
+		return models.Product{}, apperror.Internal("error getting product")
 	}
 
 	if result.Item == nil {
-		return models.Product{}, errors.New("product not found")
+		return models.Product{}, apperror.NotFound("product not found")
 	}
 
 	var product models.Product
 	err = dynamodbattribute.UnmarshalMap(result.Item, &product)
 	if err != nil {
 		logrus.WithError(err).Error("[ProductRepositoryImpl.GetByID] error unmarshalling product")
-		return models.Product{}, errors.New("error getting product")
+		return models.Product{}, apperror.Internal("error getting product")
 	}
 
 	return product, nil
 }
 
+// maxBatchGetSize es el maximo de claves que acepta BatchGetItem por llamada
+const maxBatchGetSize = 100
+
+// maxBatchGetAttempts es la cantidad de llamadas que se hacen por batch para
+// leer las UnprocessedKeys
+const maxBatchGetAttempts = 3
+
+// GetByIDs implements ProductRepository. Los ids que no existen se omiten y el
+// orden del resultado no es el de ids.
+func (p *ProductRepositoryImpl) GetByIDs(ctx context.Context, ids []string) ([]models.Product, error) {
+	products := []models.Product{}
+	for start := 0; start < len(ids); start += maxBatchGetSize {
+		var keys []map[string]*dynamodb.AttributeValue
+		for _, id := range ids[start:min(start+maxBatchGetSize, len(ids))] {
+			keys = append(keys, map[string]*dynamodb.AttributeValue{
+				"ProductID": {S: aws.String(id)},
+			})
+		}
+
+		for attempt := 0; len(keys) > 0; attempt++ {
+			if attempt == maxBatchGetAttempts {
+				logrus.WithField("unprocessed", len(keys)).Error("[ProductRepositoryImpl.GetByIDs] unprocessed keys after retries")
+				return nil, apperror.Internal("error getting products")
+			}
+
+			result, err := p.db.BatchGetItemWithContext(ctx, &dynamodb.BatchGetItemInput{
+				RequestItems: map[string]*dynamodb.KeysAndAttributes{
+					p.tableName: {Keys: keys},
+				},
+			})
+			if err != nil {
+				logrus.WithError(err).Error("[ProductRepositoryImpl.GetByIDs] error getting products")
+				return nil, apperror.Internal("error getting products")
+			}
+
+			var found []models.Product
+			err = dynamodbattribute.UnmarshalListOfMaps(result.Responses[p.tableName], &found)
+			if err != nil {
+				logrus.WithError(err).Error("[ProductRepositoryImpl.GetByIDs] error unmarshalling products")
+				return nil, apperror.Internal("error getting products")
+			}
+			products = append(products, found...)
+
+			keys = nil
+			if unprocessed, ok := result.UnprocessedKeys[p.tableName]; ok {
+				keys = unprocessed.Keys
+			}
+		}
+	}
+
+	return products, nil
+}
+
 func NewProductRepositoryImpl(db dynamodbiface.DynamoDBAPI, tableName string) ProductRepository {
 	return &ProductRepositoryImpl{
 		db:        db,
@@ -1,10 +1,12 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/dieg0code/shared/apperror"
 	"github.com/dieg0code/shared/mocks"
 	"github.com/dieg0code/shared/models"
 	"github.com/stretchr/testify/assert"
@@ -35,7 +37,7 @@ func TestProductRepositoryImpl_GetByID(t *testing.T) {
 			},
 		}, nil)
 
-		product, err := repo.GetByID("test-id")
+		product, err := repo.GetByID(context.Background(), "test-id")
 
 		assert.NoError(t, err, "Expected no error, GetByID() returned an error")
 		assert.Equal(t, expectedProduct, product, "Expected product to be equal to the expected product")
@@ -48,7 +50,7 @@ func TestProductRepositoryImpl_GetByID(t *testing.T) {
 
 		mockDB.On("GetItem", mock.Anything).Return(&dynamodb.GetItemOutput{}, errors.New("error getting item"))
 
-		products, err := repo.GetByID("test-id")
+		products, err := repo.GetByID(context.Background(), "test-id")
 
 		assert.Error(t, err, "Expected an error, GetByID() did not return an error")
 		assert.Equal(t, models.Product{}, products, "Expected product to be empty")
@@ -62,19 +64,27 @@ func TestProductRepositoryImpl_GetByID(t *testing.T) {
 
 		mockDB.On("GetItem", mock.Anything).Return(&dynamodb.GetItemOutput{}, nil)
 
-		products, err := repo.GetByID("test-id")
+		products, err := repo.GetByID(context.Background(), "test-id")
 
 		assert.Error(t, err, "Expected an error, GetByID() did not return an error")
 		assert.Equal(t, models.Product{}, products, "Expected product to be empty")
 		assert.Equal(t, "product not found", err.Error(), "Expected error message to be 'product not found'")
+		assert.Equal(t, apperror.CodeNotFound, apperror.CodeOf(err), "Expected a not found error")
 
 		mockDB.AssertExpectations(t)
 	})
 }
 
 // NewProductRepositoryImpl creates a new ProductRepositoryImpl with the given DynamoDBAPI and table name
-func TestProductRepositoryImpl_GetAll(t *testing.T) {
-	t.Run("GetAll_Success", func(t *testing.T) {
+func TestProductRepositoryImpl_List(t *testing.T) {
+	// lastKey es un LastEvaluatedKey del indice por nombre
+	lastKey := map[string]*dynamodb.AttributeValue{
+		"ProductID": {S: stringPtr("test-id-2")},
+		"Listing":   {S: stringPtr(models.ListingActive)},
+		"SortName":  {S: stringPtr("test product 2")},
+	}
+
+	t.Run("List_Success", func(t *testing.T) {
 		mockDB := new(mocks.MockDynamoDB)
 		repo := NewProductRepositoryImpl(mockDB, "test-table")
 
@@ -95,7 +105,16 @@ func TestProductRepositoryImpl_GetAll(t *testing.T) {
 			},
 		}
 
-		mockDB.On("Scan", mock.Anything).Return(&dynamodb.ScanOutput{
+		mockDB.On("Query", mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+			return *input.IndexName == "ListingNameIndex" &&
+				*input.KeyConditionExpression == "Listing = :partition" &&
+				*input.ExpressionAttributeValues[":partition"].S == models.ListingActive &&
+				input.FilterExpression == nil &&
+				input.ExpressionAttributeNames == nil &&
+				*input.ScanIndexForward &&
+				*input.Limit == 2 &&
+				input.ExclusiveStartKey == nil
+		})).Return(&dynamodb.QueryOutput{
 			Items: []map[string]*dynamodb.AttributeValue{
 				{
 					"ProductID":       {S: &expectedProducts[0].ProductID},
@@ -112,31 +131,360 @@ func TestProductRepositoryImpl_GetAll(t *testing.T) {
 					"DiscountedPrice": {N: stringPtr("180")},
 				},
 			},
+			LastEvaluatedKey: lastKey,
 		}, nil)
 
-		products, err := repo.GetAll()
+		page, err := repo.List(context.Background(), models.ProductQuery{SortBy: models.SortByName, Limit: 2})
 
-		assert.NoError(t, err, "Expected no error, GetAll() returned an error")
-		assert.Equal(t, expectedProducts, products, "Expected products to be equal to the expected products")
+		assert.NoError(t, err, "Expected no error, List() returned an error")
+		assert.Equal(t, expectedProducts, page.Products, "Expected products to be equal to the expected products")
+		assert.NotEmpty(t, page.NextCursor, "Expected a cursor for the next page")
 		mockDB.AssertExpectations(t)
 	})
 
-	t.Run("GetAll_Error", func(t *testing.T) {
+	t.Run("List_LastPage", func(t *testing.T) {
 		mockDB := new(mocks.MockDynamoDB)
 		repo := NewProductRepositoryImpl(mockDB, "test-table")
 
-		mockDB.On("Scan", mock.Anything).Return(&dynamodb.ScanOutput{}, errors.New("error getting items"))
+		mockDB.On("Query", mock.Anything).Return(&dynamodb.QueryOutput{}, nil)
 
-		products, err := repo.GetAll()
+		page, err := repo.List(context.Background(), models.ProductQuery{SortBy: models.SortByName, Limit: 20})
 
-		assert.Error(t, err, "Expected an error, GetAll() did not return an error")
-		assert.Nil(t, products, "Expected products to be nil")
-		assert.Equal(t, "error getting products", err.Error(), "Expected error message to be 'error getting products'")
+		assert.NoError(t, err, "Expected no error, List() returned an error")
+		assert.Equal(t, []models.Product{}, page.Products, "Expected no products")
+		assert.Empty(t, page.NextCursor, "Expected no cursor on the last page")
+	})
+
+	t.Run("List_Cursor", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewProductRepositoryImpl(mockDB, "test-table")
+
+		cursor, err := encodeCursor(models.SortByName, lastKey)
+		assert.NoError(t, err, "Expected no error encoding the cursor")
+
+		mockDB.On("Query", mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+			return assert.ObjectsAreEqual(lastKey, input.ExclusiveStartKey)
+		})).Return(&dynamodb.QueryOutput{}, nil)
+
+		_, err = repo.List(context.Background(), models.ProductQuery{SortBy: models.SortByName, Limit: 20, Cursor: cursor})
+
+		assert.NoError(t, err, "Expected no error, List() returned an error")
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("List_InvalidCursor", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewProductRepositoryImpl(mockDB, "test-table")
+
+		// Un cursor del orden por nombre no sirve para el orden por precio
+		otherSort, err := encodeCursor(models.SortByName, lastKey)
+		assert.NoError(t, err, "Expected no error encoding the cursor")
+
+		for _, cursor := range []string{"not-a-cursor", "e30", otherSort} {
+			_, err := repo.List(context.Background(), models.ProductQuery{SortBy: models.SortByPrice, Limit: 20, Cursor: cursor})
+			assert.ErrorIs(t, err, ErrInvalidCursor, "Expected %q to be rejected", cursor)
+		}
+
+		mockDB.AssertNotCalled(t, "Query", mock.Anything)
+	})
+
+	t.Run("List_FillsPageAcrossQueries", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewProductRepositoryImpl(mockDB, "test-table")
+
+		// El filtro deja pasar un solo item de la primera query
+		mockDB.On("Query", mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+			return input.ExclusiveStartKey == nil && *input.Limit == 2
+		})).Return(&dynamodb.QueryOutput{
+			Items:            []map[string]*dynamodb.AttributeValue{{"ProductID": {S: stringPtr("test-id-1")}}},
+			LastEvaluatedKey: lastKey,
+		}, nil).Once()
+		mockDB.On("Query", mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+			return assert.ObjectsAreEqual(lastKey, input.ExclusiveStartKey) && *input.Limit == 1
+		})).Return(&dynamodb.QueryOutput{
+			Items: []map[string]*dynamodb.AttributeValue{{"ProductID": {S: stringPtr("test-id-3")}}},
+		}, nil).Once()
+
+		page, err := repo.List(context.Background(), models.ProductQuery{SortBy: models.SortByName, Store: "cugat.cl", Limit: 2})
+
+		assert.NoError(t, err, "Expected no error, List() returned an error")
+		assert.Equal(t, []models.Product{{ProductID: "test-id-1"}, {ProductID: "test-id-3"}}, page.Products, "Expected a full page")
+		assert.Empty(t, page.NextCursor, "Expected no cursor after the last item")
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("List_Filters", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewProductRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("Query", mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+			return *input.IndexName == "ListingDiscountIndex" &&
+				*input.KeyConditionExpression == "Listing = :partition" &&
+				*input.FilterExpression == "SortPrice BETWEEN :minPrice AND :maxPrice AND #store = :store AND (attribute_exists(Promotion) OR DiscountedPrice > :zero) AND DiscountPercent > :noDiscount" &&
+				*input.ExpressionAttributeNames["#store"] == "Store" &&
+				*input.ExpressionAttributeValues[":minPrice"].N == "1000" &&
+				*input.ExpressionAttributeValues[":maxPrice"].N == "5000" &&
+				*input.ExpressionAttributeValues[":store"].S == "cugat.cl" &&
+				!*input.ScanIndexForward
+		})).Return(&dynamodb.QueryOutput{}, nil)
+
+		_, err := repo.List(context.Background(), models.ProductQuery{
+			Store:      "cugat.cl",
+			OnPromo:    true,
+			Discounted: true,
+			MinPrice:   1000,
+			MaxPrice:   5000,
+			SortBy:     models.SortByDiscount,
+			Descending: true,
+			Limit:      20,
+		})
+
+		assert.NoError(t, err, "Expected no error, List() returned an error")
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("List_CategoryUsesCategoryIndex", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewProductRepositoryImpl(mockDB, "test-table")
+
+		// La categoria es la partition key, no un filtro sobre el listado
+		mockDB.On("Query", mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+			return *input.IndexName == "CategoryPriceIndex" &&
+				*input.KeyConditionExpression == "ListingCategory = :partition AND SortPrice <= :maxPrice" &&
+				*input.ExpressionAttributeValues[":partition"].S == "despensa" &&
+				input.FilterExpression == nil
+		})).Return(&dynamodb.QueryOutput{}, nil)
+
+		_, err := repo.List(context.Background(), models.ProductQuery{Category: "despensa", MaxPrice: 5000, SortBy: models.SortByPrice, Limit: 20})
+
+		assert.NoError(t, err, "Expected no error, List() returned an error")
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("List_CapsQueriesPerPage", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewProductRepositoryImpl(mockDB, "test-table")
+
+		// El filtro no deja pasar nada y el indice sigue teniendo items
+		listingKey := map[string]*dynamodb.AttributeValue{
+			"ProductID": {S: stringPtr("test-id-2")},
+			"Listing":   {S: stringPtr(models.ListingActive)},
+			"SortName":  {S: stringPtr("test product 2")},
+		}
+		mockDB.On("Query", mock.Anything).Return(&dynamodb.QueryOutput{
+			Items:            []map[string]*dynamodb.AttributeValue{},
+			LastEvaluatedKey: listingKey,
+		}, nil)
+
+		page, err := repo.List(context.Background(), models.ProductQuery{SortBy: models.SortByName, Store: "other.cl", Limit: 20})
+
+		assert.NoError(t, err, "Expected no error, List() returned an error")
+		assert.Empty(t, page.Products, "Expected a short page")
+		assert.NotEmpty(t, page.NextCursor, "Expected a cursor to keep reading")
+		mockDB.AssertNumberOfCalls(t, "Query", maxQueriesPerPage)
+	})
+
+	t.Run("List_PriceRangeOnSortKey", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewProductRepositoryImpl(mockDB, "test-table")
+
+		// Ordenando por precio el rango no necesita filtro
+		mockDB.On("Query", mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+			return *input.IndexName == "ListingPriceIndex" &&
+				*input.KeyConditionExpression == "Listing = :partition AND SortPrice >= :minPrice" &&
+				*input.ExpressionAttributeValues[":minPrice"].N == "1000" &&
+				input.FilterExpression == nil
+		})).Return(&dynamodb.QueryOutput{}, nil)
+
+		_, err := repo.List(context.Background(), models.ProductQuery{MinPrice: 1000, SortBy: models.SortByPrice, Limit: 20})
+
+		assert.NoError(t, err, "Expected no error, List() returned an error")
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("List_Error", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewProductRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("Query", mock.Anything).Return(&dynamodb.QueryOutput{}, errors.New("error getting items"))
+
+		page, err := repo.List(context.Background(), models.ProductQuery{SortBy: models.SortByName, Limit: 20})
+
+		assert.Error(t, err, "Expected an error, List() did not return an error")
+		assert.Nil(t, page.Products, "Expected products to be nil")
+		assert.Equal(t, "error listing products", err.Error(), "Expected error message to be 'error listing products'")
+
+		mockDB.AssertExpectations(t)
+	})
+}
+
+func TestProductRepositoryImpl_ListByCategory(t *testing.T) {
+	// lastKey es un LastEvaluatedKey del indice de categorias
+	lastKey := map[string]*dynamodb.AttributeValue{
+		"ProductID":       {S: stringPtr("test-id-2")},
+		"ListingCategory": {S: stringPtr("despensa")},
+		"SortName":        {S: stringPtr("test product 2")},
+	}
 
+	t.Run("ListByCategory_Success", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewProductRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("Query", mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+			return *input.IndexName == "CategoryIndex" &&
+				*input.KeyConditionExpression == "ListingCategory = :partition" &&
+				*input.ExpressionAttributeValues[":partition"].S == "despensa" &&
+				*input.FilterExpression == "SortPrice <= :maxPrice AND #store = :store" &&
+				*input.ExpressionAttributeNames["#store"] == "Store" &&
+				*input.Limit == 1 &&
+				*input.ScanIndexForward
+		})).Return(&dynamodb.QueryOutput{
+			Items:            []map[string]*dynamodb.AttributeValue{{"ProductID": {S: stringPtr("test-id-2")}}},
+			LastEvaluatedKey: lastKey,
+		}, nil)
+
+		page, err := repo.ListByCategory(context.Background(), "despensa", models.ProductQuery{Store: "cugat.cl", MaxPrice: 5000, SortBy: models.SortByName, Limit: 1})
+
+		assert.NoError(t, err, "Expected no error, ListByCategory() returned an error")
+		assert.Equal(t, []models.Product{{ProductID: "test-id-2"}}, page.Products, "Expected the products of the category")
+		assert.NotEmpty(t, page.NextCursor, "Expected a cursor for the next page")
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("ListByCategory_Cursor", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewProductRepositoryImpl(mockDB, "test-table")
+
+		cursor, err := encodeCursor(categoryCursor(models.SortByName), lastKey)
+		assert.NoError(t, err, "Expected no error encoding the cursor")
+
+		mockDB.On("Query", mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+			return assert.ObjectsAreEqual(lastKey, input.ExclusiveStartKey)
+		})).Return(&dynamodb.QueryOutput{}, nil)
+
+		_, err = repo.ListByCategory(context.Background(), "despensa", models.ProductQuery{SortBy: models.SortByName, Limit: 20, Cursor: cursor})
+
+		assert.NoError(t, err, "Expected no error, ListByCategory() returned an error")
 		mockDB.AssertExpectations(t)
 	})
+
+	t.Run("ListByCategory_InvalidCursor", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewProductRepositoryImpl(mockDB, "test-table")
+
+		// Un cursor de otra categoria o del listado general no sirve
+		otherCategory, err := encodeCursor(categoryCursor(models.SortByName), lastKey)
+		assert.NoError(t, err, "Expected no error encoding the cursor")
+		listing, err := encodeCursor(models.SortByName, lastKey)
+		assert.NoError(t, err, "Expected no error encoding the cursor")
+
+		for _, cursor := range []string{otherCategory, listing} {
+			_, err := repo.ListByCategory(context.Background(), "lacteos", models.ProductQuery{SortBy: models.SortByName, Limit: 20, Cursor: cursor})
+			assert.ErrorIs(t, err, ErrInvalidCursor, "Expected %q to be rejected", cursor)
+		}
+
+		mockDB.AssertNotCalled(t, "Query", mock.Anything)
+	})
+
+	t.Run("ListByCategory_Error", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewProductRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("Query", mock.Anything).Return(&dynamodb.QueryOutput{}, errors.New("error getting items"))
+
+		page, err := repo.ListByCategory(context.Background(), "despensa", models.ProductQuery{SortBy: models.SortByName, Limit: 20})
+
+		assert.Error(t, err, "Expected an error, ListByCategory() did not return an error")
+		assert.Nil(t, page.Products, "Expected products to be nil")
+		assert.Equal(t, "error listing products", err.Error(), "Expected error message to be 'error listing products'")
+	})
 }
 
 func stringPtr(s string) *string {
 	return &s
 }
+
+func TestProductRepositoryImpl_GetByIDs(t *testing.T) {
+	item := func(id string) map[string]*dynamodb.AttributeValue {
+		return map[string]*dynamodb.AttributeValue{
+			"ProductID": {S: stringPtr(id)},
+			"Name":      {S: stringPtr("Product " + id)},
+		}
+	}
+
+	t.Run("GetByIDs_Success", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewProductRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("BatchGetItem", mock.MatchedBy(func(input *dynamodb.BatchGetItemInput) bool {
+			return len(input.RequestItems["test-table"].Keys) == 2
+		})).Return(&dynamodb.BatchGetItemOutput{
+			Responses: map[string][]map[string]*dynamodb.AttributeValue{
+				"test-table": {item("p1")},
+			},
+		}, nil)
+
+		products, err := repo.GetByIDs(context.Background(), []string{"p1", "missing"})
+
+		assert.NoError(t, err, "Expected no error, GetByIDs() returned an error")
+		assert.Equal(t, []models.Product{{ProductID: "p1", Name: "Product p1"}}, products, "Expected only the existing product")
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("GetByIDs_RetriesUnprocessedKeys", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewProductRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("BatchGetItem", mock.Anything).Return(&dynamodb.BatchGetItemOutput{
+			Responses: map[string][]map[string]*dynamodb.AttributeValue{
+				"test-table": {item("p1")},
+			},
+			UnprocessedKeys: map[string]*dynamodb.KeysAndAttributes{
+				"test-table": {Keys: []map[string]*dynamodb.AttributeValue{
+					{"ProductID": {S: stringPtr("p2")}},
+				}},
+			},
+		}, nil).Once()
+		mockDB.On("BatchGetItem", mock.Anything).Return(&dynamodb.BatchGetItemOutput{
+			Responses: map[string][]map[string]*dynamodb.AttributeValue{
+				"test-table": {item("p2")},
+			},
+		}, nil).Once()
+
+		products, err := repo.GetByIDs(context.Background(), []string{"p1", "p2"})
+
+		assert.NoError(t, err, "Expected no error, GetByIDs() returned an error")
+		assert.Len(t, products, 2, "Expected both products")
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("GetByIDs_Chunks", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewProductRepositoryImpl(mockDB, "test-table")
+
+		ids := make([]string, maxBatchGetSize+1)
+		for i := range ids {
+			ids[i] = "p"
+		}
+		mockDB.On("BatchGetItem", mock.Anything).Return(&dynamodb.BatchGetItemOutput{}, nil).Twice()
+
+		products, err := repo.GetByIDs(context.Background(), ids)
+
+		assert.NoError(t, err, "Expected no error, GetByIDs() returned an error")
+		assert.Empty(t, products, "Expected no products")
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("GetByIDs_Error", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewProductRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("BatchGetItem", mock.Anything).Return(&dynamodb.BatchGetItemOutput{}, errors.New("error getting items"))
+
+		products, err := repo.GetByIDs(context.Background(), []string{"p1"})
+
+		assert.Error(t, err, "Expected an error, GetByIDs() did not return an error")
+		assert.Nil(t, products, "Expected products to be nil")
+		mockDB.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/dieg0code/shared/apperror"
+	"github.com/dieg0code/shared/db"
+	"github.com/dieg0code/shared/models"
+	"github.com/sirupsen/logrus"
+)
+
+type ScrapeRunRepositoryImpl struct {
+	db        dynamodbiface.DynamoDBAPI
+	tableName string
+}
+
+// Save implements ScrapeRunRepository.
+func (s *ScrapeRunRepositoryImpl) Save(ctx context.Context, run models.ScrapeRun) (models.ScrapeRun, error) {
+	item, err := dynamodbattribute.MarshalMap(run)
+	if err != nil {
+		logrus.WithError(err).Error("[ScrapeRunRepositoryImpl.Save] error marshalling scrape run")
+		return models.ScrapeRun{}, apperror.Internal("error saving scrape run")
+	}
+
+	input := &dynamodb.PutItemInput{
+		TableName: &s.tableName,
+		Item:      item,
+	}
+
+	_, err = s.db.PutItemWithContext(ctx, input)
+	if err != nil {
+		logrus.WithError(err).Error("[ScrapeRunRepositoryImpl.Save] error saving scrape run")
+		return models.ScrapeRun{}, apperror.Internal("error saving scrape run")
+	}
+
+	return run, nil
+}
+
+// GetAll implements ScrapeRunRepository.
+func (s *ScrapeRunRepositoryImpl) GetAll(ctx context.Context) ([]models.ScrapeRun, error) {
+	input := &dynamodb.ScanInput{
+		TableName: &s.tableName,
+	}
+
+	items, err := db.ScanAll(ctx, s.db, input)
+	if err != nil {
+		logrus.WithError(err).Error("[ScrapeRunRepositoryImpl.GetAll] error getting scrape runs")
+		return nil, apperror.Internal("error getting scrape runs")
+	}
+
+	var runs []models.ScrapeRun
+	err = dynamodbattribute.UnmarshalListOfMaps(items, &runs)
+	if err != nil {
+		logrus.WithError(err).Error("[ScrapeRunRepositoryImpl.GetAll] error unmarshalling scrape runs")
+		return nil, apperror.Internal("error getting scrape runs")
+	}
+
+	return runs, nil
+}
+
+// GetByID implements ScrapeRunRepository.
+func (s *ScrapeRunRepositoryImpl) GetByID(ctx context.Context, runID string) (models.ScrapeRun, error) {
+	input := &dynamodb.GetItemInput{
+		TableName: &s.tableName,
+		Key: map[string]*dynamodb.AttributeValue{
+			"RunID": {
+				S: aws.String(runID),
+			},
+		},
+	}
+
+	result, err := s.db.GetItemWithContext(ctx, input)
+	if err != nil {
+		logrus.WithError(err).Error("[ScrapeRunRepositoryImpl.GetByID] error getting scrape run")
+		return models.ScrapeRun{}, apperror.Internal("error getting scrape run")
+	}
+
+	if result.Item == nil {
+		return models.ScrapeRun{}, apperror.NotFound("scrape run not found")
+	}
+
+	var run models.ScrapeRun
+	err = dynamodbattribute.UnmarshalMap(result.Item, &run)
+	if err != nil {
+		logrus.WithError(err).Error("[ScrapeRunRepositoryImpl.GetByID] error unmarshalling scrape run")
+		return models.ScrapeRun{}, apperror.Internal("error getting scrape run")
+	}
+
+	return run, nil
+}
+
+func NewScrapeRunRepositoryImpl(db dynamodbiface.DynamoDBAPI, tableName string) ScrapeRunRepository {
+	return &ScrapeRunRepositoryImpl{
+		db:        db,
+		tableName: tableName,
+	}
+}
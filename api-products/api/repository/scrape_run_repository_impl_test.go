@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/dieg0code/shared/apperror"
+	"github.com/dieg0code/shared/mocks"
+	"github.com/dieg0code/shared/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func testScrapeRun() models.ScrapeRun {
+	return models.ScrapeRun{
+		RunID:       "run-id",
+		TriggeredBy: "user-id",
+		StartedAt:   "2024-08-20T10:00:00Z",
+		FinishedAt:  "2024-08-20T10:03:00Z",
+		Status:      models.ScrapeRunSucceeded,
+		Products:    120,
+		Pages:       6,
+		FailedPages: 1,
+		Categories: []models.CategoryRun{
+			{Category: "despensa", Products: 120, Pages: 5},
+			{Category: "lacteos", Products: 0, Pages: 1, FailedPages: 1, Errors: []models.ScrapeError{
+				{Category: "lacteos", PageURL: "https://cugat.cl/categoria-producto/lacteos/page/1/", StatusCode: 500, Error: "Internal Server Error"},
+			}},
+		},
+	}
+}
+
+func TestScrapeRunRepositoryImpl_Save(t *testing.T) {
+	t.Run("Save_Success", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewScrapeRunRepositoryImpl(mockDB, "test-table")
+
+		run := testScrapeRun()
+
+		mockDB.On("PutItem", mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+			return *input.Item["RunID"].S == "run-id"
+		})).Return(&dynamodb.PutItemOutput{}, nil)
+
+		result, err := repo.Save(context.Background(), run)
+
+		assert.NoError(t, err, "Expected no error, Save() returned an error")
+		assert.Equal(t, run, result, "Expected scrape run to be the same")
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("Save_Error", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewScrapeRunRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("PutItem", mock.Anything).Return(&dynamodb.PutItemOutput{}, errors.New("error putting item"))
+
+		result, err := repo.Save(context.Background(), testScrapeRun())
+
+		assert.Error(t, err, "Expected an error, Save() did not return an error")
+		assert.Equal(t, models.ScrapeRun{}, result, "Expected scrape run to be empty")
+		mockDB.AssertExpectations(t)
+	})
+}
+
+func TestScrapeRunRepositoryImpl_GetAll(t *testing.T) {
+	t.Run("GetAll_Success", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewScrapeRunRepositoryImpl(mockDB, "test-table")
+
+		run := testScrapeRun()
+		item, err := dynamodbattribute.MarshalMap(run)
+		assert.NoError(t, err, "Expected no error marshalling map")
+
+		mockDB.On("Scan", mock.Anything).Return(&dynamodb.ScanOutput{
+			Items: []map[string]*dynamodb.AttributeValue{item},
+		}, nil)
+
+		runs, err := repo.GetAll(context.Background())
+
+		assert.NoError(t, err, "Expected no error, GetAll() returned an error")
+		assert.Equal(t, []models.ScrapeRun{run}, runs, "Expected scrape runs to match")
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("GetAll_Paginated", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewScrapeRunRepositoryImpl(mockDB, "test-table")
+
+		// La tabla no cabe en una sola pagina de Scan
+		mockDB.On("Scan", mock.MatchedBy(func(input *dynamodb.ScanInput) bool {
+			return input.ExclusiveStartKey == nil
+		})).Return(&dynamodb.ScanOutput{
+			Items:            []map[string]*dynamodb.AttributeValue{{"RunID": {S: aws.String("run-1")}}},
+			LastEvaluatedKey: map[string]*dynamodb.AttributeValue{"RunID": {S: aws.String("run-1")}},
+		}, nil).Once()
+		mockDB.On("Scan", mock.MatchedBy(func(input *dynamodb.ScanInput) bool {
+			return input.ExclusiveStartKey != nil && *input.ExclusiveStartKey["RunID"].S == "run-1"
+		})).Return(&dynamodb.ScanOutput{
+			Items: []map[string]*dynamodb.AttributeValue{{"RunID": {S: aws.String("run-2")}}},
+		}, nil).Once()
+
+		runs, err := repo.GetAll(context.Background())
+
+		assert.NoError(t, err, "Expected no error, GetAll() returned an error")
+		assert.Equal(t, []models.ScrapeRun{{RunID: "run-1"}, {RunID: "run-2"}}, runs, "Expected scrape runs of every page")
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("GetAll_Error", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewScrapeRunRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("Scan", mock.Anything).Return(&dynamodb.ScanOutput{}, errors.New("error scanning"))
+
+		runs, err := repo.GetAll(context.Background())
+
+		assert.Error(t, err, "Expected an error, GetAll() did not return an error")
+		assert.Nil(t, runs, "Expected scrape runs to be nil")
+		mockDB.AssertExpectations(t)
+	})
+}
+
+func TestScrapeRunRepositoryImpl_GetByID(t *testing.T) {
+	t.Run("GetByID_Success", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewScrapeRunRepositoryImpl(mockDB, "test-table")
+
+		run := testScrapeRun()
+		item, err := dynamodbattribute.MarshalMap(run)
+		assert.NoError(t, err, "Expected no error marshalling map")
+
+		mockDB.On("GetItem", mock.Anything).Return(&dynamodb.GetItemOutput{Item: item}, nil)
+
+		result, err := repo.GetByID(context.Background(), "run-id")
+
+		assert.NoError(t, err, "Expected no error, GetByID() returned an error")
+		assert.Equal(t, run, result, "Expected scrape run to match")
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("GetByID_NotFound", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewScrapeRunRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("GetItem", mock.Anything).Return(&dynamodb.GetItemOutput{}, nil)
+
+		result, err := repo.GetByID(context.Background(), "run-id")
+
+		assert.Error(t, err, "Expected an error, GetByID() did not return an error")
+		assert.Equal(t, "scrape run not found", err.Error(), "Expected error message to be 'scrape run not found'")
+		assert.Equal(t, apperror.CodeNotFound, apperror.CodeOf(err), "Expected a not found error")
+		assert.Equal(t, models.ScrapeRun{}, result, "Expected scrape run to be empty")
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("GetByID_Error", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewScrapeRunRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("GetItem", mock.Anything).Return(&dynamodb.GetItemOutput{}, errors.New("error getting item"))
+
+		result, err := repo.GetByID(context.Background(), "run-id")
+
+		assert.Error(t, err, "Expected an error, GetByID() did not return an error")
+		assert.Equal(t, models.ScrapeRun{}, result, "Expected scrape run to be empty")
+		mockDB.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/dieg0code/shared/models"
+)
+
+type SearchRepository interface {
+	GetByPrefix(ctx context.Context, prefix string) ([]models.SearchEntry, error)
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/dieg0code/shared/apperror"
+	"github.com/dieg0code/shared/models"
+	"github.com/sirupsen/logrus"
+)
+
+// SearchRepositoryImpl lee el indice invertido de busqueda que arma el scraper
+type SearchRepositoryImpl struct {
+	db        dynamodbiface.DynamoDBAPI
+	tableName string
+}
+
+// GetByPrefix implements SearchRepository. Devuelve todas las entradas de los
+// tokens que empiezan con prefix.
+func (s *SearchRepositoryImpl) GetByPrefix(ctx context.Context, prefix string) ([]models.SearchEntry, error) {
+	input := dynamodb.QueryInput{
+		TableName:              &s.tableName,
+		KeyConditionExpression: aws.String("#prefix = :prefix"),
+		ExpressionAttributeNames: map[string]*string{
+			"#prefix": aws.String("Prefix"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":prefix": {S: aws.String(prefix)},
+		},
+	}
+
+	var items []map[string]*dynamodb.AttributeValue
+	for {
+		result, err := s.db.QueryWithContext(ctx, &input)
+		if err != nil {
+			logrus.WithError(err).Error("[SearchRepositoryImpl.GetByPrefix] error querying search entries")
+			return nil, apperror.Internal("error getting search entries")
+		}
+
+		items = append(items, result.Items...)
+		if len(result.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+
+	entries := []models.SearchEntry{}
+	err := dynamodbattribute.UnmarshalListOfMaps(items, &entries)
+	if err != nil {
+		logrus.WithError(err).Error("[SearchRepositoryImpl.GetByPrefix] error unmarshalling search entries")
+		return nil, apperror.Internal("error getting search entries")
+	}
+
+	return entries, nil
+}
+
+func NewSearchRepositoryImpl(db dynamodbiface.DynamoDBAPI, tableName string) SearchRepository {
+	return &SearchRepositoryImpl{
+		db:        db,
+		tableName: tableName,
+	}
+}
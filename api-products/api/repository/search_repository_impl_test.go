@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/dieg0code/shared/mocks"
+	"github.com/dieg0code/shared/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSearchRepositoryImpl_GetByPrefix(t *testing.T) {
+	entry := func(token string, productID string) models.SearchEntry {
+		return models.SearchEntry{
+			Prefix:    "ca",
+			Key:       token + "#" + productID,
+			Token:     token,
+			ProductID: productID,
+			Field:     models.SearchFieldName,
+		}
+	}
+
+	t.Run("GetByPrefix_FollowsPages", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewSearchRepositoryImpl(mockDB, "test-table")
+
+		first, err := dynamodbattribute.MarshalMap(entry("cafe", "p1"))
+		assert.NoError(t, err, "Expected no error marshalling map")
+		second, err := dynamodbattribute.MarshalMap(entry("caf", "p2"))
+		assert.NoError(t, err, "Expected no error marshalling map")
+
+		mockDB.On("Query", mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+			return input.ExclusiveStartKey == nil && *input.ExpressionAttributeValues[":prefix"].S == "ca"
+		})).Return(&dynamodb.QueryOutput{
+			Items:            []map[string]*dynamodb.AttributeValue{first},
+			LastEvaluatedKey: first,
+		}, nil).Once()
+		mockDB.On("Query", mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+			return input.ExclusiveStartKey != nil
+		})).Return(&dynamodb.QueryOutput{
+			Items: []map[string]*dynamodb.AttributeValue{second},
+		}, nil).Once()
+
+		entries, err := repo.GetByPrefix(context.Background(), "ca")
+
+		assert.NoError(t, err, "Expected no error, GetByPrefix() returned an error")
+		assert.Equal(t, []models.SearchEntry{entry("cafe", "p1"), entry("caf", "p2")}, entries, "Expected the entries of both pages")
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("GetByPrefix_Error", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewSearchRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("Query", mock.Anything).Return(&dynamodb.QueryOutput{}, errors.New("error querying"))
+
+		entries, err := repo.GetByPrefix(context.Background(), "ca")
+
+		assert.Error(t, err, "Expected an error, GetByPrefix() did not return an error")
+		assert.Nil(t, entries, "Expected entries to be nil")
+		mockDB.AssertExpectations(t)
+	})
+}
@@ -6,24 +6,41 @@ import (
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/awslabs/aws-lambda-go-api-proxy/gin"
 	"github.com/dieg0code/serverles-api-scraper/api/controller"
+	"github.com/dieg0code/shared/problem"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 )
 
 type Router struct {
-	ProductController controller.ProductController
-	ginLambda         *ginadapter.GinLambda
+	ProductController   controller.ProductController
+	ScrapeRunController controller.ScrapeRunController
+	CategoryController  controller.CategoryController
+	ginLambda           *ginadapter.GinLambda
 }
 
-func NewRouter(productController controller.ProductController) *Router {
+func NewRouter(productController controller.ProductController, scrapeRunController controller.ScrapeRunController, categoryController controller.CategoryController) *Router {
 	return &Router{
-		ProductController: productController,
+		ProductController:   productController,
+		ScrapeRunController: scrapeRunController,
+		CategoryController:  categoryController,
 	}
 }
 
 func (r *Router) InitRoutes() *Router {
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.New()
-	router.Use(gin.Recovery())
+	router.Use(problem.Recovery())
+
+	// Las rutas y metodos desconocidos tambien responden problem+json
+	router.HandleMethodNotAllowed = true
+	router.NoRoute(problem.NoRoute)
+	router.NoMethod(problem.NoMethod)
+
+	// Los errores de validacion nombran los campos como los envia el cliente
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		problem.RegisterTagNames(v)
+	}
 
 	router.GET("", func(ctx *gin.Context) {
 		ctx.JSON(200, gin.H{
@@ -36,9 +53,23 @@ func (r *Router) InitRoutes() *Router {
 		productRoute := baseRoute.Group("/products")
 		{
 			productRoute.GET("", r.ProductController.GetAll)
+			productRoute.GET("/search", r.ProductController.Search)
 			productRoute.GET("/:productId", r.ProductController.GetByID)
+			productRoute.GET("/:productId/history", r.ProductController.GetPriceHistory)
 			productRoute.POST("", r.ProductController.UpdateData)
 		}
+
+		categoryRoute := baseRoute.Group("/categories")
+		{
+			categoryRoute.GET("", r.CategoryController.GetAll)
+			categoryRoute.GET("/:slug/products", r.CategoryController.GetProducts)
+		}
+
+		scrapeRoute := baseRoute.Group("/scrapes")
+		{
+			scrapeRoute.GET("", r.ScrapeRunController.GetAll)
+			scrapeRoute.GET("/:runId", r.ScrapeRunController.GetByID)
+		}
 	}
 
 	r.ginLambda = ginadapter.New(router)
@@ -0,0 +1,13 @@
+package service
+
+import (
+	"context"
+
+	"github.com/dieg0code/serverles-api-scraper/api/data/request"
+	"github.com/dieg0code/shared/json/response"
+)
+
+type CategoryService interface {
+	GetAll(ctx context.Context) ([]response.CategoryResponse, error)
+	GetProducts(ctx context.Context, slug string, filter request.ProductFilterRequest) ([]response.ProductResponse, response.PaginationResponse, error)
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"github.com/dieg0code/serverles-api-scraper/api/data/request"
+	"github.com/dieg0code/serverles-api-scraper/api/repository"
+	"github.com/dieg0code/shared/apperror"
+	"github.com/dieg0code/shared/json/response"
+	"github.com/dieg0code/shared/models"
+	"github.com/sirupsen/logrus"
+)
+
+type CategoryServiceImpl struct {
+	CategoryRepository repository.CategoryRepository
+	ProductRepository  repository.ProductRepository
+}
+
+// GetAll implements CategoryService.
+func (c *CategoryServiceImpl) GetAll(ctx context.Context) ([]response.CategoryResponse, error) {
+	result, err := c.CategoryRepository.GetAll(ctx)
+	if err != nil {
+		logrus.WithError(err).Error("[CategoryServiceImpl.GetAll] Error getting all categories")
+		return nil, err
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Store != result[j].Store {
+			return result[i].Store < result[j].Store
+		}
+		return result[i].Slug < result[j].Slug
+	})
+
+	categories := []response.CategoryResponse{}
+	for _, category := range result {
+		categories = append(categories, response.CategoryResponse{
+			Store:        category.Store,
+			Slug:         category.Slug,
+			Name:         category.Name,
+			Parent:       category.Parent,
+			ProductCount: category.ProductCount,
+			LastUpdated:  category.LastUpdated,
+		})
+	}
+
+	return categories, nil
+}
+
+// GetProducts implements CategoryService. Todos los ordenes salen de los
+// indices de categoria. Los productos solo quedan indexados en su categoria
+// de primer nivel, asi que una subcategoria o un slug desconocido es un 404.
+func (c *CategoryServiceImpl) GetProducts(ctx context.Context, slug string, filter request.ProductFilterRequest) ([]response.ProductResponse, response.PaginationResponse, error) {
+	categories, err := c.CategoryRepository.GetAll(ctx)
+	if err != nil {
+		logrus.WithError(err).Error("[CategoryServiceImpl.GetProducts] Error getting categories")
+		return nil, response.PaginationResponse{}, err
+	}
+	if !isListedCategory(categories, slug, filter.Store) {
+		return nil, response.PaginationResponse{}, apperror.NotFound("category not found")
+	}
+
+	filter.Category = slug
+	query := toProductQuery(filter)
+
+	page, err := c.ProductRepository.ListByCategory(ctx, slug, query)
+	if err != nil {
+		logrus.WithError(err).Error("[CategoryServiceImpl.GetProducts] Error getting products by category")
+		return nil, response.PaginationResponse{}, err
+	}
+
+	products, pagination := toProductPage(page, query.Limit)
+	return products, pagination, nil
+}
+
+// isListedCategory indica si el slug es una categoria de primer nivel, de la
+// tienda pedida si hay una
+func isListedCategory(categories []models.Category, slug string, store string) bool {
+	for _, category := range categories {
+		if category.Slug != slug || category.Parent != "" {
+			continue
+		}
+		if store == "" || category.Store == store {
+			return true
+		}
+	}
+	return false
+}
+
+func NewCategoryServiceImpl(categoryRepository repository.CategoryRepository, productRepository repository.ProductRepository) CategoryService {
+	return &CategoryServiceImpl{
+		CategoryRepository: categoryRepository,
+		ProductRepository:  productRepository,
+	}
+}
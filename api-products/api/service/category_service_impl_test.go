@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dieg0code/serverles-api-scraper/api/data/request"
+	"github.com/dieg0code/shared/apperror"
+	"github.com/dieg0code/shared/json/response"
+	"github.com/dieg0code/shared/mocks"
+	"github.com/dieg0code/shared/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCategoryService_GetAll(t *testing.T) {
+	t.Run("GetAll_Success", func(t *testing.T) {
+		mockCategoryRepo := new(mocks.MockCategoryRepository)
+		mockProductRepo := new(mocks.MockProductRepository)
+		categoryService := NewCategoryServiceImpl(mockCategoryRepo, mockProductRepo)
+
+		mockCategoryRepo.On("GetAll").Return([]models.Category{
+			{Store: "cugat.cl", Slug: "lacteos", Name: "Lacteos", ProductCount: 12, LastUpdated: "2024-08-20T10:05:00Z"},
+			{Store: "cugat.cl", Slug: "arroz", Name: "Arroz", Parent: "despensa", LastSeen: "2024-08-20T10:00:00Z"},
+			{Store: "cugat.cl", Slug: "despensa", Name: "Despensa", ProductCount: 42, LastUpdated: "2024-08-20T10:05:00Z"},
+		}, nil)
+
+		categories, err := categoryService.GetAll(context.Background())
+
+		assert.NoError(t, err, "Expected no error, GetAll() returned an error")
+		assert.Equal(t, []response.CategoryResponse{
+			{Store: "cugat.cl", Slug: "arroz", Name: "Arroz", Parent: "despensa"},
+			{Store: "cugat.cl", Slug: "despensa", Name: "Despensa", ProductCount: 42, LastUpdated: "2024-08-20T10:05:00Z"},
+			{Store: "cugat.cl", Slug: "lacteos", Name: "Lacteos", ProductCount: 12, LastUpdated: "2024-08-20T10:05:00Z"},
+		}, categories, "Expected categories sorted by store and slug")
+		mockCategoryRepo.AssertExpectations(t)
+	})
+
+	t.Run("GetAll_Error", func(t *testing.T) {
+		mockCategoryRepo := new(mocks.MockCategoryRepository)
+		mockProductRepo := new(mocks.MockProductRepository)
+		categoryService := NewCategoryServiceImpl(mockCategoryRepo, mockProductRepo)
+
+		mockCategoryRepo.On("GetAll").Return([]models.Category{}, assert.AnError)
+
+		categories, err := categoryService.GetAll(context.Background())
+
+		assert.Error(t, err, "Expected error getting all categories")
+		assert.Nil(t, categories, "Expected nil categories")
+	})
+}
+
+func TestCategoryService_GetProducts(t *testing.T) {
+	t.Run("GetProducts_ByName", func(t *testing.T) {
+		mockCategoryRepo := new(mocks.MockCategoryRepository)
+		mockProductRepo := new(mocks.MockProductRepository)
+		categoryService := NewCategoryServiceImpl(mockCategoryRepo, mockProductRepo)
+
+		mockCategoryRepo.On("GetAll").Return([]models.Category{
+			{Store: "cugat.cl", Slug: "despensa", Name: "Despensa"},
+			{Store: "cugat.cl", Slug: "arroz", Name: "Arroz", Parent: "despensa"},
+		}, nil)
+		// El orden por defecto usa el indice de categorias
+		mockProductRepo.On("ListByCategory", "despensa", models.ProductQuery{
+			Store:    "cugat.cl",
+			Category: "despensa",
+			SortBy:   models.SortByName,
+			Limit:    20,
+		}).Return(models.ProductPage{
+			Products:   []models.Product{{ProductID: "test-id", Name: "Arroz", CategorySlug: "despensa"}},
+			NextCursor: "next",
+		}, nil)
+
+		products, pagination, err := categoryService.GetProducts(context.Background(), "despensa", request.ProductFilterRequest{Store: "cugat.cl"})
+
+		assert.NoError(t, err, "Expected no error, GetProducts() returned an error")
+		assert.Equal(t, []response.ProductResponse{{ProductID: "test-id", Name: "Arroz", CategorySlug: "despensa"}}, products, "Expected the products of the category")
+		assert.Equal(t, response.PaginationResponse{PageSize: 20, Count: 1, NextCursor: "next", HasMore: true}, pagination, "Expected pagination metadata")
+		mockProductRepo.AssertNotCalled(t, "List", mock.Anything)
+	})
+
+	t.Run("GetProducts_OtherSort", func(t *testing.T) {
+		mockCategoryRepo := new(mocks.MockCategoryRepository)
+		mockProductRepo := new(mocks.MockProductRepository)
+		categoryService := NewCategoryServiceImpl(mockCategoryRepo, mockProductRepo)
+
+		mockCategoryRepo.On("GetAll").Return([]models.Category{
+			{Store: "cugat.cl", Slug: "despensa", Name: "Despensa"},
+			{Store: "cugat.cl", Slug: "arroz", Name: "Arroz", Parent: "despensa"},
+		}, nil)
+		// Los otros ordenes tambien usan los indices de categoria
+		mockProductRepo.On("ListByCategory", "despensa", models.ProductQuery{
+			Category:   "despensa",
+			SortBy:     models.SortByPrice,
+			Descending: true,
+			Limit:      10,
+		}).Return(models.ProductPage{Products: []models.Product{}}, nil)
+
+		products, _, err := categoryService.GetProducts(context.Background(), "despensa", request.ProductFilterRequest{
+			Category: "lacteos",
+			Sort:     models.SortByPrice,
+			Order:    "desc",
+			PageSize: 10,
+		})
+
+		assert.NoError(t, err, "Expected no error, GetProducts() returned an error")
+		assert.Empty(t, products, "Expected no products")
+		mockProductRepo.AssertExpectations(t)
+		mockProductRepo.AssertNotCalled(t, "List", mock.Anything)
+	})
+
+	t.Run("GetProducts_Error", func(t *testing.T) {
+		mockCategoryRepo := new(mocks.MockCategoryRepository)
+		mockProductRepo := new(mocks.MockProductRepository)
+		categoryService := NewCategoryServiceImpl(mockCategoryRepo, mockProductRepo)
+
+		mockCategoryRepo.On("GetAll").Return([]models.Category{
+			{Store: "cugat.cl", Slug: "despensa", Name: "Despensa"},
+			{Store: "cugat.cl", Slug: "arroz", Name: "Arroz", Parent: "despensa"},
+		}, nil)
+		mockProductRepo.On("ListByCategory", "despensa", mock.Anything).Return(models.ProductPage{}, assert.AnError)
+
+		products, _, err := categoryService.GetProducts(context.Background(), "despensa", request.ProductFilterRequest{})
+
+		assert.Error(t, err, "Expected error getting products by category")
+		assert.Nil(t, products, "Expected nil products")
+	})
+
+	t.Run("GetProducts_Subcategory", func(t *testing.T) {
+		mockCategoryRepo := new(mocks.MockCategoryRepository)
+		mockProductRepo := new(mocks.MockProductRepository)
+		categoryService := NewCategoryServiceImpl(mockCategoryRepo, mockProductRepo)
+
+		mockCategoryRepo.On("GetAll").Return([]models.Category{
+			{Store: "cugat.cl", Slug: "despensa", Name: "Despensa"},
+			{Store: "cugat.cl", Slug: "arroz", Name: "Arroz", Parent: "despensa"},
+		}, nil)
+
+		// Los productos de arroz estan indexados en despensa
+		products, _, err := categoryService.GetProducts(context.Background(), "arroz", request.ProductFilterRequest{})
+
+		assert.True(t, apperror.Is(err, apperror.CodeNotFound), "Expected not found for a subcategory")
+		assert.Nil(t, products, "Expected nil products")
+		mockProductRepo.AssertNotCalled(t, "ListByCategory", mock.Anything, mock.Anything)
+	})
+
+	t.Run("GetProducts_UnknownCategory", func(t *testing.T) {
+		mockCategoryRepo := new(mocks.MockCategoryRepository)
+		mockProductRepo := new(mocks.MockProductRepository)
+		categoryService := NewCategoryServiceImpl(mockCategoryRepo, mockProductRepo)
+
+		mockCategoryRepo.On("GetAll").Return([]models.Category{
+			{Store: "cugat.cl", Slug: "despensa", Name: "Despensa"},
+			{Store: "cugat.cl", Slug: "arroz", Name: "Arroz", Parent: "despensa"},
+		}, nil)
+
+		products, _, err := categoryService.GetProducts(context.Background(), "despensa", request.ProductFilterRequest{Store: "otra.cl"})
+
+		assert.True(t, apperror.Is(err, apperror.CodeNotFound), "Expected not found for a category the store doesn't have")
+		assert.Nil(t, products, "Expected nil products")
+		mockProductRepo.AssertNotCalled(t, "ListByCategory", mock.Anything, mock.Anything)
+	})
+
+	t.Run("GetProducts_ErrorGettingCategories", func(t *testing.T) {
+		mockCategoryRepo := new(mocks.MockCategoryRepository)
+		mockProductRepo := new(mocks.MockProductRepository)
+		categoryService := NewCategoryServiceImpl(mockCategoryRepo, mockProductRepo)
+
+		mockCategoryRepo.On("GetAll").Return([]models.Category{}, assert.AnError)
+
+		products, _, err := categoryService.GetProducts(context.Background(), "despensa", request.ProductFilterRequest{})
+
+		assert.Error(t, err, "Expected error getting categories")
+		assert.Nil(t, products, "Expected nil products")
+		mockProductRepo.AssertNotCalled(t, "ListByCategory", mock.Anything, mock.Anything)
+	})
+}
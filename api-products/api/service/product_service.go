@@ -1,12 +1,16 @@
 package service
 
 import (
+	"context"
+
 	"github.com/dieg0code/serverles-api-scraper/api/data/request"
 	"github.com/dieg0code/shared/json/response"
 )
 
 type ProductService interface {
-	GetAll() ([]response.ProductResponse, error)
-	GetByID(productID string) (response.ProductResponse, error)
-	UpdateData(updateData request.UpdateDataRequest) (bool, error)
+	GetAll(ctx context.Context, filter request.ProductFilterRequest) ([]response.ProductResponse, response.PaginationResponse, error)
+	GetByID(ctx context.Context, productID string) (response.ProductResponse, error)
+	Search(ctx context.Context, searchReq request.SearchRequest) ([]response.ProductResponse, error)
+	UpdateData(ctx context.Context, updateData request.UpdateDataRequest, triggeredBy string) (string, error)
+	GetPriceHistory(ctx context.Context, productID string, historyReq request.PriceHistoryRequest) ([]response.PriceHistoryResponse, error)
 }
@@ -1,89 +1,299 @@
 package service
 
 import (
+	"context"
+	"encoding/json"
+	"time"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/lambda"
 	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
 	"github.com/dieg0code/serverles-api-scraper/api/data/request"
 	"github.com/dieg0code/serverles-api-scraper/api/repository"
+	"github.com/dieg0code/shared/apperror"
+	sharedRequest "github.com/dieg0code/shared/json/request"
 	"github.com/dieg0code/shared/json/response"
+	"github.com/dieg0code/shared/models"
+	"github.com/dieg0code/shared/search"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
 
 type ProductServiceImpl struct {
-	ProductRepository repository.ProductRepository
-	lambdaClient      lambdaiface.LambdaAPI
+	ProductRepository      repository.ProductRepository
+	PriceHistoryRepository repository.PriceHistoryRepository
+	ScrapeRunRepository    repository.ScrapeRunRepository
+	SearchRepository       repository.SearchRepository
+	lambdaClient           lambdaiface.LambdaAPI
 }
 
+// defaultPageSize es el tamaño de pagina cuando no se envia page_size
+const defaultPageSize = 20
+
 // GetAll implements ProductService.
-func (p *ProductServiceImpl) GetAll() ([]response.ProductResponse, error) {
-	result, err := p.ProductRepository.GetAll()
+func (p *ProductServiceImpl) GetAll(ctx context.Context, filter request.ProductFilterRequest) ([]response.ProductResponse, response.PaginationResponse, error) {
+	query := toProductQuery(filter)
+	page, err := p.ProductRepository.List(ctx, query)
 	if err != nil {
 		logrus.WithError(err).Error("[ProductServiceImpl.GetAll] Error getting all products")
-		return nil, err
+		return nil, response.PaginationResponse{}, err
+	}
+
+	products, pagination := toProductPage(page, query.Limit)
+	return products, pagination, nil
+}
+
+// GetByID implements ProductService.
+func (p *ProductServiceImpl) GetByID(ctx context.Context, productID string) (response.ProductResponse, error) {
+	result, err := p.ProductRepository.GetByID(ctx, productID)
+	if err != nil {
+		logrus.WithError(err).Error("[ProductServiceImpl.GetByID] Error getting product by ID")
+		return response.ProductResponse{}, err
+	}
+
+	return toProductResponse(result), nil
+}
+
+// Search implements ProductService.
+func (p *ProductServiceImpl) Search(ctx context.Context, searchReq request.SearchRequest) ([]response.ProductResponse, error) {
+	products := []response.ProductResponse{}
+	query := search.Tokenize(searchReq.Query)
+	if len(query) == 0 {
+		return products, nil
+	}
+
+	limit := searchReq.Limit
+	if limit == 0 {
+		limit = defaultPageSize
+	}
+
+	// Cada palabra solo puede coincidir con tokens de su misma particion
+	var entries []models.SearchEntry
+	seen := make(map[string]bool)
+	for _, word := range query {
+		prefix := search.Prefix(word)
+		if seen[prefix] {
+			continue
+		}
+		seen[prefix] = true
+
+		result, err := p.SearchRepository.GetByPrefix(ctx, prefix)
+		if err != nil {
+			logrus.WithError(err).Error("[ProductServiceImpl.Search] Error getting search entries")
+			return nil, err
+		}
+		entries = append(entries, result...)
 	}
 
-	var products []response.ProductResponse
-	for _, product := range result {
-		productResponse := response.ProductResponse{
-			ProductID:       product.ProductID,
-			Name:            product.Name,
-			Category:        product.Category,
-			DiscountedPrice: product.DiscountedPrice,
-			OriginalPrice:   product.OriginalPrice,
-			LastUpdated:     product.LastUpdated,
+	// Los productos se leen en bloques de limit en el orden del ranking. El
+	// indice puede tener entradas de productos descontinuados o borrados, por
+	// eso se sigue leyendo hasta completar limit.
+	ranked := search.Rank(query, entries)
+	for start := 0; start < len(ranked) && len(products) < limit; start += limit {
+		var ids []string
+		for _, result := range ranked[start:min(start+limit, len(ranked))] {
+			ids = append(ids, result.ProductID)
+		}
+
+		found, err := p.ProductRepository.GetByIDs(ctx, ids)
+		if err != nil {
+			logrus.WithError(err).Error("[ProductServiceImpl.Search] Error getting products")
+			return nil, err
 		}
 
-		products = append(products, productResponse)
+		byID := make(map[string]models.Product, len(found))
+		for _, product := range found {
+			byID[product.ProductID] = product
+		}
+
+		for _, id := range ids {
+			product, ok := byID[id]
+			if !ok || product.Listing != models.ListingActive {
+				continue
+			}
+			products = append(products, toProductResponse(product))
+			if len(products) == limit {
+				break
+			}
+		}
 	}
 
 	return products, nil
 }
 
-// GetByID implements ProductService.
-func (p *ProductServiceImpl) GetByID(productID string) (response.ProductResponse, error) {
-	result, err := p.ProductRepository.GetByID(productID)
+// GetPriceHistory implements ProductService.
+func (p *ProductServiceImpl) GetPriceHistory(ctx context.Context, productID string, historyReq request.PriceHistoryRequest) ([]response.PriceHistoryResponse, error) {
+	// Las fechas son YYYY-MM-DD, asi que se pueden comparar como string.
+	// gtefield no sirve aca porque en strings compara el largo.
+	if historyReq.From != "" && historyReq.To != "" && historyReq.From > historyReq.To {
+		return nil, apperror.Validation("from must be before or equal to to")
+	}
+
+	result, err := p.PriceHistoryRepository.GetByProductID(ctx, productID, historyReq.From, historyReq.To)
 	if err != nil {
-		logrus.WithError(err).Error("[ProductServiceImpl.GetByID] Error getting product by ID")
-		return response.ProductResponse{}, err
+		logrus.WithError(err).Error("[ProductServiceImpl.GetPriceHistory] Error getting price history")
+		return nil, err
 	}
 
-	productResponse := response.ProductResponse{
-		ProductID:       result.ProductID,
-		Name:            result.Name,
-		Category:        result.Category,
-		OriginalPrice:   result.OriginalPrice,
-		DiscountedPrice: result.DiscountedPrice,
+	history := []response.PriceHistoryResponse{}
+	for _, observation := range result {
+		history = append(history, response.PriceHistoryResponse{
+			Date:            observation.Date,
+			OriginalPrice:   observation.OriginalPrice,
+			DiscountedPrice: observation.DiscountedPrice,
+		})
 	}
 
-	return productResponse, nil
+	return history, nil
 }
 
 // UpdateData implements ProductService.
-func (p *ProductServiceImpl) UpdateData(updateData request.UpdateDataRequest) (bool, error) {
+func (p *ProductServiceImpl) UpdateData(ctx context.Context, updateData request.UpdateDataRequest, triggeredBy string) (string, error) {
 	if !updateData.UpdateData {
-		return false, nil
+		return "", nil
+	}
+
+	// Registrar la ejecución antes de invocar al scraper
+	run := models.ScrapeRun{
+		RunID:       uuid.New().String(),
+		TriggeredBy: triggeredBy,
+		StartedAt:   time.Now().UTC().Format(time.RFC3339),
+		Status:      models.ScrapeRunPending,
+		Categories:  []models.CategoryRun{},
+	}
+
+	_, err := p.ScrapeRunRepository.Save(ctx, run)
+	if err != nil {
+		logrus.WithError(err).Error("[ProductServiceImpl.UpdateData] Error saving scrape run")
+		return "", err
+	}
+
+	payload, err := json.Marshal(sharedRequest.ScrapeRequest{
+		RunID:       run.RunID,
+		TriggeredBy: run.TriggeredBy,
+	})
+	if err != nil {
+		logrus.WithError(err).Error("[ProductServiceImpl.UpdateData] Error marshalling scrape request")
+		return "", err
 	}
 
 	// Preparar la entrada para invocar la función Lambda
 	input := &lambda.InvokeInput{
 		FunctionName:   aws.String("scraper"),
 		InvocationType: aws.String("Event"),
+		Payload:        payload,
 	}
 
 	// Invocar la función Lambda
-	_, err := p.lambdaClient.Invoke(input)
+	_, err = p.lambdaClient.InvokeWithContext(ctx, input)
 	if err != nil {
 		logrus.WithError(err).Error("[ProductServiceImpl.UpdateData] Error invoking lambda function")
-		return false, err
+
+		run.Status = models.ScrapeRunFailed
+		run.Error = err.Error()
+		run.FinishedAt = time.Now().UTC().Format(time.RFC3339)
+		if _, saveErr := p.ScrapeRunRepository.Save(ctx, run); saveErr != nil {
+			logrus.WithError(saveErr).Error("[ProductServiceImpl.UpdateData] Error saving failed scrape run")
+		}
+
+		return "", err
+	}
+
+	return run.RunID, nil
+}
+
+// toProductQuery arma la consulta del repositorio con los valores por defecto
+// del listado
+func toProductQuery(filter request.ProductFilterRequest) models.ProductQuery {
+	pageSize := filter.PageSize
+	if pageSize == 0 {
+		pageSize = defaultPageSize
 	}
 
-	return true, nil
+	sortBy := filter.Sort
+	if sortBy == "" {
+		sortBy = models.SortByName
+	}
+
+	return models.ProductQuery{
+		Store:      filter.Store,
+		Category:   filter.Category,
+		OnPromo:    filter.OnPromo,
+		Discounted: filter.Discounted,
+		MinPrice:   filter.MinPrice,
+		MaxPrice:   filter.MaxPrice,
+		SortBy:     sortBy,
+		Descending: filter.Order == "desc",
+		Limit:      pageSize,
+		Cursor:     filter.Cursor,
+	}
+}
+
+// toProductPage convierte una pagina del repositorio en la respuesta paginada
+func toProductPage(page models.ProductPage, pageSize int) ([]response.ProductResponse, response.PaginationResponse) {
+	products := []response.ProductResponse{}
+	for _, product := range page.Products {
+		products = append(products, toProductResponse(product))
+	}
+
+	pagination := response.PaginationResponse{
+		PageSize:   pageSize,
+		Count:      len(products),
+		NextCursor: page.NextCursor,
+		HasMore:    page.NextCursor != "",
+	}
+
+	return products, pagination
+}
+
+func toProductResponse(product models.Product) response.ProductResponse {
+	return response.ProductResponse{
+		ProductID:       product.ProductID,
+		Store:           product.Store,
+		Name:            product.Name,
+		Category:        product.Category,
+		CategorySlug:    product.CategorySlug,
+		OriginalPrice:   product.OriginalPrice,
+		DiscountedPrice: product.DiscountedPrice,
+		DiscountPercent: product.DiscountPercent,
+		MinPrice:        product.MinPrice,
+		MaxPrice:        product.MaxPrice,
+		Promotion:       toPromotionResponse(product.Promotion),
+		URL:             product.URL,
+		ImageURL:        product.ImageURL,
+		SKU:             product.SKU,
+		StockStatus:     product.StockStatus,
+		PackageQuantity: product.PackageQuantity,
+		PackageUnit:     product.PackageUnit,
+		PackageCount:    product.PackageCount,
+		UnitPrice:       product.UnitPrice,
+		LastUpdated:     product.LastUpdated,
+		DiscontinuedAt:  product.DiscontinuedAt,
+	}
+}
+
+func toPromotionResponse(promotion *models.Promotion) *response.PromotionResponse {
+	if promotion == nil {
+		return nil
+	}
+	return &response.PromotionResponse{
+		Kind:        promotion.Kind,
+		Quantity:    promotion.Quantity,
+		Price:       promotion.Price,
+		PayQuantity: promotion.PayQuantity,
+		Percent:     promotion.Percent,
+		UnitPrice:   promotion.UnitPrice,
+		ValidFrom:   promotion.ValidFrom,
+		ValidUntil:  promotion.ValidUntil,
+	}
 }
 
-func NewProductServiceImpl(productRepository repository.ProductRepository, lambdaClient lambdaiface.LambdaAPI) ProductService {
+func NewProductServiceImpl(productRepository repository.ProductRepository, priceHistoryRepository repository.PriceHistoryRepository, scrapeRunRepository repository.ScrapeRunRepository, searchRepository repository.SearchRepository, lambdaClient lambdaiface.LambdaAPI) ProductService {
 	return &ProductServiceImpl{
-		ProductRepository: productRepository,
-		lambdaClient:      lambdaClient,
+		ProductRepository:      productRepository,
+		PriceHistoryRepository: priceHistoryRepository,
+		ScrapeRunRepository:    scrapeRunRepository,
+		SearchRepository:       searchRepository,
+		lambdaClient:           lambdaClient,
 	}
 }
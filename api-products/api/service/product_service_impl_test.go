@@ -1,13 +1,18 @@
 package service
 
 import (
+	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/service/lambda"
 	"github.com/dieg0code/serverles-api-scraper/api/data/request"
+	"github.com/dieg0code/shared/apperror"
+	sharedRequest "github.com/dieg0code/shared/json/request"
 	"github.com/dieg0code/shared/json/response"
 	"github.com/dieg0code/shared/mocks"
 	"github.com/dieg0code/shared/models"
+	"github.com/dieg0code/shared/search"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -15,8 +20,11 @@ import (
 func TestPoductService_GetAll(t *testing.T) {
 	t.Run("GetAll_Success", func(t *testing.T) {
 		mockRepo := new(mocks.MockProductRepository)
+		mockPriceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		mockScrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		mockSearchRepo := new(mocks.MockSearchRepository)
 		mockLambdaClient := new(mocks.MockLambdaClient)
-		productService := NewProductServiceImpl(mockRepo, mockLambdaClient)
+		productService := NewProductServiceImpl(mockRepo, mockPriceHistoryRepo, mockScrapeRunRepo, mockSearchRepo, mockLambdaClient)
 
 		expectedProducts := []response.ProductResponse{
 			{
@@ -25,6 +33,7 @@ func TestPoductService_GetAll(t *testing.T) {
 				Category:        "Test Category",
 				OriginalPrice:   100,
 				DiscountedPrice: 90,
+				DiscountPercent: 10,
 			},
 			{
 				ProductID:       "test-id-2",
@@ -32,42 +41,94 @@ func TestPoductService_GetAll(t *testing.T) {
 				Category:        "Test Category 2",
 				OriginalPrice:   200,
 				DiscountedPrice: 190,
+				DiscountPercent: 5,
 			},
 		}
 
-		mockRepo.On("GetAll").Return([]models.Product{
-			{
-				ProductID:       "test-id",
-				Name:            "Test Product",
-				Category:        "Test Category",
-				OriginalPrice:   100,
-				DiscountedPrice: 90,
-			},
-			{
-				ProductID:       "test-id-2",
-				Name:            "Test Product 2",
-				Category:        "Test Category 2",
-				OriginalPrice:   200,
-				DiscountedPrice: 190,
+		// Sin parametros se pide la primera pagina por nombre
+		mockRepo.On("List", models.ProductQuery{SortBy: models.SortByName, Limit: 20}).Return(models.ProductPage{
+			Products: []models.Product{
+				{
+					ProductID:       "test-id",
+					Name:            "Test Product",
+					Category:        "Test Category",
+					OriginalPrice:   100,
+					DiscountedPrice: 90,
+					DiscountPercent: 10,
+				},
+				{
+					ProductID:       "test-id-2",
+					Name:            "Test Product 2",
+					Category:        "Test Category 2",
+					OriginalPrice:   200,
+					DiscountedPrice: 190,
+					DiscountPercent: 5,
+				},
 			},
+			NextCursor: "next",
 		}, nil)
 
-		products, err := productService.GetAll()
+		products, pagination, err := productService.GetAll(context.Background(), request.ProductFilterRequest{})
 
 		assert.NoError(t, err, "Expected no error, GetAll() returned an error")
 		assert.Equal(t, expectedProducts, products, "Expected products to be equal to the expected products")
+		assert.Equal(t, response.PaginationResponse{PageSize: 20, Count: 2, NextCursor: "next", HasMore: true}, pagination, "Expected pagination metadata")
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("GetAll_Filters", func(t *testing.T) {
+		mockRepo := new(mocks.MockProductRepository)
+		mockPriceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		mockScrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		mockSearchRepo := new(mocks.MockSearchRepository)
+		mockLambdaClient := new(mocks.MockLambdaClient)
+		productService := NewProductServiceImpl(mockRepo, mockPriceHistoryRepo, mockScrapeRunRepo, mockSearchRepo, mockLambdaClient)
+
+		mockRepo.On("List", models.ProductQuery{
+			Store:      "cugat.cl",
+			Category:   "despensa",
+			OnPromo:    true,
+			Discounted: true,
+			MinPrice:   1000,
+			MaxPrice:   5000,
+			SortBy:     models.SortByDiscount,
+			Descending: true,
+			Limit:      50,
+			Cursor:     "cursor",
+		}).Return(models.ProductPage{}, nil)
+
+		products, pagination, err := productService.GetAll(context.Background(), request.ProductFilterRequest{
+			Store:      "cugat.cl",
+			Category:   "despensa",
+			OnPromo:    true,
+			Discounted: true,
+			MinPrice:   1000,
+			MaxPrice:   5000,
+			Sort:       "discount",
+			Order:      "desc",
+			PageSize:   50,
+			Cursor:     "cursor",
+		})
+
+		assert.NoError(t, err, "Expected no error, GetAll() returned an error")
+		assert.Equal(t, []response.ProductResponse{}, products, "Expected an empty page")
+		assert.Equal(t, response.PaginationResponse{PageSize: 50}, pagination, "Expected the last page")
 
 		mockRepo.AssertExpectations(t)
 	})
 
 	t.Run("GetAll_Error", func(t *testing.T) {
 		mockRepo := new(mocks.MockProductRepository)
+		mockPriceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		mockScrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		mockSearchRepo := new(mocks.MockSearchRepository)
 		mockLambdaClient := new(mocks.MockLambdaClient)
-		productService := NewProductServiceImpl(mockRepo, mockLambdaClient)
+		productService := NewProductServiceImpl(mockRepo, mockPriceHistoryRepo, mockScrapeRunRepo, mockSearchRepo, mockLambdaClient)
 
-		mockRepo.On("GetAll").Return([]models.Product{}, assert.AnError)
+		mockRepo.On("List", mock.Anything).Return(models.ProductPage{}, assert.AnError)
 
-		products, err := productService.GetAll()
+		products, _, err := productService.GetAll(context.Background(), request.ProductFilterRequest{})
 
 		assert.Error(t, err, "Expected error Getting all products")
 		assert.Nil(t, products, "Expected products to be nil")
@@ -78,26 +139,61 @@ func TestPoductService_GetAll(t *testing.T) {
 func TestPoductService_GetByID(t *testing.T) {
 	t.Run("GetByID_Success", func(t *testing.T) {
 		mockRepo := new(mocks.MockProductRepository)
+		mockPriceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		mockScrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		mockSearchRepo := new(mocks.MockSearchRepository)
 		mockLambdaClient := new(mocks.MockLambdaClient)
-		productService := NewProductServiceImpl(mockRepo, mockLambdaClient)
+		productService := NewProductServiceImpl(mockRepo, mockPriceHistoryRepo, mockScrapeRunRepo, mockSearchRepo, mockLambdaClient)
 
 		expectedProduct := response.ProductResponse{
 			ProductID:       "test-id",
+			Store:           "cugat.cl",
 			Name:            "Test Product",
 			Category:        "Test Category",
 			OriginalPrice:   100,
 			DiscountedPrice: 90,
+			Promotion: &response.PromotionResponse{
+				Kind:       models.PromotionMultiBuy,
+				Quantity:   2,
+				Price:      160,
+				UnitPrice:  80,
+				ValidUntil: "2026-12-31",
+			},
+			URL:             "https://cugat.cl/producto/test-product/",
+			ImageURL:        "https://cugat.cl/wp-content/uploads/test-product.jpg",
+			SKU:             "1234",
+			StockStatus:     models.StockInStock,
+			PackageQuantity: 1,
+			PackageUnit:     "l",
+			PackageCount:    1,
+			UnitPrice:       90,
 		}
 
 		mockRepo.On("GetByID", "test-id").Return(models.Product{
 			ProductID:       "test-id",
+			Store:           "cugat.cl",
 			Name:            "Test Product",
 			Category:        "Test Category",
 			OriginalPrice:   100,
 			DiscountedPrice: 90,
+			Promotion: &models.Promotion{
+				Kind:       models.PromotionMultiBuy,
+				Quantity:   2,
+				Price:      160,
+				UnitPrice:  80,
+				ValidUntil: "2026-12-31",
+			},
+			URL:             "https://cugat.cl/producto/test-product/",
+			ImageURL:        "https://cugat.cl/wp-content/uploads/test-product.jpg",
+			SKU:             "1234",
+			StockStatus:     models.StockInStock,
+			PackageQuantity: 1,
+			PackageUnit:     "l",
+			PackageCount:    1,
+			UnitPrice:       90,
 		}, nil)
 
-		product, err := productService.GetByID("test-id")
+		product, err := productService.GetByID(context.Background(), "test-id")
 
 		assert.NoError(t, err, "Expected no error, GetByID() returned an error")
 		assert.Equal(t, expectedProduct, product, "Expected product to be equal to the expected product")
@@ -107,12 +203,15 @@ func TestPoductService_GetByID(t *testing.T) {
 
 	t.Run("GetByID_Error", func(t *testing.T) {
 		mockRepo := new(mocks.MockProductRepository)
+		mockPriceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		mockScrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		mockSearchRepo := new(mocks.MockSearchRepository)
 		mockLambdaClient := new(mocks.MockLambdaClient)
-		productService := NewProductServiceImpl(mockRepo, mockLambdaClient)
+		productService := NewProductServiceImpl(mockRepo, mockPriceHistoryRepo, mockScrapeRunRepo, mockSearchRepo, mockLambdaClient)
 
 		mockRepo.On("GetByID", "test-id").Return(models.Product{}, assert.AnError)
 
-		product, err := productService.GetByID("test-id")
+		product, err := productService.GetByID(context.Background(), "test-id")
 
 		assert.Error(t, err, "Expected error Getting product by ID")
 		assert.Equal(t, response.ProductResponse{}, product, "Expected product to be empty")
@@ -122,54 +221,279 @@ func TestPoductService_GetByID(t *testing.T) {
 func TestPoductService_UpdateData(t *testing.T) {
 	t.Run("UpdateData_Success", func(t *testing.T) {
 		mockRepo := new(mocks.MockProductRepository)
+		mockPriceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		mockScrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		mockSearchRepo := new(mocks.MockSearchRepository)
 		mockLambdaClient := new(mocks.MockLambdaClient)
-		productService := NewProductServiceImpl(mockRepo, mockLambdaClient)
+		productService := NewProductServiceImpl(mockRepo, mockPriceHistoryRepo, mockScrapeRunRepo, mockSearchRepo, mockLambdaClient)
 
 		updateReq := request.UpdateDataRequest{
 			UpdateData: true,
 		}
 
-		mockLambdaClient.On("Invoke", mock.Anything).Return(&lambda.InvokeOutput{}, nil)
+		var savedRun models.ScrapeRun
+		mockScrapeRunRepo.On("Save", mock.MatchedBy(func(run models.ScrapeRun) bool {
+			savedRun = run
+			return run.Status == models.ScrapeRunPending && run.TriggeredBy == "user-id"
+		})).Return(models.ScrapeRun{}, nil)
+
+		mockLambdaClient.On("Invoke", mock.MatchedBy(func(input *lambda.InvokeInput) bool {
+			var scrapeReq sharedRequest.ScrapeRequest
+			err := json.Unmarshal(input.Payload, &scrapeReq)
+			return err == nil && scrapeReq.RunID == savedRun.RunID && scrapeReq.TriggeredBy == "user-id"
+		})).Return(&lambda.InvokeOutput{}, nil)
 
-		success, err := productService.UpdateData(updateReq)
+		runID, err := productService.UpdateData(context.Background(), updateReq, "user-id")
 
 		assert.NoError(t, err, "Expected no error, UpdateData() returned an error")
-		assert.True(t, success, "Expected success to be true")
+		assert.NotEmpty(t, runID, "Expected run ID to be set")
+		assert.Equal(t, savedRun.RunID, runID, "Expected run ID to match the saved run")
 
+		mockScrapeRunRepo.AssertExpectations(t)
 		mockLambdaClient.AssertExpectations(t)
 	})
 
 	t.Run("UpdateData_InvokeError", func(t *testing.T) {
 		mockRepo := new(mocks.MockProductRepository)
+		mockPriceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		mockScrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		mockSearchRepo := new(mocks.MockSearchRepository)
 		mockLambdaClient := new(mocks.MockLambdaClient)
-		productService := NewProductServiceImpl(mockRepo, mockLambdaClient)
+		productService := NewProductServiceImpl(mockRepo, mockPriceHistoryRepo, mockScrapeRunRepo, mockSearchRepo, mockLambdaClient)
 
 		updateReq := request.UpdateDataRequest{
 			UpdateData: true,
 		}
 
+		mockScrapeRunRepo.On("Save", mock.MatchedBy(func(run models.ScrapeRun) bool {
+			return run.Status == models.ScrapeRunPending
+		})).Return(models.ScrapeRun{}, nil).Once()
+		mockScrapeRunRepo.On("Save", mock.MatchedBy(func(run models.ScrapeRun) bool {
+			return run.Status == models.ScrapeRunFailed && run.FinishedAt != ""
+		})).Return(models.ScrapeRun{}, nil).Once()
 		mockLambdaClient.On("Invoke", mock.Anything).Return(&lambda.InvokeOutput{}, assert.AnError)
 
-		success, err := productService.UpdateData(updateReq)
+		runID, err := productService.UpdateData(context.Background(), updateReq, "user-id")
 
 		assert.Error(t, err, "Expected error invoking lambda function")
-		assert.False(t, success, "Expected success to be false")
+		assert.Empty(t, runID, "Expected run ID to be empty")
 
+		mockScrapeRunRepo.AssertExpectations(t)
 		mockLambdaClient.AssertExpectations(t)
 	})
 
+	t.Run("UpdateData_SaveRunError", func(t *testing.T) {
+		mockRepo := new(mocks.MockProductRepository)
+		mockPriceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		mockScrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		mockSearchRepo := new(mocks.MockSearchRepository)
+		mockLambdaClient := new(mocks.MockLambdaClient)
+		productService := NewProductServiceImpl(mockRepo, mockPriceHistoryRepo, mockScrapeRunRepo, mockSearchRepo, mockLambdaClient)
+
+		updateReq := request.UpdateDataRequest{
+			UpdateData: true,
+		}
+
+		mockScrapeRunRepo.On("Save", mock.Anything).Return(models.ScrapeRun{}, assert.AnError)
+
+		runID, err := productService.UpdateData(context.Background(), updateReq, "user-id")
+
+		assert.Error(t, err, "Expected error saving scrape run")
+		assert.Empty(t, runID, "Expected run ID to be empty")
+
+		mockScrapeRunRepo.AssertExpectations(t)
+		mockLambdaClient.AssertNotCalled(t, "Invoke", mock.Anything)
+	})
+
 	t.Run("UpdateData_NoUpdate", func(t *testing.T) {
 		mockRepo := new(mocks.MockProductRepository)
+		mockPriceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		mockScrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		mockSearchRepo := new(mocks.MockSearchRepository)
 		mockLambdaClient := new(mocks.MockLambdaClient)
-		productService := NewProductServiceImpl(mockRepo, mockLambdaClient)
+		productService := NewProductServiceImpl(mockRepo, mockPriceHistoryRepo, mockScrapeRunRepo, mockSearchRepo, mockLambdaClient)
 
 		updateReq := request.UpdateDataRequest{
 			UpdateData: false,
 		}
 
-		success, err := productService.UpdateData(updateReq)
+		runID, err := productService.UpdateData(context.Background(), updateReq, "user-id")
 
 		assert.NoError(t, err, "Expected no error, UpdateData() returned an error")
-		assert.False(t, success, "Expected success to be false")
+		assert.Empty(t, runID, "Expected run ID to be empty")
+	})
+}
+
+func TestPoductService_GetPriceHistory(t *testing.T) {
+	t.Run("GetPriceHistory_Success", func(t *testing.T) {
+		mockRepo := new(mocks.MockProductRepository)
+		mockPriceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		mockScrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		mockSearchRepo := new(mocks.MockSearchRepository)
+		mockLambdaClient := new(mocks.MockLambdaClient)
+		productService := NewProductServiceImpl(mockRepo, mockPriceHistoryRepo, mockScrapeRunRepo, mockSearchRepo, mockLambdaClient)
+
+		historyReq := request.PriceHistoryRequest{
+			From: "2024-08-01",
+			To:   "2024-08-31",
+		}
+
+		mockPriceHistoryRepo.On("GetByProductID", "test-id", "2024-08-01", "2024-08-31").Return([]models.PriceObservation{
+			{ProductID: "test-id", Date: "2024-08-01", OriginalPrice: 100, DiscountedPrice: 0},
+			{ProductID: "test-id", Date: "2024-08-02", OriginalPrice: 110, DiscountedPrice: 90},
+		}, nil)
+
+		history, err := productService.GetPriceHistory(context.Background(), "test-id", historyReq)
+
+		assert.NoError(t, err, "Expected no error, GetPriceHistory() returned an error")
+		assert.Equal(t, []response.PriceHistoryResponse{
+			{Date: "2024-08-01", OriginalPrice: 100, DiscountedPrice: 0},
+			{Date: "2024-08-02", OriginalPrice: 110, DiscountedPrice: 90},
+		}, history, "Expected history to be equal to the expected history")
+
+		mockPriceHistoryRepo.AssertExpectations(t)
+	})
+
+	t.Run("GetPriceHistory_Empty", func(t *testing.T) {
+		mockRepo := new(mocks.MockProductRepository)
+		mockPriceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		mockScrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		mockSearchRepo := new(mocks.MockSearchRepository)
+		mockLambdaClient := new(mocks.MockLambdaClient)
+		productService := NewProductServiceImpl(mockRepo, mockPriceHistoryRepo, mockScrapeRunRepo, mockSearchRepo, mockLambdaClient)
+
+		mockPriceHistoryRepo.On("GetByProductID", "test-id", "", "").Return([]models.PriceObservation(nil), nil)
+
+		history, err := productService.GetPriceHistory(context.Background(), "test-id", request.PriceHistoryRequest{})
+
+		assert.NoError(t, err, "Expected no error, GetPriceHistory() returned an error")
+		assert.Empty(t, history, "Expected history to be empty")
+		assert.NotNil(t, history, "Expected history to be an empty list")
+	})
+
+	t.Run("GetPriceHistory_Error", func(t *testing.T) {
+		mockRepo := new(mocks.MockProductRepository)
+		mockPriceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		mockScrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		mockSearchRepo := new(mocks.MockSearchRepository)
+		mockLambdaClient := new(mocks.MockLambdaClient)
+		productService := NewProductServiceImpl(mockRepo, mockPriceHistoryRepo, mockScrapeRunRepo, mockSearchRepo, mockLambdaClient)
+
+		mockPriceHistoryRepo.On("GetByProductID", "test-id", "", "").Return([]models.PriceObservation{}, assert.AnError)
+
+		history, err := productService.GetPriceHistory(context.Background(), "test-id", request.PriceHistoryRequest{})
+
+		assert.Error(t, err, "Expected error getting price history")
+		assert.Nil(t, history, "Expected history to be nil")
+	})
+	t.Run("GetPriceHistory_FromAfterTo", func(t *testing.T) {
+		mockRepo := new(mocks.MockProductRepository)
+		mockPriceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		mockScrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		mockSearchRepo := new(mocks.MockSearchRepository)
+		mockLambdaClient := new(mocks.MockLambdaClient)
+		productService := NewProductServiceImpl(mockRepo, mockPriceHistoryRepo, mockScrapeRunRepo, mockSearchRepo, mockLambdaClient)
+
+		history, err := productService.GetPriceHistory(context.Background(), "test-id", request.PriceHistoryRequest{From: "2024-08-31", To: "2024-08-01"})
+
+		assert.True(t, apperror.Is(err, apperror.CodeValidation), "Expected a validation error, but got %v", err)
+		assert.Nil(t, history, "Expected history to be nil")
+		mockPriceHistoryRepo.AssertNotCalled(t, "GetByProductID", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestPoductService_Search(t *testing.T) {
+	active := func(id string, name string) models.Product {
+		return models.Product{ProductID: id, Name: name, Listing: models.ListingActive}
+	}
+
+	t.Run("Search_Success", func(t *testing.T) {
+		mockRepo := new(mocks.MockProductRepository)
+		mockPriceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		mockScrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		mockSearchRepo := new(mocks.MockSearchRepository)
+		mockLambdaClient := new(mocks.MockLambdaClient)
+		productService := NewProductServiceImpl(mockRepo, mockPriceHistoryRepo, mockScrapeRunRepo, mockSearchRepo, mockLambdaClient)
+
+		nescafe := active("cafe-nescafe", "Café Nescafé")
+		molido := active("cafe-molido", "Café Molido")
+		discontinued := models.Product{ProductID: "cafe-viejo", Name: "Café Viejo"}
+
+		var entries []models.SearchEntry
+		for _, product := range []models.Product{nescafe, molido, discontinued} {
+			entries = append(entries, search.Entries(product)...)
+		}
+		// "cafe" y "nescafe" estan en particiones distintas
+		mockSearchRepo.On("GetByPrefix", "ca").Return(entries, nil)
+		mockSearchRepo.On("GetByPrefix", "ne").Return(entries, nil)
+		mockRepo.On("GetByIDs", []string{"cafe-nescafe", "cafe-molido", "cafe-viejo"}).Return([]models.Product{discontinued, molido, nescafe}, nil)
+
+		products, err := productService.Search(context.Background(), request.SearchRequest{Query: "cafe nescafe"})
+
+		assert.NoError(t, err, "Expected no error, Search() returned an error")
+		assert.Equal(t, []response.ProductResponse{
+			{ProductID: "cafe-nescafe", Name: "Café Nescafé"},
+			{ProductID: "cafe-molido", Name: "Café Molido"},
+		}, products, "Expected active products in ranking order")
+		mockSearchRepo.AssertExpectations(t)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Search_FillsLimit", func(t *testing.T) {
+		mockRepo := new(mocks.MockProductRepository)
+		mockPriceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		mockScrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		mockSearchRepo := new(mocks.MockSearchRepository)
+		mockLambdaClient := new(mocks.MockLambdaClient)
+		productService := NewProductServiceImpl(mockRepo, mockPriceHistoryRepo, mockScrapeRunRepo, mockSearchRepo, mockLambdaClient)
+
+		var entries []models.SearchEntry
+		for _, id := range []string{"a", "b", "c"} {
+			entries = append(entries, search.Entries(active(id, "Leche"))...)
+		}
+		mockSearchRepo.On("GetByPrefix", "le").Return(entries, nil)
+		// "a" ya no existe, se lee el siguiente bloque para completar el limite
+		mockRepo.On("GetByIDs", []string{"a", "b"}).Return([]models.Product{active("b", "Leche")}, nil)
+		mockRepo.On("GetByIDs", []string{"c"}).Return([]models.Product{active("c", "Leche")}, nil)
+
+		products, err := productService.Search(context.Background(), request.SearchRequest{Query: "leche", Limit: 2})
+
+		assert.NoError(t, err, "Expected no error, Search() returned an error")
+		assert.Equal(t, []response.ProductResponse{
+			{ProductID: "b", Name: "Leche"},
+			{ProductID: "c", Name: "Leche"},
+		}, products, "Expected the limit to be filled with the next products")
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Search_NoWords", func(t *testing.T) {
+		mockRepo := new(mocks.MockProductRepository)
+		mockPriceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		mockScrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		mockSearchRepo := new(mocks.MockSearchRepository)
+		mockLambdaClient := new(mocks.MockLambdaClient)
+		productService := NewProductServiceImpl(mockRepo, mockPriceHistoryRepo, mockScrapeRunRepo, mockSearchRepo, mockLambdaClient)
+
+		products, err := productService.Search(context.Background(), request.SearchRequest{Query: "de la"})
+
+		assert.NoError(t, err, "Expected no error, Search() returned an error")
+		assert.Equal(t, []response.ProductResponse{}, products, "Expected no products")
+		mockSearchRepo.AssertNotCalled(t, "GetByPrefix", mock.Anything)
+	})
+
+	t.Run("Search_Error", func(t *testing.T) {
+		mockRepo := new(mocks.MockProductRepository)
+		mockPriceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		mockScrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		mockSearchRepo := new(mocks.MockSearchRepository)
+		mockLambdaClient := new(mocks.MockLambdaClient)
+		productService := NewProductServiceImpl(mockRepo, mockPriceHistoryRepo, mockScrapeRunRepo, mockSearchRepo, mockLambdaClient)
+
+		mockSearchRepo.On("GetByPrefix", "le").Return([]models.SearchEntry{}, assert.AnError)
+
+		products, err := productService.Search(context.Background(), request.SearchRequest{Query: "leche"})
+
+		assert.Error(t, err, "Expected an error, Search() did not return an error")
+		assert.Nil(t, products, "Expected products to be nil")
 	})
 }
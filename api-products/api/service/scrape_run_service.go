@@ -0,0 +1,12 @@
+package service
+
+import (
+	"context"
+
+	"github.com/dieg0code/shared/json/response"
+)
+
+type ScrapeRunService interface {
+	GetAll(ctx context.Context) ([]response.ScrapeRunResponse, error)
+	GetByID(ctx context.Context, runID string) (response.ScrapeRunResponse, error)
+}
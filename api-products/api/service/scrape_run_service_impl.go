@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"github.com/dieg0code/serverles-api-scraper/api/repository"
+	"github.com/dieg0code/shared/json/response"
+	"github.com/dieg0code/shared/models"
+	"github.com/sirupsen/logrus"
+)
+
+type ScrapeRunServiceImpl struct {
+	ScrapeRunRepository repository.ScrapeRunRepository
+}
+
+// GetAll implements ScrapeRunService.
+func (s *ScrapeRunServiceImpl) GetAll(ctx context.Context) ([]response.ScrapeRunResponse, error) {
+	result, err := s.ScrapeRunRepository.GetAll(ctx)
+	if err != nil {
+		logrus.WithError(err).Error("[ScrapeRunServiceImpl.GetAll] Error getting all scrape runs")
+		return nil, err
+	}
+
+	// Las ejecuciones más recientes primero
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].StartedAt > result[j].StartedAt
+	})
+
+	runs := []response.ScrapeRunResponse{}
+	for _, run := range result {
+		runs = append(runs, toScrapeRunResponse(run))
+	}
+
+	return runs, nil
+}
+
+// GetByID implements ScrapeRunService.
+func (s *ScrapeRunServiceImpl) GetByID(ctx context.Context, runID string) (response.ScrapeRunResponse, error) {
+	result, err := s.ScrapeRunRepository.GetByID(ctx, runID)
+	if err != nil {
+		logrus.WithError(err).Error("[ScrapeRunServiceImpl.GetByID] Error getting scrape run by ID")
+		return response.ScrapeRunResponse{}, err
+	}
+
+	return toScrapeRunResponse(result), nil
+}
+
+func toScrapeRunResponse(run models.ScrapeRun) response.ScrapeRunResponse {
+	categories := []response.CategoryRunResponse{}
+	for _, category := range run.Categories {
+		var errors []response.ScrapeErrorResponse
+		for _, scrapeErr := range category.Errors {
+			errors = append(errors, response.ScrapeErrorResponse{
+				Category:   scrapeErr.Category,
+				PageURL:    scrapeErr.PageURL,
+				StatusCode: scrapeErr.StatusCode,
+				Error:      scrapeErr.Error,
+			})
+		}
+
+		categories = append(categories, response.CategoryRunResponse{
+			Store:       category.Store,
+			Category:    category.Category,
+			Products:    category.Products,
+			Pages:       category.Pages,
+			FailedPages: category.FailedPages,
+			Errors:      errors,
+		})
+	}
+
+	return response.ScrapeRunResponse{
+		RunID:       run.RunID,
+		TriggeredBy: run.TriggeredBy,
+		StartedAt:   run.StartedAt,
+		FinishedAt:  run.FinishedAt,
+		Status:      run.Status,
+		Error:       run.Error,
+		Products:    run.Products,
+		Pages:       run.Pages,
+		FailedPages: run.FailedPages,
+		Categories:  categories,
+	}
+}
+
+func NewScrapeRunServiceImpl(scrapeRunRepository repository.ScrapeRunRepository) ScrapeRunService {
+	return &ScrapeRunServiceImpl{
+		ScrapeRunRepository: scrapeRunRepository,
+	}
+}
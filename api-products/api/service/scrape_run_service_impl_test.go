@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dieg0code/shared/json/response"
+	"github.com/dieg0code/shared/mocks"
+	"github.com/dieg0code/shared/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScrapeRunService_GetAll(t *testing.T) {
+	t.Run("GetAll_Success", func(t *testing.T) {
+		mockRepo := new(mocks.MockScrapeRunRepository)
+		scrapeRunService := NewScrapeRunServiceImpl(mockRepo)
+
+		mockRepo.On("GetAll").Return([]models.ScrapeRun{
+			{
+				RunID:      "old-run",
+				StartedAt:  "2024-08-19T10:00:00Z",
+				Status:     models.ScrapeRunSucceeded,
+				Categories: []models.CategoryRun{{Category: "despensa", Products: 120}},
+			},
+			{
+				RunID:     "new-run",
+				StartedAt: "2024-08-20T10:00:00Z",
+				Status:    models.ScrapeRunRunning,
+			},
+		}, nil)
+
+		runs, err := scrapeRunService.GetAll(context.Background())
+
+		assert.NoError(t, err, "Expected no error, GetAll() returned an error")
+		assert.Equal(t, []response.ScrapeRunResponse{
+			{
+				RunID:      "new-run",
+				StartedAt:  "2024-08-20T10:00:00Z",
+				Status:     models.ScrapeRunRunning,
+				Categories: []response.CategoryRunResponse{},
+			},
+			{
+				RunID:      "old-run",
+				StartedAt:  "2024-08-19T10:00:00Z",
+				Status:     models.ScrapeRunSucceeded,
+				Categories: []response.CategoryRunResponse{{Category: "despensa", Products: 120}},
+			},
+		}, runs, "Expected runs sorted by start time, newest first")
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("GetAll_Error", func(t *testing.T) {
+		mockRepo := new(mocks.MockScrapeRunRepository)
+		scrapeRunService := NewScrapeRunServiceImpl(mockRepo)
+
+		mockRepo.On("GetAll").Return([]models.ScrapeRun{}, assert.AnError)
+
+		runs, err := scrapeRunService.GetAll(context.Background())
+
+		assert.Error(t, err, "Expected error getting all scrape runs")
+		assert.Nil(t, runs, "Expected runs to be nil")
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestScrapeRunService_GetByID(t *testing.T) {
+	t.Run("GetByID_Success", func(t *testing.T) {
+		mockRepo := new(mocks.MockScrapeRunRepository)
+		scrapeRunService := NewScrapeRunServiceImpl(mockRepo)
+
+		mockRepo.On("GetByID", "run-id").Return(models.ScrapeRun{
+			RunID:       "run-id",
+			TriggeredBy: "user-id",
+			StartedAt:   "2024-08-20T10:00:00Z",
+			FinishedAt:  "2024-08-20T10:03:00Z",
+			Status:      models.ScrapeRunFailed,
+			Error:       "error scraping data",
+			Products:    0,
+			Pages:       1,
+			FailedPages: 1,
+			Categories: []models.CategoryRun{{
+				Category:    "lacteos",
+				Pages:       1,
+				FailedPages: 1,
+				Errors: []models.ScrapeError{
+					{Category: "lacteos", PageURL: "https://cugat.cl/categoria-producto/lacteos/page/1/", StatusCode: 500, Error: "Internal Server Error"},
+				},
+			}},
+		}, nil)
+
+		run, err := scrapeRunService.GetByID(context.Background(), "run-id")
+
+		assert.NoError(t, err, "Expected no error, GetByID() returned an error")
+		assert.Equal(t, response.ScrapeRunResponse{
+			RunID:       "run-id",
+			TriggeredBy: "user-id",
+			StartedAt:   "2024-08-20T10:00:00Z",
+			FinishedAt:  "2024-08-20T10:03:00Z",
+			Status:      models.ScrapeRunFailed,
+			Error:       "error scraping data",
+			Products:    0,
+			Pages:       1,
+			FailedPages: 1,
+			Categories: []response.CategoryRunResponse{{
+				Category:    "lacteos",
+				Pages:       1,
+				FailedPages: 1,
+				Errors: []response.ScrapeErrorResponse{
+					{Category: "lacteos", PageURL: "https://cugat.cl/categoria-producto/lacteos/page/1/", StatusCode: 500, Error: "Internal Server Error"},
+				},
+			}},
+		}, run, "Expected run to match")
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("GetByID_Error", func(t *testing.T) {
+		mockRepo := new(mocks.MockScrapeRunRepository)
+		scrapeRunService := NewScrapeRunServiceImpl(mockRepo)
+
+		mockRepo.On("GetByID", "run-id").Return(models.ScrapeRun{}, assert.AnError)
+
+		run, err := scrapeRunService.GetByID(context.Background(), "run-id")
+
+		assert.Error(t, err, "Expected error getting scrape run by ID")
+		assert.Equal(t, response.ScrapeRunResponse{}, run, "Expected run to be empty")
+		mockRepo.AssertExpectations(t)
+	})
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/dieg0code/serverles-api-scraper/api/router"
 	"github.com/dieg0code/serverles-api-scraper/api/service"
 	"github.com/dieg0code/shared/db"
+	"github.com/dieg0code/shared/problem"
 	"github.com/sirupsen/logrus"
 )
 
@@ -23,12 +24,20 @@ func init() {
 
 	region := "sa-east-1"
 	tableName := "Products"
+	priceHistoryTableName := "PriceHistory"
+	scrapeRunTableName := "ScrapeRuns"
+	categoryTableName := "Categories"
+	searchIndexTableName := "SearchIndex"
 
 	// Instance DynamoDB
 	db := db.NewDynamoDB(region)
 
 	// Instance repository
 	productRepo := repository.NewProductRepositoryImpl(db, tableName)
+	priceHistoryRepo := repository.NewPriceHistoryRepositoryImpl(db, priceHistoryTableName)
+	scrapeRunRepo := repository.NewScrapeRunRepositoryImpl(db, scrapeRunTableName)
+	categoryRepo := repository.NewCategoryRepositoryImpl(db, categoryTableName)
+	searchRepo := repository.NewSearchRepositoryImpl(db, searchIndexTableName)
 
 	// Crear una nueva sesión de AWS
 	sess, err := session.NewSession(&aws.Config{
@@ -42,13 +51,17 @@ func init() {
 	lambdaClient := lambdaClient.New(sess)
 
 	// Instance service
-	productService := service.NewProductServiceImpl(productRepo, lambdaClient)
+	productService := service.NewProductServiceImpl(productRepo, priceHistoryRepo, scrapeRunRepo, searchRepo, lambdaClient)
+	scrapeRunService := service.NewScrapeRunServiceImpl(scrapeRunRepo)
+	categoryService := service.NewCategoryServiceImpl(categoryRepo, productRepo)
 
 	// Instance controller
 	productController := controller.NewProductControllerImpl(productService)
+	scrapeRunController := controller.NewScrapeRunControllerImpl(scrapeRunService)
+	categoryController := controller.NewCategoryControllerImpl(categoryService)
 
 	// Instance router
-	r = router.NewRouter(productController)
+	r = router.NewRouter(productController, scrapeRunController, categoryController)
 	r.InitRoutes()
 
 	logrus.Info("Serverless API scraper initialized Successfully")
@@ -59,10 +72,8 @@ func Handler(ctx context.Context, req events.APIGatewayProxyRequest) (events.API
 	response, err := r.Handler(ctx, req)
 	if err != nil {
 		logrus.Error("Error handling request:", err)
-		return events.APIGatewayProxyResponse{
-			StatusCode: 500,
-			Body:       `{"error": "Internal Server Error"}`,
-		}, err
+		// Sin error para que API Gateway entregue el problem y no un 502
+		return problem.APIGatewayResponse(err, "Internal Server Error", req.RequestContext.RequestID), nil
 	}
 	logrus.Info("Request handled successfully")
 	return response, nil
@@ -6,6 +6,7 @@ import (
 	"github.com/dieg0code/api-users/services"
 	"github.com/dieg0code/shared/json/request"
 	"github.com/dieg0code/shared/json/response"
+	"github.com/dieg0code/shared/problem"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
@@ -21,28 +22,14 @@ func (u *UserControllerImpl) LogInUser(c *gin.Context) {
 	err := c.ShouldBindJSON(&loginRequest)
 	if err != nil {
 		logrus.WithError(err).Error("[UserControllerImpl.LogInUser] Error binding JSON")
-		errorResponse := response.BaseResponse{
-			Code:    400,
-			Status:  "error",
-			Message: "Invalid request body",
-			Data:    nil,
-		}
-
-		c.JSON(400, errorResponse)
+		problem.BadRequest(c, err, "Invalid request body")
 		return
 	}
 
 	loginResponse, err := u.userService.LogInUser(loginRequest)
 	if err != nil {
 		logrus.WithError(err).Error("[UserControllerImpl.LogInUser] Error logging in user")
-		errorResponse := response.BaseResponse{
-			Code:    500,
-			Status:  "error",
-			Message: "Error logging in user",
-			Data:    nil,
-		}
-
-		c.JSON(500, errorResponse)
+		problem.Render(c, err, "Error logging in user")
 		return
 	}
 
@@ -62,14 +49,7 @@ func (u *UserControllerImpl) GetAllUsers(c *gin.Context) {
 	users, err := u.userService.GetAllUsers()
 	if err != nil {
 		logrus.WithError(err).Error("[UserControllerImpl.GetAllUsers] Error getting all users")
-		errorResponse := response.BaseResponse{
-			Code:    500,
-			Status:  "error",
-			Message: "Error getting all users",
-			Data:    nil,
-		}
-
-		c.JSON(500, errorResponse)
+		problem.Render(c, err, "Error getting all users")
 		return
 	}
 
@@ -90,14 +70,7 @@ func (u *UserControllerImpl) GetUserByID(c *gin.Context) {
 	user, err := u.userService.GetUserByID(userID)
 	if err != nil {
 		logrus.WithError(err).Error("[UserControllerImpl.GetUserByID] Error getting user by ID")
-		errorResponse := response.BaseResponse{
-			Code:    500,
-			Status:  "error",
-			Message: "Error getting user by ID",
-			Data:    nil,
-		}
-
-		c.JSON(500, errorResponse)
+		problem.Render(c, err, "Error getting user by ID")
 		return
 	}
 
@@ -118,28 +91,14 @@ func (u *UserControllerImpl) RegisterUser(c *gin.Context) {
 	err := c.ShouldBindJSON(&registerUserRequest)
 	if err != nil {
 		logrus.WithError(err).Error("[UserControllerImpl.RegisterUser] Error binding JSON")
-		errorResponse := response.BaseResponse{
-			Code:    400,
-			Status:  "error",
-			Message: "Invalid request body",
-			Data:    nil,
-		}
-
-		c.JSON(400, errorResponse)
+		problem.BadRequest(c, err, "Invalid request body")
 		return
 	}
 
 	user, err := u.userService.RegisterUser(registerUserRequest)
 	if err != nil {
 		logrus.WithError(err).Error("[UserControllerImpl.RegisterUser] Error registering user")
-		errorResponse := response.BaseResponse{
-			Code:    500,
-			Status:  "error",
-			Message: "Error registering user",
-			Data:    nil,
-		}
-
-		c.JSON(500, errorResponse)
+		problem.Render(c, err, "Error registering user")
 		return
 	}
 
@@ -8,11 +8,14 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/dieg0code/shared/apperror"
 	"github.com/dieg0code/shared/json/request"
 	"github.com/dieg0code/shared/json/response"
 	"github.com/dieg0code/shared/mocks"
 	"github.com/dieg0code/shared/models"
+	"github.com/dieg0code/shared/problem"
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -76,11 +79,11 @@ func TestLogInUser(t *testing.T) {
 
 		assert.Equal(t, http.StatusBadRequest, rec.Code, "Expected status code 400")
 
-		var response response.BaseResponse
-		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		var body problem.Problem
+		err = json.Unmarshal(rec.Body.Bytes(), &body)
 		assert.NoError(t, err, "Expected no error unmarshalling response body")
-		assert.Equal(t, "error", response.Status, "Expected response status to be error")
-		assert.Equal(t, "Invalid request body", response.Message, "Expected response message to be 'Invalid request body'")
+		assert.Equal(t, "Bad Request", body.Title, "Expected response status to be Bad Request")
+		assert.Equal(t, "Invalid request body", body.Detail, "Expected response message to be 'Invalid request body'")
 
 		userService.AssertExpectations(t)
 	})
@@ -113,11 +116,46 @@ func TestLogInUser(t *testing.T) {
 
 		assert.Equal(t, http.StatusInternalServerError, rec.Code, "Expected status code 500")
 
-		var response response.BaseResponse
-		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		var body problem.Problem
+		err = json.Unmarshal(rec.Body.Bytes(), &body)
+		assert.NoError(t, err, "Expected no error unmarshalling response body")
+		assert.Equal(t, "Internal Server Error", body.Title, "Expected response status to be Internal Server Error")
+		assert.Equal(t, "Error logging in user", body.Detail, "Expected response message to be 'Error logging in user'")
+
+		userService.AssertExpectations(t)
+	})
+
+	t.Run("LogInUser_InvalidCredentials", func(t *testing.T) {
+		userService := new(mocks.MockUserService)
+		userController := NewUserControllerImpl(userService)
+
+		gin.SetMode(gin.TestMode)
+
+		router := gin.Default()
+
+		router.POST("/login", userController.LogInUser)
+
+		userService.On("LogInUser", mock.Anything).Return(response.LogInUserResponse{}, apperror.Unauthorized("invalid email or password"))
+
+		reqBody, err := json.Marshal(request.LogInUserRequest{
+			Email:    "test@test.com",
+			Password: "wrong password",
+		})
+		assert.NoError(t, err, "Expected no error marshalling request body")
+
+		req, err := http.NewRequest(http.MethodPost, "/login", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err, "Expected no error creating request")
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, "Expected status code 401")
+
+		var body problem.Problem
+		err = json.Unmarshal(rec.Body.Bytes(), &body)
 		assert.NoError(t, err, "Expected no error unmarshalling response body")
-		assert.Equal(t, "error", response.Status, "Expected response status to be error")
-		assert.Equal(t, "Error logging in user", response.Message, "Expected response message to be 'Error logging in user'")
+		assert.Equal(t, "UNAUTHORIZED", body.Code, "Expected error code to be 'UNAUTHORIZED'")
+		assert.Empty(t, rec.Header().Get("Authorization"), "Expected no token")
 
 		userService.AssertExpectations(t)
 	})
@@ -186,11 +224,11 @@ func TestGetAllUsers(t *testing.T) {
 
 		assert.Equal(t, http.StatusInternalServerError, rec.Code, "Expected status code 500")
 
-		var response response.BaseResponse
-		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		var body problem.Problem
+		err = json.Unmarshal(rec.Body.Bytes(), &body)
 		assert.NoError(t, err, "Expected no error unmarshalling response body")
-		assert.Equal(t, "error", response.Status, "Expected response status to be error")
-		assert.Equal(t, "Error getting all users", response.Message, "Expected response message to be 'Error getting all users'")
+		assert.Equal(t, "Internal Server Error", body.Title, "Expected response status to be Internal Server Error")
+		assert.Equal(t, "Error getting all users", body.Detail, "Expected response message to be 'Error getting all users'")
 
 		userService.AssertExpectations(t)
 	})
@@ -255,10 +293,40 @@ func TestGetUserByID(t *testing.T) {
 
 		assert.Equal(t, http.StatusInternalServerError, rec.Code, "Status code shoud be 500")
 
-		var response response.BaseResponse
-		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		var body problem.Problem
+		err = json.Unmarshal(rec.Body.Bytes(), &body)
+		assert.NoError(t, err, "Expected no error unmarshalling response body")
+		assert.Equal(t, "Internal Server Error", body.Title, "Expected response status to be 'Internal Server Error'")
+
+		userService.AssertExpectations(t)
+	})
+
+	t.Run("GetUserByID_NotFound", func(t *testing.T) {
+		userService := new(mocks.MockUserService)
+		userController := NewUserControllerImpl(userService)
+
+		gin.SetMode(gin.TestMode)
+
+		router := gin.Default()
+
+		router.GET("/users/:userID", userController.GetUserByID)
+
+		userService.On("GetUserByID", "missing-id").Return(response.UserResponse{}, apperror.NotFound("user not found"))
+
+		req, err := http.NewRequest(http.MethodGet, "/users/missing-id", nil)
+		assert.NoError(t, err, "Expected no error creating request")
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code, "Status code shoud be 404")
+
+		var body problem.Problem
+		err = json.Unmarshal(rec.Body.Bytes(), &body)
 		assert.NoError(t, err, "Expected no error unmarshalling response body")
-		assert.Equal(t, "error", response.Status, "Expected response status to be 'error'")
+		assert.Equal(t, "Not Found", body.Title, "Expected response status to be 'Not Found'")
+		assert.Equal(t, "user not found", body.Detail, "Expected response message to be 'user not found'")
+		assert.Equal(t, "NOT_FOUND", body.Code, "Expected error code to be 'NOT_FOUND'")
 
 		userService.AssertExpectations(t)
 	})
@@ -328,11 +396,11 @@ func TestRegisterUser(t *testing.T) {
 
 		assert.Equal(t, http.StatusBadRequest, rec.Code, "Expected status code 400")
 
-		var response response.BaseResponse
-		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		var body problem.Problem
+		err = json.Unmarshal(rec.Body.Bytes(), &body)
 		assert.NoError(t, err, "Expected no error unmarshalling response body")
-		assert.Equal(t, "error", response.Status, "Expected response status to be error")
-		assert.Equal(t, "Invalid request body", response.Message, "Expected response message to be 'Invalid request body'")
+		assert.Equal(t, "Bad Request", body.Title, "Expected response status to be Bad Request")
+		assert.Equal(t, "Invalid request body", body.Detail, "Expected response message to be 'Invalid request body'")
 
 		userService.AssertExpectations(t)
 	})
@@ -367,14 +435,96 @@ func TestRegisterUser(t *testing.T) {
 
 		assert.Equal(t, http.StatusInternalServerError, rec.Code, "Expected status code 500")
 
-		var response response.BaseResponse
-		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		var body problem.Problem
+		err = json.Unmarshal(rec.Body.Bytes(), &body)
 		assert.NoError(t, err, "Expected no error unmarshalling response body")
-		assert.Equal(t, "error", response.Status, "Expected response status to be error")
-		assert.Equal(t, "Error registering user", response.Message, "Expected response message to be 'Error registering user'")
-		assert.Nil(t, response.Data, "Expected response data to be nil")
+		assert.Equal(t, "Internal Server Error", body.Title, "Expected response status to be Internal Server Error")
+		assert.Equal(t, "Error registering user", body.Detail, "Expected response message to be 'Error registering user'")
+		assert.Equal(t, problem.ContentType, rec.Header().Get("Content-Type"), "Expected a problem+json response")
 
 		userService.AssertExpectations(t)
 	})
 
+	t.Run("RegisterUser_EmailAlreadyRegistered", func(t *testing.T) {
+		userService := new(mocks.MockUserService)
+		userController := NewUserControllerImpl(userService)
+
+		gin.SetMode(gin.TestMode)
+
+		router := gin.Default()
+
+		router.POST("/register", userController.RegisterUser)
+
+		userService.On("RegisterUser", mock.Anything).Return(models.User{}, apperror.Conflict("email already registered"))
+
+		reqBody, err := json.Marshal(request.CreateUserRequest{
+			Username: "test",
+			Email:    "test@test.com",
+			Password: "password",
+			Role:     "user",
+		})
+		assert.NoError(t, err, "Expected no error marshalling request body")
+
+		req, err := http.NewRequest(http.MethodPost, "/register", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err, "Expected no error creating request")
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusConflict, rec.Code, "Expected status code 409")
+
+		var body problem.Problem
+		err = json.Unmarshal(rec.Body.Bytes(), &body)
+		assert.NoError(t, err, "Expected no error unmarshalling response body")
+		assert.Equal(t, "CONFLICT", body.Code, "Expected error code to be 'CONFLICT'")
+		assert.Equal(t, "email already registered", body.Detail, "Expected response message to be 'email already registered'")
+
+		userService.AssertExpectations(t)
+	})
+
+	t.Run("RegisterUser_InvalidUserData", func(t *testing.T) {
+		userService := new(mocks.MockUserService)
+		userController := NewUserControllerImpl(userService)
+
+		gin.SetMode(gin.TestMode)
+
+		router := gin.Default()
+
+		router.POST("/users", userController.RegisterUser)
+
+		registerUserRequest := request.CreateUserRequest{
+			Username: "test",
+			Email:    "invalid-email",
+			Password: "password",
+			Role:     "root",
+		}
+
+		// El servicio valida el request con el validador de main.go
+		v := validator.New()
+		problem.RegisterTagNames(v)
+		validationErr := apperror.Wrap(apperror.CodeValidation, "invalid user data", v.Struct(registerUserRequest))
+		userService.On("RegisterUser", registerUserRequest).Return(models.User{}, validationErr)
+
+		reqBody, err := json.Marshal(registerUserRequest)
+		assert.NoError(t, err, "Expected no error marshalling request body")
+
+		req, err := http.NewRequest(http.MethodPost, "/users", bytes.NewBuffer(reqBody))
+		assert.NoError(t, err, "Expected no error creating request")
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code, "Expected status code 400")
+
+		var body problem.Problem
+		err = json.Unmarshal(rec.Body.Bytes(), &body)
+		assert.NoError(t, err, "Expected no error unmarshalling response body")
+		assert.Equal(t, "invalid user data", body.Detail, "Expected response detail to be 'invalid user data'")
+		assert.Equal(t, []problem.FieldError{
+			{Field: "email", Rule: "email", Message: "must be a valid email"},
+			{Field: "role", Rule: "oneof", Message: "must be one of: admin, user"},
+		}, body.Errors, "Expected one error per invalid field")
+
+		userService.AssertExpectations(t)
+	})
 }
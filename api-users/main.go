@@ -11,6 +11,7 @@ import (
 	"github.com/dieg0code/api-users/services"
 	"github.com/dieg0code/api-users/utils"
 	"github.com/dieg0code/shared/db"
+	"github.com/dieg0code/shared/problem"
 	"github.com/go-playground/validator/v10"
 	"github.com/sirupsen/logrus"
 )
@@ -30,6 +31,7 @@ func init() {
 	userRepo := repository.NewUserRepositoryImpl(db, tableName)
 
 	validator := validator.New()
+	problem.RegisterTagNames(validator)
 	passwordHaher := utils.NewPasswordHasher()
 	jwtUtils := utils.NewJWTUtils()
 
@@ -50,10 +52,8 @@ func Handler(ctx context.Context, req events.APIGatewayProxyRequest) (events.API
 	response, err := r.Handler(ctx, req)
 	if err != nil {
 		logrus.Error("Error handling request:", err)
-		return events.APIGatewayProxyResponse{
-			StatusCode: 500,
-			Body:       `{"error": "Internal Server Error"}`,
-		}, err
+		// Sin error para que API Gateway entregue el problem y no un 502
+		return problem.APIGatewayResponse(err, "Internal Server Error", req.RequestContext.RequestID), nil
 	}
 	logrus.Info("Request handled successfully")
 	return response, nil
@@ -1,12 +1,14 @@
 package repository
 
 import (
-	"errors"
+	"context"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/dieg0code/shared/apperror"
+	"github.com/dieg0code/shared/db"
 	"github.com/dieg0code/shared/models"
 	"github.com/sirupsen/logrus"
 )
@@ -32,18 +34,18 @@ func (u *UserRepositoryImpl) GetByEmail(email string) (models.User, error) {
 	result, err := u.db.Query(input)
 	if err != nil {
 		logrus.WithError(err).Error("[UserRepositoryImpl.GetByEmail] error getting user")
-		return models.User{}, errors.New("error getting user")
+		return models.User{}, apperror.Internal("error getting user")
 	}
 
 	if len(result.Items) == 0 {
-		return models.User{}, errors.New("user not found")
+		return models.User{}, apperror.NotFound("user not found")
 	}
 
 	var user models.User
 	err = dynamodbattribute.UnmarshalMap(result.Items[0], &user)
 	if err != nil {
 		logrus.WithError(err).Error("[UserRepositoryImpl.GetByEmail] error unmarshalling user")
-		return models.User{}, errors.New("error getting user")
+		return models.User{}, apperror.Internal("error getting user")
 	}
 
 	return user, nil
@@ -75,7 +77,7 @@ func (u *UserRepositoryImpl) Create(user models.User) (models.User, error) {
 	_, err := u.db.PutItem(input)
 	if err != nil {
 		logrus.WithError(err).Error("[UserRepositoryImpl.Create] error creating user")
-		return models.User{}, errors.New("error creating user")
+		return models.User{}, apperror.Internal("error creating user")
 	}
 
 	return user, nil
@@ -87,17 +89,17 @@ func (u *UserRepositoryImpl) GetAll() ([]models.User, error) {
 		TableName: &u.tableName,
 	}
 
-	result, err := u.db.Scan(input)
+	items, err := db.ScanAll(context.Background(), u.db, input)
 	if err != nil {
 		logrus.WithError(err).Error("[UserRepositoryImpl.GetAll] error getting users")
-		return nil, errors.New("error getting users")
+		return nil, apperror.Internal("error getting users")
 	}
 
 	var users []models.User
-	err = dynamodbattribute.UnmarshalListOfMaps(result.Items, &users)
+	err = dynamodbattribute.UnmarshalListOfMaps(items, &users)
 	if err != nil {
 		logrus.WithError(err).Error("[UserRepositoryImpl.GetAll] error unmarshalling users")
-		return nil, errors.New("error getting users")
+		return nil, apperror.Internal("error getting users")
 	}
 
 	return users, nil
@@ -117,18 +119,18 @@ func (u *UserRepositoryImpl) GetByID(id string) (models.User, error) {
 	result, err := u.db.GetItem(input)
 	if err != nil {
 		logrus.WithError(err).Error("[UserRepositoryImpl.GetByID] error getting user")
-		return models.User{}, errors.New("error getting user")
+		return models.User{}, apperror.Internal("error getting user")
 	}
 
 	if result.Item == nil {
-		return models.User{}, errors.New("user not found")
+		return models.User{}, apperror.NotFound("user not found")
 	}
 
 	var user models.User
 	err = dynamodbattribute.UnmarshalMap(result.Item, &user)
 	if err != nil {
 		logrus.WithError(err).Error("[UserRepositoryImpl.GetByID] error unmarshalling user")
-		return models.User{}, errors.New("error getting user")
+		return models.User{}, apperror.Internal("error getting user")
 	}
 
 	return user, nil
@@ -4,8 +4,10 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/dieg0code/shared/apperror"
 	"github.com/dieg0code/shared/mocks"
 	"github.com/dieg0code/shared/models"
 	"github.com/stretchr/testify/assert"
@@ -51,6 +53,7 @@ func TestUserRepositoryImpl_GetByEmail(t *testing.T) {
 
 		assert.Error(t, err, "Expected error getting user")
 		assert.Equal(t, "user not found", err.Error(), "Expected error message to be 'user not found'")
+		assert.Equal(t, apperror.CodeNotFound, apperror.CodeOf(err), "Expected a not found error")
 
 		mockDB.AssertExpectations(t)
 	})
@@ -156,6 +159,32 @@ func TestUserRepositoryImpl_GetAll(t *testing.T) {
 		mockDB.AssertExpectations(t)
 	})
 
+	t.Run("GetAll_Paginated", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewUserRepositoryImpl(mockDB, "test-table")
+
+		// La tabla no cabe en una sola pagina de Scan
+		mockDB.On("Scan", mock.MatchedBy(func(input *dynamodb.ScanInput) bool {
+			return input.ExclusiveStartKey == nil
+		})).Return(&dynamodb.ScanOutput{
+			Items:            []map[string]*dynamodb.AttributeValue{{"UserID": {S: aws.String("1")}}},
+			LastEvaluatedKey: map[string]*dynamodb.AttributeValue{"UserID": {S: aws.String("1")}},
+		}, nil).Once()
+		mockDB.On("Scan", mock.MatchedBy(func(input *dynamodb.ScanInput) bool {
+			return input.ExclusiveStartKey != nil && *input.ExclusiveStartKey["UserID"].S == "1"
+		})).Return(&dynamodb.ScanOutput{
+			Items: []map[string]*dynamodb.AttributeValue{{"UserID": {S: aws.String("2")}}},
+		}, nil).Once()
+
+		users, err := repo.GetAll()
+		assert.NoError(t, err, "Expected no error getting users")
+
+		assert.Len(t, users, 2, "Expected users of every page")
+		assert.Equal(t, "2", users[1].UserID, "Expected users in scan order")
+
+		mockDB.AssertExpectations(t)
+	})
+
 	t.Run("GetAll_Error", func(t *testing.T) {
 		mockDB := new(mocks.MockDynamoDB)
 		repo := NewUserRepositoryImpl(mockDB, "test-table")
@@ -210,6 +239,7 @@ func TestUserRepositoryImpl_GetByID(t *testing.T) {
 		assert.Error(t, err, "Expected error getting user")
 
 		assert.Equal(t, "user not found", err.Error(), "Expected error message to be 'user not found'")
+		assert.Equal(t, apperror.CodeNotFound, apperror.CodeOf(err), "Expected a not found error")
 
 		mockDB.AssertExpectations(t)
 	})
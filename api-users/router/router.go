@@ -6,7 +6,10 @@ import (
 	"github.com/aws/aws-lambda-go/events"
 	ginadapter "github.com/awslabs/aws-lambda-go-api-proxy/gin"
 	"github.com/dieg0code/api-users/controllers"
+	"github.com/dieg0code/shared/problem"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 )
 
 type Router struct {
@@ -23,7 +26,17 @@ func NewRouter(userController controllers.UserController) *Router {
 func (r *Router) InitRoutes() *Router {
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.New()
-	router.Use(gin.Recovery())
+	router.Use(problem.Recovery())
+
+	// Las rutas y metodos desconocidos tambien responden problem+json
+	router.HandleMethodNotAllowed = true
+	router.NoRoute(problem.NoRoute)
+	router.NoMethod(problem.NoMethod)
+
+	// Los errores de validacion nombran los campos como los envia el cliente
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		problem.RegisterTagNames(v)
+	}
 
 	router.GET("", func(ctx *gin.Context) {
 		ctx.JSON(200, gin.H{
@@ -1,10 +1,9 @@
 package services
 
 import (
-	"errors"
-
 	"github.com/dieg0code/api-users/repository"
 	"github.com/dieg0code/api-users/utils"
+	"github.com/dieg0code/shared/apperror"
 	"github.com/dieg0code/shared/json/request"
 	"github.com/dieg0code/shared/json/response"
 	"github.com/dieg0code/shared/models"
@@ -25,10 +24,15 @@ func (u *UserServiceImpl) LogInUser(logInUserReq request.LogInUserRequest) (resp
 	err := u.validator.Struct(logInUserReq)
 	if err != nil {
 		logrus.WithError(err).Error("[UserServiceImpl.LogInUser] error validating login user request")
-		return response.LogInUserResponse{}, err
+		return response.LogInUserResponse{}, apperror.Wrap(apperror.CodeValidation, "invalid login request", err)
 	}
 
+	// Un email que no existe responde lo mismo que una contraseña incorrecta
+	// para no revelar que emails estan registrados
 	user, err := u.userRepository.GetByEmail(logInUserReq.Email)
+	if apperror.Is(err, apperror.CodeNotFound) {
+		return response.LogInUserResponse{}, apperror.Unauthorized("invalid email or password")
+	}
 	if err != nil {
 		logrus.WithError(err).Error("[UserServiceImpl.LogInUser] error getting user by email")
 		return response.LogInUserResponse{}, err
@@ -37,7 +41,7 @@ func (u *UserServiceImpl) LogInUser(logInUserReq request.LogInUserRequest) (resp
 	err = u.passwordHasher.ComparePassword(user.Password, logInUserReq.Password)
 	if err != nil {
 		logrus.WithError(err).Error("[UserServiceImpl.LogInUser] error comparing password")
-		return response.LogInUserResponse{}, errors.New("invalid password")
+		return response.LogInUserResponse{}, apperror.Unauthorized("invalid email or password")
 	}
 
 	token, err := u.jwtUtils.GenerateToken(user.UserID)
@@ -90,7 +94,7 @@ func (u *UserServiceImpl) GetAllUsers() ([]response.UserResponse, error) {
 // GetUserByID implements UserService.
 func (u *UserServiceImpl) GetUserByID(id string) (response.UserResponse, error) {
 	if id == "" {
-		return response.UserResponse{}, errors.New("id is required")
+		return response.UserResponse{}, apperror.Validation("id is required")
 	}
 
 	user, err := u.userRepository.GetByID(id)
@@ -119,6 +123,15 @@ func (u *UserServiceImpl) RegisterUser(createUserReq request.CreateUserRequest)
 	err := u.validator.Struct(createUserReq)
 	if err != nil {
 		logrus.WithError(err).Error("[UserServiceImpl.RegisterUser] error validating create user request")
+		return models.User{}, apperror.Wrap(apperror.CodeValidation, "invalid user data", err)
+	}
+
+	_, err = u.userRepository.GetByEmail(createUserReq.Email)
+	if err == nil {
+		return models.User{}, apperror.Conflict("email already registered")
+	}
+	if !apperror.Is(err, apperror.CodeNotFound) {
+		logrus.WithError(err).Error("[UserServiceImpl.RegisterUser] error checking email")
 		return models.User{}, err
 	}
 
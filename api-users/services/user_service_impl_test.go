@@ -4,6 +4,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/dieg0code/shared/apperror"
 	"github.com/dieg0code/shared/json/request"
 	"github.com/dieg0code/shared/mocks"
 	"github.com/dieg0code/shared/models"
@@ -63,6 +64,7 @@ func TestUserServiceImpl_LogInUser(t *testing.T) {
 		logInUserResponse, err := userService.LogInUser(loginUserReq)
 
 		assert.Error(t, err, "Expected error login user")
+		assert.Equal(t, apperror.CodeValidation, apperror.CodeOf(err), "Expected a validation error")
 		assert.Empty(t, logInUserResponse, "Expected empty login user response")
 
 		userRepo.AssertExpectations(t)
@@ -90,6 +92,30 @@ func TestUserServiceImpl_LogInUser(t *testing.T) {
 		userRepo.AssertExpectations(t)
 	})
 
+	t.Run("LogInUser_UserNotFound", func(t *testing.T) {
+		userRepo := new(mocks.MockUserRepository)
+		passwordHasher := new(mocks.MockPasswordHasher)
+		jwtUtils := new(mocks.MockJWTUtils)
+		validator := validator.New()
+		userService := NewUserServiceImpl(userRepo, validator, passwordHasher, jwtUtils)
+
+		loginUserReq := request.LogInUserRequest{
+			Email:    "unknown@test.com",
+			Password: "password",
+		}
+
+		userRepo.On("GetByEmail", loginUserReq.Email).Return(models.User{}, apperror.NotFound("user not found"))
+
+		logInUserResponse, err := userService.LogInUser(loginUserReq)
+
+		assert.Error(t, err, "Expected error login user")
+		assert.Equal(t, apperror.CodeUnauthorized, apperror.CodeOf(err), "Expected an unauthorized error")
+		assert.Equal(t, "invalid email or password", err.Error(), "Expected the same error as a wrong password")
+		assert.Empty(t, logInUserResponse, "Expected empty login user response")
+
+		userRepo.AssertExpectations(t)
+	})
+
 	t.Run("LogInUser_ComparePasswordError", func(t *testing.T) {
 		userRepo := new(mocks.MockUserRepository)
 		passwordHasher := new(mocks.MockPasswordHasher)
@@ -116,7 +142,8 @@ func TestUserServiceImpl_LogInUser(t *testing.T) {
 		logInUserResponse, err := userService.LogInUser(loginUserReq)
 
 		assert.Error(t, err, "Expected error login user")
-		assert.Equal(t, "invalid password", err.Error(), "Expected invalid password error")
+		assert.Equal(t, "invalid email or password", err.Error(), "Expected invalid credentials error")
+		assert.Equal(t, apperror.CodeUnauthorized, apperror.CodeOf(err), "Expected an unauthorized error")
 		assert.Empty(t, logInUserResponse, "Expected empty login user response")
 
 		userRepo.AssertExpectations(t)
@@ -370,6 +397,7 @@ func TestUserServiceImpl_RegisterUser(t *testing.T) {
 
 		hashedPassword := "hashed-password"
 
+		userRepo.On("GetByEmail", createUserReq.Email).Return(models.User{}, apperror.NotFound("user not found"))
 		passwordHasher.On("HashPassword", createUserReq.Password).Return(hashedPassword, nil)
 
 		userMatcher := mock.MatchedBy(func(user models.User) bool {
@@ -414,9 +442,36 @@ func TestUserServiceImpl_RegisterUser(t *testing.T) {
 		registeredUser, err := userService.RegisterUser(createUserReq)
 
 		assert.Error(t, err, "Expected error registering user")
+		assert.Equal(t, apperror.CodeValidation, apperror.CodeOf(err), "Expected a validation error")
+		assert.Empty(t, registeredUser, "Expected empty registered user")
+
+		userRepo.AssertExpectations(t)
+	})
+
+	t.Run("RegisterUser_EmailAlreadyRegistered", func(t *testing.T) {
+		userRepo := new(mocks.MockUserRepository)
+		passwordHasher := new(mocks.MockPasswordHasher)
+		jwtUtils := new(mocks.MockJWTUtils)
+		validator := validator.New()
+		userService := NewUserServiceImpl(userRepo, validator, passwordHasher, jwtUtils)
+
+		createUserReq := request.CreateUserRequest{
+			Username: "test",
+			Email:    "test@test.com",
+			Password: "password",
+			Role:     "user",
+		}
+
+		userRepo.On("GetByEmail", createUserReq.Email).Return(models.User{UserID: "test-id", Email: createUserReq.Email}, nil)
+
+		registeredUser, err := userService.RegisterUser(createUserReq)
+
+		assert.Error(t, err, "Expected error registering user")
+		assert.Equal(t, apperror.CodeConflict, apperror.CodeOf(err), "Expected a conflict error")
 		assert.Empty(t, registeredUser, "Expected empty registered user")
 
 		userRepo.AssertExpectations(t)
+		passwordHasher.AssertNotCalled(t, "HashPassword", mock.Anything)
 	})
 
 	t.Run("RegisterUser_HashPasswordError", func(t *testing.T) {
@@ -433,6 +488,7 @@ func TestUserServiceImpl_RegisterUser(t *testing.T) {
 			Role:     "user",
 		}
 
+		userRepo.On("GetByEmail", createUserReq.Email).Return(models.User{}, apperror.NotFound("user not found"))
 		passwordHasher.On("HashPassword", createUserReq.Password).Return("", errors.New("error hashing password"))
 
 		registeredUser, err := userService.RegisterUser(createUserReq)
@@ -459,6 +515,7 @@ func TestUserServiceImpl_RegisterUser(t *testing.T) {
 
 		hashedPassword := "hashed-password"
 
+		userRepo.On("GetByEmail", createUserReq.Email).Return(models.User{}, apperror.NotFound("user not found"))
 		passwordHasher.On("HashPassword", createUserReq.Password).Return(hashedPassword, nil)
 		userRepo.On("Create", mock.Anything).Return(models.User{}, errors.New("error creating user"))
 
@@ -0,0 +1,67 @@
+// record guarda paginas de categorias de una tienda en el corpus de fixtures
+// de los tests del scraper. Uso:
+//
+//	go run ./cmd/record -store cugat.cl -category despensa -pages 2
+//
+// Despues de grabar hay que regenerar los golden files con
+// go test ./src/scraper -run TestFixtures -update
+package main
+
+import (
+	"errors"
+	"flag"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dieg0code/scraper/src/fixtures"
+	"github.com/dieg0code/scraper/src/scraper"
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	store := flag.String("store", "cugat.cl", "store to record, as in stores/*.yaml")
+	categories := flag.String("category", "", "comma separated category slugs to record")
+	pages := flag.Int("pages", 2, "maximum pages to record per category")
+	dir := flag.String("out", "src/scraper/testdata/fixtures", "fixtures directory")
+	userAgent := flag.String("user-agent", "Mozilla/5.0 (compatible; price-scraper-fixtures)", "user agent sent to the store")
+	flag.Parse()
+
+	if *categories == "" {
+		logrus.Fatal("at least one -category is required")
+	}
+
+	configs, err := scraper.DefaultSelectorConfigs()
+	if err != nil {
+		logrus.WithError(err).Fatal("Invalid stores config")
+	}
+
+	var adapter scraper.StoreAdapter
+	for _, config := range configs {
+		if config.Store == *store {
+			adapter = scraper.NewWooCommerceAdapter(config)
+		}
+	}
+	if adapter == nil {
+		logrus.Fatalf("store %s not found in stores config", *store)
+	}
+
+	recorder := fixtures.NewRecorderImpl(&http.Client{Timeout: 30 * time.Second}, *dir, *userAgent)
+
+	for _, category := range strings.Split(*categories, ",") {
+		category = strings.TrimSpace(category)
+		for page := 1; page <= *pages; page++ {
+			_, err := recorder.Record(adapter.Store(), adapter.PageURL(category, page))
+			if errors.Is(err, fixtures.ErrPageNotFound) {
+				logrus.Infof("category %s has %d pages", category, page-1)
+				break
+			}
+			if err != nil {
+				logrus.WithError(err).Fatalf("Error recording category %s page %d", category, page)
+			}
+
+			// No cargar la tienda
+			time.Sleep(time.Second)
+		}
+	}
+}
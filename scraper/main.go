@@ -2,13 +2,19 @@ package main
 
 import (
 	"context"
+	"os"
+	"time"
 
 	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	lambdaClient "github.com/aws/aws-sdk-go/service/lambda"
 	"github.com/dieg0code/scraper/src/repository"
 	"github.com/dieg0code/scraper/src/scraper"
 	"github.com/dieg0code/scraper/src/service"
 	"github.com/dieg0code/shared/db"
-	"github.com/gocolly/colly"
+	"github.com/dieg0code/shared/json/request"
+	"github.com/dieg0code/shared/models"
 	"github.com/sirupsen/logrus"
 )
 
@@ -19,27 +25,125 @@ func init() {
 
 	region := "sa-east-1"
 	tableName := "Products"
+	priceHistoryTableName := "PriceHistory"
+	scrapeRunTableName := "ScrapeRuns"
+	categoryTableName := "Categories"
+	checkpointTableName := "ScrapeCheckpoints"
+	searchIndexTableName := "SearchIndex"
 
 	db := db.NewDynamoDB(region)
 
 	scraperRepo := repository.NewScraperRepositoryImpl(db, tableName)
+	priceHistoryRepo := repository.NewPriceHistoryRepositoryImpl(db, priceHistoryTableName)
+	scrapeRunRepo := repository.NewScrapeRunRepositoryImpl(db, scrapeRunTableName)
+	categoryRepo := repository.NewCategoryRepositoryImpl(db, categoryTableName)
+	checkpointRepo := repository.NewCheckpointRepositoryImpl(db, checkpointTableName)
+	searchIndexRepo := repository.NewSearchIndexRepositoryImpl(db, searchIndexTableName)
 
-	collector := colly.NewCollector()
-	scraper := scraper.NewScraperImpl(collector)
+	// Cliente Lambda para que el scraper se vuelva a invocar y siga un run
+	// que no alcanzo a terminar
+	sess, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		logrus.WithError(err).Fatal("Error creating AWS session")
+	}
+	lambdaClient := lambdaClient.New(sess)
+
+	// Maximo de categorias y de requests simultaneos contra cada tienda; cada
+	// tienda puede cambiar su limite con rate_limit en su config
+	concurrency := 4
+	politenessDelay := 500 * time.Millisecond
+	randomDelay := 250 * time.Millisecond
+	requestTimeout := 20 * time.Second
+	// Reintentos de paginas con 429, 5xx o errores de red
+	retryPolicy := scraper.RetryPolicy{
+		MaxRetries: 3,
+		BaseDelay:  time.Second,
+		MaxDelay:   15 * time.Second,
+	}
+	userAgent := os.Getenv("SCRAPER_USER_AGENT")
+	if userAgent == "" {
+		userAgent = "Mozilla/5.0 (compatible; serverless-api-scraper)"
+	}
+	// Por defecto se respeta robots.txt; RESPECT_ROBOTS_TXT=false lo ignora
+	respectRobotsTxt := os.Getenv("RESPECT_ROBOTS_TXT") != "false"
+	// Limite de seguridad de paginas por categoria, las paginas se descubren solas
+	maxPages := 50
+	// Tiempo que un producto descontinuado sigue disponible antes de eliminarlo
+	gracePeriod := 7 * 24 * time.Hour
+	// Proporcion de paginas fallidas tolerada antes de marcar el run como fallido
+	maxFailureRatio := 0.2
+	// Categorias que no se scrapean aunque aparezcan en el menu de la tienda
+	categoryDenyList := []string{}
+	// Tiempo que se reserva antes del timeout de la Lambda para guardar lo
+	// scrapeado y el checkpoint; cubre las requests en curso y las escrituras
+	deadlineMargin := 45 * time.Second
+	// Los runs que no alcanzan a terminar siguen en una nueva invocacion de
+	// esta misma Lambda, hasta maxInvocations invocaciones por run
+	functionName := os.Getenv("AWS_LAMBDA_FUNCTION_NAME")
+	maxInvocations := 10
+
+	// Las reglas de extraccion de cada tienda vienen de stores/*.yaml; la
+	// variable STORES_CONFIG (YAML o JSON) las reemplaza sin recompilar
+	storeConfigs, err := loadStoreConfigs()
+	if err != nil {
+		logrus.WithError(err).Fatal("Invalid stores config")
+	}
+
+	collector, err := scraper.NewCollector(scraper.CollectorConfig{
+		UserAgent:        userAgent,
+		RespectRobotsTxt: respectRobotsTxt,
+		Timeout:          requestTimeout,
+		RateLimit: scraper.RateLimit{
+			Parallelism: concurrency,
+			Delay:       politenessDelay,
+			RandomDelay: randomDelay,
+		},
+	}, storeConfigs)
+	if err != nil {
+		logrus.WithError(err).Fatal("Error creating collector")
+	}
+
+	// Un scraper por tienda, cada uno con el adapter que conoce su HTML
+	var scrapers []scraper.Scraper
+	for _, storeConfig := range storeConfigs {
+		scrapers = append(scrapers, scraper.NewScraperImpl(collector, scraper.NewWooCommerceAdapter(storeConfig), retryPolicy))
+	}
+
+	scraperService = service.NewScraperServiceImpl(scrapers, scraperRepo, priceHistoryRepo, scrapeRunRepo, categoryRepo, checkpointRepo, searchIndexRepo, lambdaClient, service.Config{
+		Concurrency:        concurrency,
+		MaxPages:           maxPages,
+		GracePeriod:        gracePeriod,
+		MaxFailureRatio:    maxFailureRatio,
+		DiscoverCategories: true,
+		CategoryDenyList:   categoryDenyList,
+		DeadlineMargin:     deadlineMargin,
+		FunctionName:       functionName,
+		MaxInvocations:     maxInvocations,
+	})
+}
+
+func loadStoreConfigs() ([]scraper.SelectorConfig, error) {
+	config := os.Getenv("STORES_CONFIG")
+	if config == "" {
+		return scraper.DefaultSelectorConfigs()
+	}
 
-	scraperService = service.NewScraperServiceImpl(scraper, scraperRepo)
+	logrus.Info("Loading stores config from STORES_CONFIG")
+	return scraper.LoadSelectorConfigs([]byte(config))
 }
 
-func handleRequest(ctx context.Context) (bool, error) {
-	logrus.Info("Handling request")
-	success, err := scraperService.GetProducts()
+func handleRequest(ctx context.Context, scrapeReq request.ScrapeRequest) (models.ScrapeRun, error) {
+	logrus.WithField("run_id", scrapeReq.RunID).Info("Handling request")
+	run, err := scraperService.GetProducts(ctx, scrapeReq)
 	if err != nil {
 		logrus.WithError(err).Error("Error handling request")
-		return false, err
+		return run, err
 	}
 
 	logrus.Info("Request handled successfully")
-	return success, nil
+	return run, nil
 }
 
 func main() {
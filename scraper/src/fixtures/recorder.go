@@ -0,0 +1,90 @@
+package fixtures
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrPageNotFound indica que la pagina no existe, ej: se paso de la ultima
+// pagina de la categoria
+var ErrPageNotFound = errors.New("page not found")
+
+// Recorder guarda paginas de la tienda en un corpus de testdata. Cada pagina
+// queda en <dir>/<store>/<path>/index.html para que http.FileServer la sirva
+// en el mismo path al reproducirla.
+type Recorder interface {
+	Record(store string, pageURL string) (string, error)
+}
+
+type RecorderImpl struct {
+	client    *http.Client
+	dir       string
+	userAgent string
+}
+
+// Record implements Recorder. Devuelve el archivo donde quedo la pagina.
+func (r *RecorderImpl) Record(store string, pageURL string) (string, error) {
+	link, err := url.Parse(pageURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid page URL %q: %w", pageURL, err)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, pageURL, nil)
+	if err != nil {
+		return "", err
+	}
+	if r.userAgent != "" {
+		req.Header.Set("User-Agent", r.userAgent)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		logrus.WithError(err).Errorf("[RecorderImpl.Record] error getting %s", pageURL)
+		return "", err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", ErrPageNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %d getting %s", resp.StatusCode, pageURL)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	file := filepath.Join(r.dir, store, filepath.FromSlash(strings.Trim(link.Path, "/")), "index.html")
+	err = os.MkdirAll(filepath.Dir(file), 0o755)
+	if err != nil {
+		return "", err
+	}
+
+	err = os.WriteFile(file, body, 0o644)
+	if err != nil {
+		return "", err
+	}
+
+	logrus.Infof("[RecorderImpl.Record] saved %s to %s", pageURL, file)
+	return file, nil
+}
+
+func NewRecorderImpl(client *http.Client, dir string, userAgent string) Recorder {
+	return &RecorderImpl{
+		client:    client,
+		dir:       dir,
+		userAgent: userAgent,
+	}
+}
@@ -0,0 +1,52 @@
+package fixtures
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorderImpl_Record(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/categoria-producto/despensa/page/1/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		assert.Equal(t, "fixtures-test", r.Header.Get("User-Agent"), "Expected configured user agent")
+
+		w.Header().Set("Content-Type", "text/html")
+		_, err := w.Write([]byte(`<div class="product-small box">Arroz</div>`))
+		assert.NoError(t, err, "Expected no error writing response")
+	}))
+	defer ts.Close()
+
+	t.Run("Record_Success", func(t *testing.T) {
+		dir := t.TempDir()
+		recorder := NewRecorderImpl(ts.Client(), dir, "fixtures-test")
+
+		file, err := recorder.Record("cugat.cl", ts.URL+"/categoria-producto/despensa/page/1/")
+
+		assert.NoError(t, err, "Expected no error recording page")
+		assert.Equal(t, filepath.Join(dir, "cugat.cl", "categoria-producto", "despensa", "page", "1", "index.html"), file, "Expected page saved under its path")
+
+		content, err := os.ReadFile(file)
+		assert.NoError(t, err, "Expected recorded file to exist")
+		assert.Equal(t, `<div class="product-small box">Arroz</div>`, string(content), "Expected recorded page content")
+	})
+
+	t.Run("Record_NotFound", func(t *testing.T) {
+		dir := t.TempDir()
+		recorder := NewRecorderImpl(ts.Client(), dir, "fixtures-test")
+
+		file, err := recorder.Record("cugat.cl", ts.URL+"/categoria-producto/despensa/page/2/")
+
+		assert.True(t, errors.Is(err, ErrPageNotFound), "Expected page not found error")
+		assert.Empty(t, file, "Expected no file")
+		assert.NoDirExists(t, filepath.Join(dir, "cugat.cl"), "Expected nothing saved")
+	})
+}
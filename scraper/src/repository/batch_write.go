@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+)
+
+// maxBatchSize es el maximo de escrituras que acepta BatchWriteItem por llamada
+const maxBatchSize = 25
+
+// batchBackoff es la espera antes de reintentar los UnprocessedItems de un
+// batch; se duplica en cada intento hasta MaxDelay
+var batchBackoff = struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}{
+	MaxRetries: 5,
+	BaseDelay:  50 * time.Millisecond,
+	MaxDelay:   2 * time.Second,
+}
+
+// errUnprocessed es el error de los items que DynamoDB siguio devolviendo como
+// UnprocessedItems despues del ultimo reintento
+var errUnprocessed = errors.New("item not processed after retries")
+
+// ItemFailure es un item de un batch que no se pudo escribir
+type ItemFailure struct {
+	ID  string
+	Err error
+}
+
+// BatchError reporta los items de un batch que no se pudieron escribir. El
+// resto del batch se escribio igual.
+type BatchError struct {
+	Total    int
+	Failures []ItemFailure
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("%d of %d items could not be written", len(e.Failures), e.Total)
+}
+
+func (e *BatchError) add(id string, err error) {
+	e.Failures = append(e.Failures, ItemFailure{ID: id, Err: err})
+}
+
+// errOrNil devuelve el BatchError solo si hubo fallas
+func (e *BatchError) errOrNil() error {
+	if len(e.Failures) == 0 {
+		return nil
+	}
+	return e
+}
+
+// batchWrite escribe requests en llamadas de hasta 25 items y reintenta los
+// UnprocessedItems con backoff. idOf identifica cada item (por su Item o Key)
+// para deduplicar, porque BatchWriteItem rechaza dos escrituras de la misma
+// clave en una llamada, y para reportar las fallas en batchErr.
+func batchWrite(ctx context.Context, db dynamodbiface.DynamoDBAPI, tableName string, requests []*dynamodb.WriteRequest, idOf func(map[string]*dynamodb.AttributeValue) string, batchErr *BatchError) {
+	// Con claves repetidas gana la ultima escritura, igual que con PutItem
+	index := make(map[string]int)
+	var unique []*dynamodb.WriteRequest
+	for _, request := range requests {
+		id := idOf(writeRequestKey(request))
+		if i, ok := index[id]; ok {
+			unique[i] = request
+			continue
+		}
+		index[id] = len(unique)
+		unique = append(unique, request)
+	}
+	batchErr.Total += len(unique)
+
+	fail := func(pending []*dynamodb.WriteRequest, err error) {
+		for _, request := range pending {
+			batchErr.add(idOf(writeRequestKey(request)), err)
+		}
+	}
+
+	for start := 0; start < len(unique); start += maxBatchSize {
+		pending := unique[start:min(start+maxBatchSize, len(unique))]
+
+		for attempt := 0; len(pending) > 0; attempt++ {
+			if attempt > 0 {
+				if attempt > batchBackoff.MaxRetries {
+					fail(pending, errUnprocessed)
+					break
+				}
+				if err := sleepContext(ctx, backoffDelay(attempt)); err != nil {
+					fail(pending, err)
+					break
+				}
+			}
+
+			output, err := db.BatchWriteItemWithContext(ctx, &dynamodb.BatchWriteItemInput{
+				RequestItems: map[string][]*dynamodb.WriteRequest{tableName: pending},
+			})
+			if err != nil {
+				fail(pending, err)
+				break
+			}
+
+			pending = output.UnprocessedItems[tableName]
+		}
+	}
+}
+
+// writeRequestKey devuelve el item de un put o la clave de un delete
+func writeRequestKey(request *dynamodb.WriteRequest) map[string]*dynamodb.AttributeValue {
+	if request.PutRequest != nil {
+		return request.PutRequest.Item
+	}
+	if request.DeleteRequest != nil {
+		return request.DeleteRequest.Key
+	}
+	return nil
+}
+
+// backoffDelay es la espera antes del reintento numero attempt (desde 1)
+func backoffDelay(attempt int) time.Duration {
+	delay := batchBackoff.BaseDelay
+	for i := 1; i < attempt && delay < batchBackoff.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, batchBackoff.MaxDelay)
+}
+
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
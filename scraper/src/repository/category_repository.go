@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/dieg0code/shared/models"
+)
+
+type CategoryRepository interface {
+	Save(ctx context.Context, category models.Category) (models.Category, error)
+	GetByStore(ctx context.Context, store string) ([]models.Category, error)
+	UpdateStats(ctx context.Context, store string, slug string, productCount int, updatedAt string) error
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/dieg0code/shared/models"
+	"github.com/sirupsen/logrus"
+)
+
+type CategoryRepositoryImpl struct {
+	db        dynamodbiface.DynamoDBAPI
+	tableName string
+}
+
+// Save implements CategoryRepository. Actualiza solo los datos del menu para
+// no borrar ProductCount ni LastUpdated de la categoria.
+func (c *CategoryRepositoryImpl) Save(ctx context.Context, category models.Category) (models.Category, error) {
+	updateExpression := "SET #name = :name, LastSeen = :lastSeen"
+	values := map[string]*dynamodb.AttributeValue{
+		":name":     {S: aws.String(category.Name)},
+		":lastSeen": {S: aws.String(category.LastSeen)},
+	}
+	if category.Parent != "" {
+		updateExpression += ", Parent = :parent"
+		values[":parent"] = &dynamodb.AttributeValue{S: aws.String(category.Parent)}
+	} else {
+		updateExpression += " REMOVE Parent"
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: &c.tableName,
+		Key: map[string]*dynamodb.AttributeValue{
+			"Store": {S: aws.String(category.Store)},
+			"Slug":  {S: aws.String(category.Slug)},
+		},
+		UpdateExpression: aws.String(updateExpression),
+		// Name es una palabra reservada de DynamoDB
+		ExpressionAttributeNames:  map[string]*string{"#name": aws.String("Name")},
+		ExpressionAttributeValues: values,
+	}
+
+	_, err := c.db.UpdateItemWithContext(ctx, input)
+	if err != nil {
+		logrus.WithError(err).Error("[CategoryRepositoryImpl.Save] error saving category")
+		return models.Category{}, errors.New("error saving category")
+	}
+
+	return category, nil
+}
+
+// GetByStore implements CategoryRepository.
+func (c *CategoryRepositoryImpl) GetByStore(ctx context.Context, store string) ([]models.Category, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              &c.tableName,
+		KeyConditionExpression: aws.String("#store = :store"),
+		// Store es una palabra reservada de DynamoDB
+		ExpressionAttributeNames: map[string]*string{"#store": aws.String("Store")},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":store": {S: aws.String(store)},
+		},
+	}
+
+	// Una tienda con muchas categorias no cabe en una sola pagina de Query
+	var items []map[string]*dynamodb.AttributeValue
+	for {
+		result, err := c.db.QueryWithContext(ctx, input)
+		if err != nil {
+			logrus.WithError(err).Error("[CategoryRepositoryImpl.GetByStore] error querying categories")
+			return nil, errors.New("error getting categories")
+		}
+
+		items = append(items, result.Items...)
+		if len(result.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+
+	var categories []models.Category
+	err := dynamodbattribute.UnmarshalListOfMaps(items, &categories)
+	if err != nil {
+		logrus.WithError(err).Error("[CategoryRepositoryImpl.GetByStore] error unmarshalling categories")
+		return nil, errors.New("error getting categories")
+	}
+
+	return categories, nil
+}
+
+// UpdateStats implements CategoryRepository.
+func (c *CategoryRepositoryImpl) UpdateStats(ctx context.Context, store string, slug string, productCount int, updatedAt string) error {
+	input := &dynamodb.UpdateItemInput{
+		TableName: &c.tableName,
+		Key: map[string]*dynamodb.AttributeValue{
+			"Store": {S: aws.String(store)},
+			"Slug":  {S: aws.String(slug)},
+		},
+		UpdateExpression: aws.String("SET ProductCount = :productCount, LastUpdated = :lastUpdated"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":productCount": {N: aws.String(strconv.Itoa(productCount))},
+			":lastUpdated":  {S: aws.String(updatedAt)},
+		},
+	}
+
+	_, err := c.db.UpdateItemWithContext(ctx, input)
+	if err != nil {
+		logrus.WithError(err).Error("[CategoryRepositoryImpl.UpdateStats] error updating category stats")
+		return errors.New("error updating category stats")
+	}
+
+	return nil
+}
+
+func NewCategoryRepositoryImpl(db dynamodbiface.DynamoDBAPI, tableName string) CategoryRepository {
+	return &CategoryRepositoryImpl{
+		db:        db,
+		tableName: tableName,
+	}
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/dieg0code/shared/mocks"
+	"github.com/dieg0code/shared/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCategoryRepository_Save(t *testing.T) {
+	t.Run("Save_Success", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewCategoryRepositoryImpl(mockDB, "test-table")
+
+		category := models.Category{
+			Store:    "cugat.cl",
+			Slug:     "arroz",
+			Name:     "Arroz",
+			Parent:   "despensa",
+			LastSeen: "2024-08-20T10:00:00Z",
+		}
+
+		mockDB.On("UpdateItem", mock.MatchedBy(func(input *dynamodb.UpdateItemInput) bool {
+			return *input.Key["Store"].S == "cugat.cl" &&
+				*input.Key["Slug"].S == "arroz" &&
+				*input.ExpressionAttributeValues[":name"].S == "Arroz" &&
+				*input.ExpressionAttributeValues[":parent"].S == "despensa" &&
+				!strings.Contains(*input.UpdateExpression, "ProductCount")
+		})).Return(&dynamodb.UpdateItemOutput{}, nil)
+
+		result, err := repo.Save(context.Background(), category)
+		assert.NoError(t, err, "Expected no error saving category")
+		assert.Equal(t, category, result, "Expected category to be the same")
+
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("Save_TopLevel", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewCategoryRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("UpdateItem", mock.MatchedBy(func(input *dynamodb.UpdateItemInput) bool {
+			_, hasParent := input.ExpressionAttributeValues[":parent"]
+			return !hasParent && strings.HasSuffix(*input.UpdateExpression, "REMOVE Parent")
+		})).Return(&dynamodb.UpdateItemOutput{}, nil)
+
+		_, err := repo.Save(context.Background(), models.Category{Store: "cugat.cl", Slug: "despensa", Name: "Despensa"})
+		assert.NoError(t, err, "Expected no error saving category")
+
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("Save_Error", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewCategoryRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("UpdateItem", mock.Anything).Return(&dynamodb.UpdateItemOutput{}, assert.AnError)
+
+		result, err := repo.Save(context.Background(), models.Category{Slug: "despensa"})
+		assert.Error(t, err, "Expected error saving category")
+		assert.Equal(t, models.Category{}, result, "Expected empty category")
+
+		mockDB.AssertExpectations(t)
+	})
+}
+
+func TestCategoryRepository_GetByStore(t *testing.T) {
+	t.Run("GetByStore_Success", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewCategoryRepositoryImpl(mockDB, "test-table")
+
+		category := models.Category{Store: "cugat.cl", Slug: "despensa", Name: "Despensa", LastSeen: "2024-08-20T10:00:00Z"}
+		item, err := dynamodbattribute.MarshalMap(category)
+		assert.NoError(t, err, "Expected no error marshalling category")
+
+		mockDB.On("Query", mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+			return *input.KeyConditionExpression == "#store = :store" &&
+				*input.ExpressionAttributeNames["#store"] == "Store" &&
+				*input.ExpressionAttributeValues[":store"].S == "cugat.cl"
+		})).Return(&dynamodb.QueryOutput{
+			Items: []map[string]*dynamodb.AttributeValue{item},
+		}, nil)
+
+		categories, err := repo.GetByStore(context.Background(), "cugat.cl")
+		assert.NoError(t, err, "Expected no error getting categories")
+		assert.Equal(t, []models.Category{category}, categories, "Expected categories to match")
+
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("GetByStore_FollowsPages", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewCategoryRepositoryImpl(mockDB, "test-table")
+
+		despensa := models.Category{Store: "cugat.cl", Slug: "despensa", Name: "Despensa", LastSeen: "2024-08-20T10:00:00Z"}
+		lacteos := models.Category{Store: "cugat.cl", Slug: "lacteos", Name: "Lacteos", LastSeen: "2024-08-20T10:00:00Z"}
+		firstItem, err := dynamodbattribute.MarshalMap(despensa)
+		assert.NoError(t, err, "Expected no error marshalling category")
+		secondItem, err := dynamodbattribute.MarshalMap(lacteos)
+		assert.NoError(t, err, "Expected no error marshalling category")
+		lastKey := map[string]*dynamodb.AttributeValue{
+			"Store": {S: aws.String("cugat.cl")},
+			"Slug":  {S: aws.String("despensa")},
+		}
+
+		mockDB.On("Query", mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+			return input.ExclusiveStartKey == nil
+		})).Return(&dynamodb.QueryOutput{
+			Items:            []map[string]*dynamodb.AttributeValue{firstItem},
+			LastEvaluatedKey: lastKey,
+		}, nil).Once()
+		mockDB.On("Query", mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+			return assert.ObjectsAreEqual(lastKey, input.ExclusiveStartKey)
+		})).Return(&dynamodb.QueryOutput{
+			Items: []map[string]*dynamodb.AttributeValue{secondItem},
+		}, nil).Once()
+
+		categories, err := repo.GetByStore(context.Background(), "cugat.cl")
+		assert.NoError(t, err, "Expected no error getting categories")
+		assert.Equal(t, []models.Category{despensa, lacteos}, categories, "Expected the categories of every page")
+
+		mockDB.AssertNumberOfCalls(t, "Query", 2)
+	})
+
+	t.Run("GetByStore_Error", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewCategoryRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("Query", mock.Anything).Return(&dynamodb.QueryOutput{}, assert.AnError)
+
+		categories, err := repo.GetByStore(context.Background(), "cugat.cl")
+		assert.Error(t, err, "Expected error getting categories")
+		assert.Nil(t, categories, "Expected nil categories")
+
+		mockDB.AssertExpectations(t)
+	})
+}
+
+func TestCategoryRepository_UpdateStats(t *testing.T) {
+	t.Run("UpdateStats_Success", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewCategoryRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("UpdateItem", mock.MatchedBy(func(input *dynamodb.UpdateItemInput) bool {
+			return *input.Key["Store"].S == "cugat.cl" &&
+				*input.Key["Slug"].S == "despensa" &&
+				*input.ExpressionAttributeValues[":productCount"].N == "42" &&
+				*input.ExpressionAttributeValues[":lastUpdated"].S == "2024-08-20T10:00:00Z"
+		})).Return(&dynamodb.UpdateItemOutput{}, nil)
+
+		err := repo.UpdateStats(context.Background(), "cugat.cl", "despensa", 42, "2024-08-20T10:00:00Z")
+		assert.NoError(t, err, "Expected no error updating category stats")
+
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("UpdateStats_Error", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewCategoryRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("UpdateItem", mock.Anything).Return(&dynamodb.UpdateItemOutput{}, assert.AnError)
+
+		err := repo.UpdateStats(context.Background(), "cugat.cl", "despensa", 42, "2024-08-20T10:00:00Z")
+		assert.Error(t, err, "Expected error updating category stats")
+
+		mockDB.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/dieg0code/shared/models"
+)
+
+type CheckpointRepository interface {
+	Save(ctx context.Context, checkpoint models.ScrapeCheckpoint) (models.ScrapeCheckpoint, error)
+	GetByRunID(ctx context.Context, runID string) (models.ScrapeCheckpoint, error)
+	Delete(ctx context.Context, runID string) error
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/dieg0code/shared/models"
+	"github.com/sirupsen/logrus"
+)
+
+type CheckpointRepositoryImpl struct {
+	db        dynamodbiface.DynamoDBAPI
+	tableName string
+}
+
+// Save implements CheckpointRepository. Cada run tiene un solo checkpoint,
+// guardar uno nuevo reemplaza al anterior.
+func (c *CheckpointRepositoryImpl) Save(ctx context.Context, checkpoint models.ScrapeCheckpoint) (models.ScrapeCheckpoint, error) {
+	item, err := dynamodbattribute.MarshalMap(checkpoint)
+	if err != nil {
+		logrus.WithError(err).Error("[CheckpointRepositoryImpl.Save] error marshalling checkpoint")
+		return models.ScrapeCheckpoint{}, errors.New("error saving checkpoint")
+	}
+
+	input := &dynamodb.PutItemInput{
+		TableName: &c.tableName,
+		Item:      item,
+	}
+
+	_, err = c.db.PutItemWithContext(ctx, input)
+	if err != nil {
+		logrus.WithError(err).Error("[CheckpointRepositoryImpl.Save] error saving checkpoint")
+		return models.ScrapeCheckpoint{}, errors.New("error saving checkpoint")
+	}
+
+	return checkpoint, nil
+}
+
+// GetByRunID implements CheckpointRepository.
+func (c *CheckpointRepositoryImpl) GetByRunID(ctx context.Context, runID string) (models.ScrapeCheckpoint, error) {
+	input := &dynamodb.GetItemInput{
+		TableName: &c.tableName,
+		Key: map[string]*dynamodb.AttributeValue{
+			"RunID": {
+				S: aws.String(runID),
+			},
+		},
+	}
+
+	result, err := c.db.GetItemWithContext(ctx, input)
+	if err != nil {
+		logrus.WithError(err).Error("[CheckpointRepositoryImpl.GetByRunID] error getting checkpoint")
+		return models.ScrapeCheckpoint{}, errors.New("error getting checkpoint")
+	}
+
+	if result.Item == nil {
+		return models.ScrapeCheckpoint{}, errors.New("checkpoint not found")
+	}
+
+	var checkpoint models.ScrapeCheckpoint
+	err = dynamodbattribute.UnmarshalMap(result.Item, &checkpoint)
+	if err != nil {
+		logrus.WithError(err).Error("[CheckpointRepositoryImpl.GetByRunID] error unmarshalling checkpoint")
+		return models.ScrapeCheckpoint{}, errors.New("error getting checkpoint")
+	}
+
+	return checkpoint, nil
+}
+
+// Delete implements CheckpointRepository.
+func (c *CheckpointRepositoryImpl) Delete(ctx context.Context, runID string) error {
+	input := &dynamodb.DeleteItemInput{
+		TableName: &c.tableName,
+		Key: map[string]*dynamodb.AttributeValue{
+			"RunID": {
+				S: aws.String(runID),
+			},
+		},
+	}
+
+	_, err := c.db.DeleteItemWithContext(ctx, input)
+	if err != nil {
+		logrus.WithError(err).Error("[CheckpointRepositoryImpl.Delete] error deleting checkpoint")
+		return errors.New("error deleting checkpoint")
+	}
+
+	return nil
+}
+
+func NewCheckpointRepositoryImpl(db dynamodbiface.DynamoDBAPI, tableName string) CheckpointRepository {
+	return &CheckpointRepositoryImpl{
+		db:        db,
+		tableName: tableName,
+	}
+}
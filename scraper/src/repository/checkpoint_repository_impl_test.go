@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/dieg0code/shared/mocks"
+	"github.com/dieg0code/shared/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCheckpointRepository_Save(t *testing.T) {
+	t.Run("Save_Success", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewCheckpointRepositoryImpl(mockDB, "test-table")
+
+		checkpoint := models.ScrapeCheckpoint{
+			RunID:   "run-id",
+			SavedAt: "2024-08-20T10:00:00Z",
+			Pending: []models.CategoryCursor{
+				{Store: "cugat.cl", Category: "despensa", NextPage: 3, MaxPage: 10},
+			},
+		}
+
+		mockDB.On("PutItem", mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+			pending := input.Item["Pending"].L
+			return *input.Item["RunID"].S == "run-id" &&
+				len(pending) == 1 &&
+				*pending[0].M["NextPage"].N == "3"
+		})).Return(&dynamodb.PutItemOutput{}, nil)
+
+		result, err := repo.Save(context.Background(), checkpoint)
+		assert.NoError(t, err, "Expected no error saving checkpoint")
+		assert.Equal(t, checkpoint, result, "Expected checkpoint to be the same")
+
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("Save_Error", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewCheckpointRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("PutItem", mock.Anything).Return(&dynamodb.PutItemOutput{}, assert.AnError)
+
+		result, err := repo.Save(context.Background(), models.ScrapeCheckpoint{RunID: "run-id"})
+		assert.Error(t, err, "Expected error saving checkpoint")
+		assert.Equal(t, models.ScrapeCheckpoint{}, result, "Expected empty checkpoint")
+
+		mockDB.AssertExpectations(t)
+	})
+}
+
+func TestCheckpointRepository_GetByRunID(t *testing.T) {
+	t.Run("GetByRunID_Success", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewCheckpointRepositoryImpl(mockDB, "test-table")
+
+		checkpoint := models.ScrapeCheckpoint{
+			RunID:       "run-id",
+			SavedAt:     "2024-08-20T10:00:00Z",
+			Invocations: 2,
+			Pending: []models.CategoryCursor{
+				{Store: "cugat.cl", Category: "despensa", NextPage: 3},
+			},
+		}
+		item, err := dynamodbattribute.MarshalMap(checkpoint)
+		assert.NoError(t, err, "Expected no error marshalling checkpoint")
+
+		mockDB.On("GetItem", mock.MatchedBy(func(input *dynamodb.GetItemInput) bool {
+			return *input.Key["RunID"].S == "run-id"
+		})).Return(&dynamodb.GetItemOutput{Item: item}, nil)
+
+		result, err := repo.GetByRunID(context.Background(), "run-id")
+		assert.NoError(t, err, "Expected no error getting checkpoint")
+		assert.Equal(t, checkpoint, result, "Expected checkpoint to match")
+
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("GetByRunID_NotFound", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewCheckpointRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("GetItem", mock.Anything).Return(&dynamodb.GetItemOutput{}, nil)
+
+		result, err := repo.GetByRunID(context.Background(), "run-id")
+		assert.EqualError(t, err, "checkpoint not found", "Expected not found error")
+		assert.Equal(t, models.ScrapeCheckpoint{}, result, "Expected empty checkpoint")
+
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("GetByRunID_Error", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewCheckpointRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("GetItem", mock.Anything).Return(&dynamodb.GetItemOutput{}, assert.AnError)
+
+		_, err := repo.GetByRunID(context.Background(), "run-id")
+		assert.Error(t, err, "Expected error getting checkpoint")
+
+		mockDB.AssertExpectations(t)
+	})
+}
+
+func TestCheckpointRepository_Delete(t *testing.T) {
+	t.Run("Delete_Success", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewCheckpointRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("DeleteItem", mock.MatchedBy(func(input *dynamodb.DeleteItemInput) bool {
+			return *input.Key["RunID"].S == "run-id"
+		})).Return(&dynamodb.DeleteItemOutput{}, nil)
+
+		err := repo.Delete(context.Background(), "run-id")
+		assert.NoError(t, err, "Expected no error deleting checkpoint")
+
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("Delete_Error", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewCheckpointRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("DeleteItem", mock.Anything).Return(&dynamodb.DeleteItemOutput{}, assert.AnError)
+
+		err := repo.Delete(context.Background(), "run-id")
+		assert.Error(t, err, "Expected error deleting checkpoint")
+
+		mockDB.AssertExpectations(t)
+	})
+}
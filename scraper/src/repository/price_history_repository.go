@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/dieg0code/shared/models"
+)
+
+type PriceHistoryRepository interface {
+	Create(ctx context.Context, observation models.PriceObservation) (models.PriceObservation, error)
+	CreateMany(ctx context.Context, observations []models.PriceObservation) error
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/dieg0code/shared/models"
+	"github.com/sirupsen/logrus"
+)
+
+type PriceHistoryRepositoryImpl struct {
+	db        dynamodbiface.DynamoDBAPI
+	tableName string
+}
+
+// Create implements PriceHistoryRepository.
+func (p *PriceHistoryRepositoryImpl) Create(ctx context.Context, observation models.PriceObservation) (models.PriceObservation, error) {
+	// La clave es ProductID + Date, si el scraper corre dos veces el mismo dia
+	// la observacion del dia se sobrescribe con el ultimo precio
+	input := &dynamodb.PutItemInput{
+		TableName: &p.tableName,
+		Item:      observationItem(observation),
+	}
+
+	_, err := p.db.PutItemWithContext(ctx, input)
+	if err != nil {
+		logrus.WithError(err).Error("[PriceHistoryRepositoryImpl.Create] error creating price observation")
+		return models.PriceObservation{}, errors.New("error creating price observation")
+	}
+
+	return observation, nil
+}
+
+// CreateMany implements PriceHistoryRepository. Escribe las observaciones en
+// batches de 25; si algunas no se pudieron escribir devuelve un *BatchError
+// con sus ids (ProductID#Date).
+func (p *PriceHistoryRepositoryImpl) CreateMany(ctx context.Context, observations []models.PriceObservation) error {
+	requests := make([]*dynamodb.WriteRequest, 0, len(observations))
+	for _, observation := range observations {
+		requests = append(requests, &dynamodb.WriteRequest{
+			PutRequest: &dynamodb.PutRequest{Item: observationItem(observation)},
+		})
+	}
+
+	batchErr := &BatchError{}
+	batchWrite(ctx, p.db, p.tableName, requests, observationKey, batchErr)
+	return batchErr.errOrNil()
+}
+
+func observationItem(observation models.PriceObservation) map[string]*dynamodb.AttributeValue {
+	return map[string]*dynamodb.AttributeValue{
+		"ProductID": {
+			S: aws.String(observation.ProductID),
+		},
+		"Date": {
+			S: aws.String(observation.Date),
+		},
+		"OriginalPrice": {
+			N: aws.String(fmt.Sprintf("%d", observation.OriginalPrice)),
+		},
+		"DiscountedPrice": {
+			N: aws.String(fmt.Sprintf("%d", observation.DiscountedPrice)),
+		},
+	}
+}
+
+// observationKey es el id con que se reporta una observacion en un batch
+func observationKey(item map[string]*dynamodb.AttributeValue) string {
+	return aws.StringValue(item["ProductID"].S) + "#" + aws.StringValue(item["Date"].S)
+}
+
+func NewPriceHistoryRepositoryImpl(db dynamodbiface.DynamoDBAPI, tableName string) PriceHistoryRepository {
+	return &PriceHistoryRepositoryImpl{
+		db:        db,
+		tableName: tableName,
+	}
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/dieg0code/shared/mocks"
+	"github.com/dieg0code/shared/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPriceHistoryRepository_Create(t *testing.T) {
+	t.Run("Create_Success", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewPriceHistoryRepositoryImpl(mockDB, "test-table")
+
+		observation := models.PriceObservation{
+			ProductID:       "test-id",
+			Date:            "2024-08-20",
+			OriginalPrice:   100,
+			DiscountedPrice: 90,
+		}
+
+		mockDB.On("PutItem", mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+			return *input.Item["ProductID"].S == "test-id" &&
+				*input.Item["Date"].S == "2024-08-20" &&
+				*input.Item["OriginalPrice"].N == "100" &&
+				*input.Item["DiscountedPrice"].N == "90"
+		})).Return(&dynamodb.PutItemOutput{}, nil)
+
+		result, err := repo.Create(context.Background(), observation)
+		assert.NoError(t, err, "Expected no error creating price observation")
+		assert.Equal(t, observation, result, "Expected observation to be the same")
+
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("Create_Error", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewPriceHistoryRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("PutItem", mock.Anything).Return(&dynamodb.PutItemOutput{}, assert.AnError)
+
+		result, err := repo.Create(context.Background(), models.PriceObservation{ProductID: "test-id", Date: "2024-08-20"})
+		assert.Error(t, err, "Expected error creating price observation")
+		assert.Equal(t, models.PriceObservation{}, result, "Expected empty observation")
+
+		mockDB.AssertExpectations(t)
+	})
+}
+
+func TestPriceHistoryRepository_CreateMany(t *testing.T) {
+	t.Run("CreateMany_Success", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewPriceHistoryRepositoryImpl(mockDB, "test-table")
+
+		observations := []models.PriceObservation{
+			{ProductID: "test-id", Date: "2024-08-20", OriginalPrice: 100, DiscountedPrice: 90},
+			{ProductID: "other-id", Date: "2024-08-20", OriginalPrice: 50, DiscountedPrice: 50},
+		}
+
+		mockDB.On("BatchWriteItem", mock.MatchedBy(func(input *dynamodb.BatchWriteItemInput) bool {
+			requests := input.RequestItems["test-table"]
+			return len(requests) == 2 &&
+				*requests[0].PutRequest.Item["ProductID"].S == "test-id" &&
+				*requests[0].PutRequest.Item["OriginalPrice"].N == "100" &&
+				*requests[1].PutRequest.Item["ProductID"].S == "other-id"
+		})).Return(&dynamodb.BatchWriteItemOutput{}, nil)
+
+		err := repo.CreateMany(context.Background(), observations)
+		assert.NoError(t, err, "Expected no error creating price observations")
+
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("CreateMany_Error", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewPriceHistoryRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("BatchWriteItem", mock.Anything).Return(&dynamodb.BatchWriteItemOutput{}, assert.AnError)
+
+		err := repo.CreateMany(context.Background(), []models.PriceObservation{{ProductID: "test-id", Date: "2024-08-20"}})
+
+		var batchErr *BatchError
+		assert.ErrorAs(t, err, &batchErr, "Expected a batch error")
+		assert.Equal(t, []ItemFailure{{ID: "test-id#2024-08-20", Err: assert.AnError}}, batchErr.Failures, "Expected the observation to be reported by its key")
+	})
+}
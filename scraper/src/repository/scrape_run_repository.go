@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/dieg0code/shared/models"
+)
+
+type ScrapeRunRepository interface {
+	Save(ctx context.Context, run models.ScrapeRun) (models.ScrapeRun, error)
+	GetByID(ctx context.Context, runID string) (models.ScrapeRun, error)
+}
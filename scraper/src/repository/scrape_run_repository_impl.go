@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/dieg0code/shared/models"
+	"github.com/sirupsen/logrus"
+)
+
+type ScrapeRunRepositoryImpl struct {
+	db        dynamodbiface.DynamoDBAPI
+	tableName string
+}
+
+// Save implements ScrapeRunRepository.
+func (s *ScrapeRunRepositoryImpl) Save(ctx context.Context, run models.ScrapeRun) (models.ScrapeRun, error) {
+	item, err := dynamodbattribute.MarshalMap(run)
+	if err != nil {
+		logrus.WithError(err).Error("[ScrapeRunRepositoryImpl.Save] error marshalling scrape run")
+		return models.ScrapeRun{}, errors.New("error saving scrape run")
+	}
+
+	input := &dynamodb.PutItemInput{
+		TableName: &s.tableName,
+		Item:      item,
+	}
+
+	_, err = s.db.PutItemWithContext(ctx, input)
+	if err != nil {
+		logrus.WithError(err).Error("[ScrapeRunRepositoryImpl.Save] error saving scrape run")
+		return models.ScrapeRun{}, errors.New("error saving scrape run")
+	}
+
+	return run, nil
+}
+
+// GetByID implements ScrapeRunRepository.
+func (s *ScrapeRunRepositoryImpl) GetByID(ctx context.Context, runID string) (models.ScrapeRun, error) {
+	input := &dynamodb.GetItemInput{
+		TableName: &s.tableName,
+		Key: map[string]*dynamodb.AttributeValue{
+			"RunID": {
+				S: aws.String(runID),
+			},
+		},
+	}
+
+	result, err := s.db.GetItemWithContext(ctx, input)
+	if err != nil {
+		logrus.WithError(err).Error("[ScrapeRunRepositoryImpl.GetByID] error getting scrape run")
+		return models.ScrapeRun{}, errors.New("error getting scrape run")
+	}
+
+	if result.Item == nil {
+		return models.ScrapeRun{}, errors.New("scrape run not found")
+	}
+
+	var run models.ScrapeRun
+	err = dynamodbattribute.UnmarshalMap(result.Item, &run)
+	if err != nil {
+		logrus.WithError(err).Error("[ScrapeRunRepositoryImpl.GetByID] error unmarshalling scrape run")
+		return models.ScrapeRun{}, errors.New("error getting scrape run")
+	}
+
+	return run, nil
+}
+
+func NewScrapeRunRepositoryImpl(db dynamodbiface.DynamoDBAPI, tableName string) ScrapeRunRepository {
+	return &ScrapeRunRepositoryImpl{
+		db:        db,
+		tableName: tableName,
+	}
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/dieg0code/shared/mocks"
+	"github.com/dieg0code/shared/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestScrapeRunRepository_Save(t *testing.T) {
+	t.Run("Save_Success", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewScrapeRunRepositoryImpl(mockDB, "test-table")
+
+		run := models.ScrapeRun{
+			RunID:       "run-id",
+			TriggeredBy: "user-id",
+			StartedAt:   "2024-08-20T10:00:00Z",
+			Status:      models.ScrapeRunRunning,
+			Categories: []models.CategoryRun{
+				{Category: "despensa", Products: 10},
+			},
+		}
+
+		mockDB.On("PutItem", mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+			return *input.Item["RunID"].S == "run-id" &&
+				*input.Item["Status"].S == models.ScrapeRunRunning &&
+				len(input.Item["Categories"].L) == 1
+		})).Return(&dynamodb.PutItemOutput{}, nil)
+
+		result, err := repo.Save(context.Background(), run)
+		assert.NoError(t, err, "Expected no error saving scrape run")
+		assert.Equal(t, run, result, "Expected scrape run to be the same")
+
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("Save_Error", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewScrapeRunRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("PutItem", mock.Anything).Return(&dynamodb.PutItemOutput{}, assert.AnError)
+
+		result, err := repo.Save(context.Background(), models.ScrapeRun{RunID: "run-id"})
+		assert.Error(t, err, "Expected error saving scrape run")
+		assert.Equal(t, models.ScrapeRun{}, result, "Expected empty scrape run")
+
+		mockDB.AssertExpectations(t)
+	})
+}
+
+func TestScrapeRunRepository_GetByID(t *testing.T) {
+	t.Run("GetByID_Success", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewScrapeRunRepositoryImpl(mockDB, "test-table")
+
+		run := models.ScrapeRun{
+			RunID:      "run-id",
+			Status:     models.ScrapeRunInterrupted,
+			Products:   10,
+			Categories: []models.CategoryRun{{Store: "cugat.cl", Category: "despensa", Products: 10}},
+		}
+		item, err := dynamodbattribute.MarshalMap(run)
+		assert.NoError(t, err, "Expected no error marshalling scrape run")
+
+		mockDB.On("GetItem", mock.MatchedBy(func(input *dynamodb.GetItemInput) bool {
+			return *input.Key["RunID"].S == "run-id"
+		})).Return(&dynamodb.GetItemOutput{Item: item}, nil)
+
+		result, err := repo.GetByID(context.Background(), "run-id")
+		assert.NoError(t, err, "Expected no error getting scrape run")
+		assert.Equal(t, run, result, "Expected scrape run to match")
+
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("GetByID_NotFound", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewScrapeRunRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("GetItem", mock.Anything).Return(&dynamodb.GetItemOutput{}, nil)
+
+		result, err := repo.GetByID(context.Background(), "run-id")
+		assert.EqualError(t, err, "scrape run not found", "Expected not found error")
+		assert.Equal(t, models.ScrapeRun{}, result, "Expected empty scrape run")
+
+		mockDB.AssertExpectations(t)
+	})
+}
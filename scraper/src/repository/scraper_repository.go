@@ -1,8 +1,17 @@
 package repository
 
-import "github.com/dieg0code/shared/models"
+import (
+	"context"
+
+	"github.com/dieg0code/shared/models"
+)
 
 type ScraperRepository interface {
-	Create(product models.Product) (models.Product, error)
-	DeleteAll() error
+	Create(ctx context.Context, product models.Product) (models.Product, error)
+	CreateMany(ctx context.Context, products []models.Product) error
+	GetAll(ctx context.Context) ([]models.Product, error)
+	MarkDiscontinued(ctx context.Context, productID string, discontinuedAt string) error
+	Delete(ctx context.Context, productID string) error
+	DeleteMany(ctx context.Context, productIDs []string) error
+	DeleteAll(ctx context.Context) error
 }
@@ -1,12 +1,14 @@
 package repository
 
 import (
+	"context"
 	"errors"
-	"fmt"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/dieg0code/shared/db"
 	"github.com/dieg0code/shared/models"
 	"github.com/sirupsen/logrus"
 )
@@ -17,33 +19,21 @@ type ScraperRepositoryImpl struct {
 }
 
 // Create implements ScraperRepository.
-func (s *ScraperRepositoryImpl) Create(product models.Product) (models.Product, error) {
+func (s *ScraperRepositoryImpl) Create(ctx context.Context, product models.Product) (models.Product, error) {
+	// DiscontinuedAt va vacio al hacer upsert, asi un producto que reaparece
+	// deja de estar descontinuado
+	item, err := dynamodbattribute.MarshalMap(product)
+	if err != nil {
+		logrus.WithError(err).Error("[ProductRepositoryImpl.Create] error marshalling product")
+		return models.Product{}, errors.New("error creating product")
+	}
+
 	input := &dynamodb.PutItemInput{
 		TableName: &s.tableName,
-		Item: map[string]*dynamodb.AttributeValue{
-			"ProductID": {
-				S: aws.String(product.ProductID),
-			},
-			"Name": {
-				S: aws.String(product.Name),
-			},
-			"Category": {
-				S: aws.String(product.Category),
-			},
-			"OriginalPrice": {
-				N: aws.String(fmt.Sprintf("%d", product.OriginalPrice)),
-			},
-			"DiscountedPrice": {
-				N: aws.String(fmt.Sprintf("%d", product.DiscountedPrice)),
-			},
-
-			"LastUpdated": {
-				S: aws.String(product.LastUpdated),
-			},
-		},
+		Item:      item,
 	}
 
-	_, err := s.db.PutItem(input)
+	_, err = s.db.PutItemWithContext(ctx, input)
 	if err != nil {
 		logrus.WithError(err).Error("[ProductRepositoryImpl.Create] error creating product")
 		return models.Product{}, errors.New("error creating product")
@@ -52,50 +42,173 @@ func (s *ScraperRepositoryImpl) Create(product models.Product) (models.Product,
 	return product, nil
 }
 
-// DeleteAll implements ScraperRepository.
-func (s *ScraperRepositoryImpl) DeleteAll() error {
-	scanInput := &dynamodb.ScanInput{
+// CreateMany implements ScraperRepository. Escribe los productos en batches
+// de 25; si algunos no se pudieron escribir devuelve un *BatchError con sus ids.
+func (s *ScraperRepositoryImpl) CreateMany(ctx context.Context, products []models.Product) error {
+	batchErr := &BatchError{}
+	var requests []*dynamodb.WriteRequest
+	for _, product := range products {
+		item, err := dynamodbattribute.MarshalMap(product)
+		if err != nil {
+			logrus.WithError(err).Errorf("[ScraperRepositoryImpl.CreateMany] error marshalling product %s", product.ProductID)
+			batchErr.Total++
+			batchErr.add(product.ProductID, errors.New("error marshalling product"))
+			continue
+		}
+
+		requests = append(requests, &dynamodb.WriteRequest{
+			PutRequest: &dynamodb.PutRequest{Item: item},
+		})
+	}
+
+	batchWrite(ctx, s.db, s.tableName, requests, productKey, batchErr)
+	return batchErr.errOrNil()
+}
+
+// GetAll implements ScraperRepository.
+func (s *ScraperRepositoryImpl) GetAll(ctx context.Context) ([]models.Product, error) {
+	input := &dynamodb.ScanInput{
 		TableName: &s.tableName,
 	}
 
-	result, err := s.db.Scan(scanInput)
+	items, err := db.ScanAll(ctx, s.db, input)
 	if err != nil {
-		logrus.WithError(err).Error("[ProductRepositoryImpl.DeleteAll] error scanning products")
-		return errors.New("error scanning products")
+		logrus.WithError(err).Error("[ScraperRepositoryImpl.GetAll] error scanning products")
+		return nil, errors.New("error getting products")
 	}
 
-	if len(result.Items) == 0 {
-		logrus.Info("no products to delete")
-		return nil
+	var products []models.Product
+	err = dynamodbattribute.UnmarshalListOfMaps(items, &products)
+	if err != nil {
+		logrus.WithError(err).Error("[ScraperRepositoryImpl.GetAll] error unmarshalling products")
+		return nil, errors.New("error getting products")
+	}
+
+	return products, nil
+}
+
+// MarkDiscontinued implements ScraperRepository.
+func (s *ScraperRepositoryImpl) MarkDiscontinued(ctx context.Context, productID string, discontinuedAt string) error {
+	input := &dynamodb.UpdateItemInput{
+		TableName: &s.tableName,
+		Key: map[string]*dynamodb.AttributeValue{
+			"ProductID": {
+				S: aws.String(productID),
+			},
+		},
+		// Sin Listing ni ListingCategory el producto sale de los indices de
+		// listado de la API
+		UpdateExpression: aws.String("SET DiscontinuedAt = :discontinuedAt REMOVE Listing, ListingCategory"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":discontinuedAt": {
+				S: aws.String(discontinuedAt),
+			},
+		},
 	}
 
-	var deleteErrors []error
+	_, err := s.db.UpdateItemWithContext(ctx, input)
+	if err != nil {
+		logrus.WithError(err).Error("[ScraperRepositoryImpl.MarkDiscontinued] error marking product as discontinued")
+		return errors.New("error marking product as discontinued")
+	}
+
+	return nil
+}
 
-	// Iterar sobre los elementos escaneados y eliminarlos
-	for _, item := range result.Items {
-		deleteInput := &dynamodb.DeleteItemInput{
-			TableName: &s.tableName,
-			Key: map[string]*dynamodb.AttributeValue{
-				"ProductID": item["ProductID"],
+// Delete implements ScraperRepository.
+func (s *ScraperRepositoryImpl) Delete(ctx context.Context, productID string) error {
+	input := &dynamodb.DeleteItemInput{
+		TableName: &s.tableName,
+		Key: map[string]*dynamodb.AttributeValue{
+			"ProductID": {
+				S: aws.String(productID),
 			},
+		},
+	}
+
+	_, err := s.db.DeleteItemWithContext(ctx, input)
+	if err != nil {
+		logrus.WithError(err).Error("[ScraperRepositoryImpl.Delete] error deleting product")
+		return errors.New("error deleting product")
+	}
+
+	return nil
+}
+
+// DeleteMany implements ScraperRepository. Elimina los productos en batches
+// de 25; si algunos no se pudieron eliminar devuelve un *BatchError con sus ids.
+func (s *ScraperRepositoryImpl) DeleteMany(ctx context.Context, productIDs []string) error {
+	requests := make([]*dynamodb.WriteRequest, 0, len(productIDs))
+	for _, productID := range productIDs {
+		requests = append(requests, &dynamodb.WriteRequest{
+			DeleteRequest: &dynamodb.DeleteRequest{
+				Key: map[string]*dynamodb.AttributeValue{
+					"ProductID": {
+						S: aws.String(productID),
+					},
+				},
+			},
+		})
+	}
+
+	batchErr := &BatchError{}
+	batchWrite(ctx, s.db, s.tableName, requests, productKey, batchErr)
+	return batchErr.errOrNil()
+}
+
+// DeleteAll implements ScraperRepository.
+func (s *ScraperRepositoryImpl) DeleteAll(ctx context.Context) error {
+	// Solo hace falta la clave de cada producto
+	scanInput := &dynamodb.ScanInput{
+		TableName:            &s.tableName,
+		ProjectionExpression: aws.String("ProductID"),
+	}
+
+	// Se elimina pagina por pagina, el Scan sigue siendo valido aunque se
+	// borren los items ya leidos
+	deleted := 0
+	var deleteErr error
+	scanner := db.NewScanner(ctx, s.db, scanInput)
+	for scanner.Next() {
+		productIDs := make([]string, 0, len(scanner.Items()))
+		for _, item := range scanner.Items() {
+			productIDs = append(productIDs, productKey(item))
+		}
+		if len(productIDs) == 0 {
+			continue
 		}
 
-		_, err := s.db.DeleteItem(deleteInput)
+		err := s.DeleteMany(ctx, productIDs)
 		if err != nil {
-			logrus.WithError(err).Error("[ProductRepositoryImpl.DeleteAll] error deleting product")
-			deleteErrors = append(deleteErrors, err)
+			deleteErr = err
 		}
+		deleted += len(productIDs)
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		logrus.WithError(err).Error("[ProductRepositoryImpl.DeleteAll] error scanning products")
+		return errors.New("error scanning products")
 	}
 
-	// Si hubo errores, retornarlos combinados
-	if len(deleteErrors) > 0 {
-		logrus.WithError(err).Error("[ProductRepositoryImpl.DeleteAll] one or more errors occurred while deleting products")
+	if deleted == 0 {
+		logrus.Info("no products to delete")
+		return nil
+	}
+
+	if deleteErr != nil {
+		logrus.WithError(deleteErr).Error("[ProductRepositoryImpl.DeleteAll] one or more errors occurred while deleting products")
 		return errors.New("one or more errors occurred while deleting products")
 	}
 
 	return nil
 }
 
+// productKey es el id con que se reporta un producto en un batch
+func productKey(item map[string]*dynamodb.AttributeValue) string {
+	return aws.StringValue(item["ProductID"].S)
+}
+
 func NewScraperRepositoryImpl(db dynamodbiface.DynamoDBAPI, tableName string) ScraperRepository {
 	return &ScraperRepositoryImpl{
 		db:        db,
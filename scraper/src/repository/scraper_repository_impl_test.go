@@ -1,10 +1,15 @@
 package repository
 
 import (
+	"context"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/dieg0code/shared/mocks"
 	"github.com/dieg0code/shared/models"
 	"github.com/stretchr/testify/assert"
@@ -18,16 +23,29 @@ func TestScraperRepository_Create(t *testing.T) {
 
 		product := models.Product{
 			ProductID:       "test-id",
+			Store:           "cugat.cl",
 			Name:            "Test Product",
 			Category:        "Test Category",
 			OriginalPrice:   100,
 			DiscountedPrice: 90,
+			URL:             "https://cugat.cl/producto/test-product/",
+			ImageURL:        "https://cugat.cl/wp-content/uploads/test-product.jpg",
+			SKU:             "1234",
+			StockStatus:     models.StockOutOfStock,
+			PackageQuantity: 0.35,
+			PackageUnit:     "l",
+			PackageCount:    6,
+			UnitPrice:       2376,
 			LastUpdated:     "02-02-1996",
 		}
 
-		mockDB.On("PutItem", mock.Anything).Return(&dynamodb.PutItemOutput{}, nil)
+		mockDB.On("PutItem", mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+			var saved models.Product
+			err := dynamodbattribute.UnmarshalMap(input.Item, &saved)
+			return err == nil && saved == product
+		})).Return(&dynamodb.PutItemOutput{}, nil)
 
-		result, err := repo.Create(product)
+		result, err := repo.Create(context.Background(), product)
 		assert.NoError(t, err, "Expected no error creating product")
 
 		assert.Equal(t, product, result, "Expected product to be the same")
@@ -50,7 +68,7 @@ func TestScraperRepository_Create(t *testing.T) {
 
 		mockDB.On("PutItem", mock.Anything).Return(&dynamodb.PutItemOutput{}, assert.AnError)
 
-		result, err := repo.Create(product)
+		result, err := repo.Create(context.Background(), product)
 		assert.Error(t, err, "Expected error creating product")
 		assert.Equal(t, models.Product{}, result, "Expected empty product")
 
@@ -58,6 +76,31 @@ func TestScraperRepository_Create(t *testing.T) {
 	})
 }
 
+// mockScanPages configura un Scan de productos en varias paginas enlazadas
+// por LastEvaluatedKey
+func mockScanPages(mockDB *mocks.MockDynamoDB, pages ...[]string) {
+	var startKey string
+	for i, productIDs := range pages {
+		output := &dynamodb.ScanOutput{}
+		for _, productID := range productIDs {
+			output.Items = append(output.Items, map[string]*dynamodb.AttributeValue{"ProductID": {S: aws.String(productID)}})
+		}
+		if i < len(pages)-1 {
+			output.LastEvaluatedKey = output.Items[len(output.Items)-1]
+		}
+
+		expected := startKey
+		mockDB.On("Scan", mock.MatchedBy(func(input *dynamodb.ScanInput) bool {
+			if expected == "" {
+				return input.ExclusiveStartKey == nil
+			}
+			return input.ExclusiveStartKey != nil && aws.StringValue(input.ExclusiveStartKey["ProductID"].S) == expected
+		})).Return(output, nil).Once()
+
+		startKey = productIDs[len(productIDs)-1]
+	}
+}
+
 func TestScraperRepository_DeleteAll(t *testing.T) {
 	t.Run("DeleteAll_Success", func(t *testing.T) {
 		mockDB := new(mocks.MockDynamoDB)
@@ -72,13 +115,30 @@ func TestScraperRepository_DeleteAll(t *testing.T) {
 		}
 
 		mockDB.On("Scan", mock.Anything).Return(mockScanOutput, nil)
-		mockDB.On("DeleteItem", mock.Anything).Return(&dynamodb.DeleteItemOutput{}, nil)
+		mockDB.On("BatchWriteItem", mock.Anything).Return(&dynamodb.BatchWriteItemOutput{}, nil)
+
+		err := repo.DeleteAll(context.Background())
+		assert.NoError(t, err, "Expected no error deleting all products")
 
-		err := repo.DeleteAll()
+		// Los dos items caben en un solo batch
+		mockDB.AssertNumberOfCalls(t, "BatchWriteItem", 1)
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("DeleteAll_Paginated", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewScraperRepositoryImpl(mockDB, "test-table")
+
+		mockScanPages(mockDB, []string{"1", "2"}, []string{"3"})
+		mockDB.On("BatchWriteItem", mock.Anything).Return(&dynamodb.BatchWriteItemOutput{}, nil)
+
+		err := repo.DeleteAll(context.Background())
 		assert.NoError(t, err, "Expected no error deleting all products")
 
-		// Ensure DeleteItem is called for each item
-		mockDB.AssertNumberOfCalls(t, "DeleteItem", len(mockScanOutput.Items))
+		// Cada pagina se elimina con su propio batch
+		mockDB.AssertNumberOfCalls(t, "BatchWriteItem", 2)
+		assert.Len(t, batchRequests(mockDB.Calls[1]), 2, "Expected the first page to be deleted")
+		assert.Len(t, batchRequests(mockDB.Calls[3]), 1, "Expected the last page to be deleted")
 		mockDB.AssertExpectations(t)
 	})
 
@@ -93,7 +153,7 @@ func TestScraperRepository_DeleteAll(t *testing.T) {
 
 		mockDB.On("Scan", mock.Anything).Return(mockScanOutput, nil)
 
-		err := repo.DeleteAll()
+		err := repo.DeleteAll(context.Background())
 		assert.NoError(t, err, "Expected no error deleting all products")
 
 		mockDB.AssertExpectations(t)
@@ -105,7 +165,7 @@ func TestScraperRepository_DeleteAll(t *testing.T) {
 
 		mockDB.On("Scan", mock.Anything).Return(&dynamodb.ScanOutput{}, assert.AnError)
 
-		err := repo.DeleteAll()
+		err := repo.DeleteAll(context.Background())
 		assert.Error(t, err, "Expected error deleting all products")
 
 		mockDB.AssertExpectations(t)
@@ -124,12 +184,295 @@ func TestScraperRepository_DeleteAll(t *testing.T) {
 		}
 
 		mockDB.On("Scan", mock.Anything).Return(mockScanOutput, nil)
-		mockDB.On("DeleteItem", mock.Anything).Return(&dynamodb.DeleteItemOutput{}, assert.AnError).Twice() // Se esperan dos llamadas
+		mockDB.On("BatchWriteItem", mock.Anything).Return(&dynamodb.BatchWriteItemOutput{}, assert.AnError).Once()
 
-		err := repo.DeleteAll()
+		err := repo.DeleteAll(context.Background())
 		assert.Error(t, err, "Expected error deleting all products")
 
 		mockDB.AssertExpectations(t)
 	})
 
 }
+
+// fastBatchBackoff acorta las esperas entre reintentos de batchWrite
+func fastBatchBackoff(t *testing.T) {
+	backoff := batchBackoff
+	batchBackoff.BaseDelay = time.Millisecond
+	batchBackoff.MaxDelay = time.Millisecond
+	t.Cleanup(func() { batchBackoff = backoff })
+}
+
+// batchRequests devuelve los WriteRequest de una llamada a BatchWriteItem
+func batchRequests(call mock.Call) []*dynamodb.WriteRequest {
+	input, ok := call.Arguments.Get(0).(*dynamodb.BatchWriteItemInput)
+	if !ok {
+		return nil
+	}
+	return input.RequestItems["test-table"]
+}
+
+func TestScraperRepository_CreateMany(t *testing.T) {
+	newProducts := func(n int) []models.Product {
+		products := make([]models.Product, n)
+		for i := range products {
+			products[i] = models.Product{ProductID: fmt.Sprintf("product-%d", i), Name: fmt.Sprintf("Product %d", i)}
+		}
+		return products
+	}
+
+	t.Run("CreateMany_ChunksBatches", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewScraperRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("BatchWriteItem", mock.Anything).Return(&dynamodb.BatchWriteItemOutput{}, nil)
+
+		err := repo.CreateMany(context.Background(), newProducts(60))
+		assert.NoError(t, err, "Expected no error creating products")
+
+		// 60 productos son 3 batches: 25, 25 y 10
+		mockDB.AssertNumberOfCalls(t, "BatchWriteItem", 3)
+		assert.Len(t, batchRequests(mockDB.Calls[0]), 25, "Expected a full first batch")
+		assert.Len(t, batchRequests(mockDB.Calls[2]), 10, "Expected the remainder in the last batch")
+
+		var saved models.Product
+		err = dynamodbattribute.UnmarshalMap(batchRequests(mockDB.Calls[0])[0].PutRequest.Item, &saved)
+		assert.NoError(t, err, "Expected a marshalled product")
+		assert.Equal(t, "product-0", saved.ProductID, "Expected products in order")
+	})
+
+	t.Run("CreateMany_DeduplicatesProducts", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewScraperRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("BatchWriteItem", mock.Anything).Return(&dynamodb.BatchWriteItemOutput{}, nil)
+
+		products := []models.Product{
+			{ProductID: "1", Name: "Old"},
+			{ProductID: "2", Name: "Other"},
+			{ProductID: "1", Name: "New"},
+		}
+		err := repo.CreateMany(context.Background(), products)
+		assert.NoError(t, err, "Expected no error creating products")
+
+		requests := batchRequests(mockDB.Calls[0])
+		assert.Len(t, requests, 2, "Expected one write per product id")
+		assert.Equal(t, "New", aws.StringValue(requests[0].PutRequest.Item["Name"].S), "Expected the last write to win")
+	})
+
+	t.Run("CreateMany_RetriesUnprocessedItems", func(t *testing.T) {
+		fastBatchBackoff(t)
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewScraperRepositoryImpl(mockDB, "test-table")
+
+		products := newProducts(3)
+		unprocessed := &dynamodb.WriteRequest{PutRequest: &dynamodb.PutRequest{
+			Item: map[string]*dynamodb.AttributeValue{"ProductID": {S: aws.String("product-2")}},
+		}}
+		mockDB.On("BatchWriteItem", mock.Anything).Return(&dynamodb.BatchWriteItemOutput{
+			UnprocessedItems: map[string][]*dynamodb.WriteRequest{"test-table": {unprocessed}},
+		}, nil).Once()
+		mockDB.On("BatchWriteItem", mock.Anything).Return(&dynamodb.BatchWriteItemOutput{}, nil).Once()
+
+		err := repo.CreateMany(context.Background(), products)
+		assert.NoError(t, err, "Expected no error creating products")
+
+		mockDB.AssertNumberOfCalls(t, "BatchWriteItem", 2)
+		assert.Equal(t, []*dynamodb.WriteRequest{unprocessed}, batchRequests(mockDB.Calls[1]), "Expected only the unprocessed item to be retried")
+	})
+
+	t.Run("CreateMany_ReportsItemFailures", func(t *testing.T) {
+		fastBatchBackoff(t)
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewScraperRepositoryImpl(mockDB, "test-table")
+
+		// El segundo batch falla entero y el primero deja un item sin procesar
+		unprocessed := &dynamodb.WriteRequest{PutRequest: &dynamodb.PutRequest{
+			Item: map[string]*dynamodb.AttributeValue{"ProductID": {S: aws.String("product-0")}},
+		}}
+		mockDB.On("BatchWriteItem", mock.MatchedBy(func(input *dynamodb.BatchWriteItemInput) bool {
+			return len(input.RequestItems["test-table"]) != 5
+		})).Return(&dynamodb.BatchWriteItemOutput{
+			UnprocessedItems: map[string][]*dynamodb.WriteRequest{"test-table": {unprocessed}},
+		}, nil)
+		mockDB.On("BatchWriteItem", mock.Anything).Return(&dynamodb.BatchWriteItemOutput{}, assert.AnError)
+
+		err := repo.CreateMany(context.Background(), newProducts(30))
+		assert.Error(t, err, "Expected error creating products")
+
+		var batchErr *BatchError
+		assert.ErrorAs(t, err, &batchErr, "Expected a batch error")
+		assert.Equal(t, 30, batchErr.Total, "Expected every product to be counted")
+		assert.Len(t, batchErr.Failures, 6, "Expected the unprocessed item and the failed batch")
+		assert.Equal(t, ItemFailure{ID: "product-0", Err: errUnprocessed}, batchErr.Failures[0], "Expected the unprocessed item to be reported")
+		assert.Equal(t, ItemFailure{ID: "product-25", Err: assert.AnError}, batchErr.Failures[1], "Expected the failed batch to be reported")
+
+		// Un intento mas los reintentos del item sin procesar, y el batch fallido
+		mockDB.AssertNumberOfCalls(t, "BatchWriteItem", batchBackoff.MaxRetries+2)
+	})
+
+	t.Run("CreateMany_StopsRetryingWhenCancelled", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewScraperRepositoryImpl(mockDB, "test-table")
+
+		unprocessed := &dynamodb.WriteRequest{PutRequest: &dynamodb.PutRequest{
+			Item: map[string]*dynamodb.AttributeValue{"ProductID": {S: aws.String("product-0")}},
+		}}
+		mockDB.On("BatchWriteItem", mock.Anything).Return(&dynamodb.BatchWriteItemOutput{
+			UnprocessedItems: map[string][]*dynamodb.WriteRequest{"test-table": {unprocessed}},
+		}, nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := repo.CreateMany(ctx, newProducts(1))
+
+		var batchErr *BatchError
+		assert.ErrorAs(t, err, &batchErr, "Expected a batch error")
+		assert.Equal(t, []ItemFailure{{ID: "product-0", Err: context.Canceled}}, batchErr.Failures, "Expected the cancellation to be reported")
+		mockDB.AssertNumberOfCalls(t, "BatchWriteItem", 1)
+	})
+}
+
+func TestScraperRepository_DeleteMany(t *testing.T) {
+	t.Run("DeleteMany_Success", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewScraperRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("BatchWriteItem", mock.MatchedBy(func(input *dynamodb.BatchWriteItemInput) bool {
+			requests := input.RequestItems["test-table"]
+			return len(requests) == 2 &&
+				requests[0].DeleteRequest != nil &&
+				aws.StringValue(requests[0].DeleteRequest.Key["ProductID"].S) == "1" &&
+				aws.StringValue(requests[1].DeleteRequest.Key["ProductID"].S) == "2"
+		})).Return(&dynamodb.BatchWriteItemOutput{}, nil)
+
+		err := repo.DeleteMany(context.Background(), []string{"1", "2"})
+		assert.NoError(t, err, "Expected no error deleting products")
+
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("DeleteMany_Error", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewScraperRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("BatchWriteItem", mock.Anything).Return(&dynamodb.BatchWriteItemOutput{}, assert.AnError)
+
+		err := repo.DeleteMany(context.Background(), []string{"1", "2"})
+
+		var batchErr *BatchError
+		assert.ErrorAs(t, err, &batchErr, "Expected a batch error")
+		assert.Equal(t, "2 of 2 items could not be written", err.Error(), "Expected both products to fail")
+	})
+}
+
+func TestScraperRepository_GetAll(t *testing.T) {
+	t.Run("GetAll_Success", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewScraperRepositoryImpl(mockDB, "test-table")
+
+		mockScanOutput := &dynamodb.ScanOutput{
+			Items: []map[string]*dynamodb.AttributeValue{
+				{"ProductID": {S: aws.String("1")}, "Name": {S: aws.String("Product 1")}},
+				{"ProductID": {S: aws.String("2")}, "DiscontinuedAt": {S: aws.String("2024-01-01T00:00:00Z")}},
+			},
+		}
+
+		mockDB.On("Scan", mock.Anything).Return(mockScanOutput, nil)
+
+		products, err := repo.GetAll(context.Background())
+		assert.NoError(t, err, "Expected no error getting products")
+		assert.Equal(t, []models.Product{
+			{ProductID: "1", Name: "Product 1"},
+			{ProductID: "2", DiscontinuedAt: "2024-01-01T00:00:00Z"},
+		}, products, "Expected products to match")
+
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("GetAll_Paginated", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewScraperRepositoryImpl(mockDB, "test-table")
+
+		mockScanPages(mockDB, []string{"1", "2"}, []string{"3", "4"}, []string{"5"})
+
+		products, err := repo.GetAll(context.Background())
+		assert.NoError(t, err, "Expected no error getting products")
+
+		var productIDs []string
+		for _, product := range products {
+			productIDs = append(productIDs, product.ProductID)
+		}
+		assert.Equal(t, []string{"1", "2", "3", "4", "5"}, productIDs, "Expected products of every page")
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("GetAll_Error", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewScraperRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("Scan", mock.Anything).Return(&dynamodb.ScanOutput{}, assert.AnError)
+
+		products, err := repo.GetAll(context.Background())
+		assert.Error(t, err, "Expected error getting products")
+		assert.Nil(t, products, "Expected nil products")
+
+		mockDB.AssertExpectations(t)
+	})
+}
+
+func TestScraperRepository_MarkDiscontinued(t *testing.T) {
+	t.Run("MarkDiscontinued_Success", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewScraperRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("UpdateItem", mock.MatchedBy(func(input *dynamodb.UpdateItemInput) bool {
+			return *input.Key["ProductID"].S == "1" &&
+				*input.ExpressionAttributeValues[":discontinuedAt"].S == "2024-01-01T00:00:00Z" &&
+				strings.Contains(*input.UpdateExpression, "REMOVE Listing, ListingCategory")
+		})).Return(&dynamodb.UpdateItemOutput{}, nil)
+
+		err := repo.MarkDiscontinued(context.Background(), "1", "2024-01-01T00:00:00Z")
+		assert.NoError(t, err, "Expected no error marking product as discontinued")
+
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("MarkDiscontinued_Error", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewScraperRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("UpdateItem", mock.Anything).Return(&dynamodb.UpdateItemOutput{}, assert.AnError)
+
+		err := repo.MarkDiscontinued(context.Background(), "1", "2024-01-01T00:00:00Z")
+		assert.Error(t, err, "Expected error marking product as discontinued")
+
+		mockDB.AssertExpectations(t)
+	})
+}
+
+func TestScraperRepository_Delete(t *testing.T) {
+	t.Run("Delete_Success", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewScraperRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("DeleteItem", mock.Anything).Return(&dynamodb.DeleteItemOutput{}, nil)
+
+		err := repo.Delete(context.Background(), "1")
+		assert.NoError(t, err, "Expected no error deleting product")
+
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("Delete_Error", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewScraperRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("DeleteItem", mock.Anything).Return(&dynamodb.DeleteItemOutput{}, assert.AnError)
+
+		err := repo.Delete(context.Background(), "1")
+		assert.Error(t, err, "Expected error deleting product")
+
+		mockDB.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/dieg0code/shared/models"
+)
+
+type SearchIndexRepository interface {
+	Index(ctx context.Context, products []models.Product) error
+	Remove(ctx context.Context, products []models.Product) error
+}
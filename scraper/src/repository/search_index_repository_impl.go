@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/dieg0code/shared/models"
+	"github.com/dieg0code/shared/search"
+	"github.com/sirupsen/logrus"
+)
+
+// SearchIndexRepositoryImpl guarda el indice invertido de busqueda de los
+// productos: una entrada por token de cada producto, ver search.Entries
+type SearchIndexRepositoryImpl struct {
+	db        dynamodbiface.DynamoDBAPI
+	tableName string
+}
+
+// tokensPrefix es la particion donde se guardan los tokens indexados de cada
+// producto. Los tokens solo tienen letras y numeros, asi que ninguna busqueda
+// lee esta particion.
+const tokensPrefix = "#tokens"
+
+// indexedTokens son los tokens con que se indexo un producto la ultima vez.
+// Sirven para borrar las entradas de los tokens que el producto ya no tiene,
+// por ejemplo cuando cambia de nombre.
+type indexedTokens struct {
+	Prefix string   `dynamodbav:"Prefix"`
+	Key    string   `dynamodbav:"Key"`
+	Tokens []string `dynamodbav:"Tokens"`
+}
+
+// maxBatchGetSize es el maximo de claves que acepta BatchGetItem por llamada
+const maxBatchGetSize = 100
+
+// Index implements SearchIndexRepository. Escribe las entradas de los
+// productos y borra las de los tokens que ya no tienen, en batches de 25; si
+// algunas no se pudieron escribir devuelve un *BatchError con sus claves. Si
+// no se pueden leer los tokens anteriores no escribe nada, para no perder la
+// referencia a las entradas viejas.
+func (s *SearchIndexRepositoryImpl) Index(ctx context.Context, products []models.Product) error {
+	previous, err := s.getIndexedTokens(ctx, products)
+	if err != nil {
+		return err
+	}
+
+	batchErr := &BatchError{}
+	var requests []*dynamodb.WriteRequest
+	for _, product := range products {
+		current := make(map[string]bool)
+		tokens := indexedTokens{Prefix: tokensPrefix, Key: product.ProductID, Tokens: []string{}}
+		for _, entry := range search.Entries(product) {
+			current[entry.Token] = true
+			tokens.Tokens = append(tokens.Tokens, entry.Token)
+
+			item, err := dynamodbattribute.MarshalMap(entry)
+			if err != nil {
+				logrus.WithError(err).Errorf("[SearchIndexRepositoryImpl.Index] error marshalling search entry %s", entry.Key)
+				batchErr.Total++
+				batchErr.add(entry.Key, errors.New("error marshalling search entry"))
+				continue
+			}
+
+			requests = append(requests, &dynamodb.WriteRequest{
+				PutRequest: &dynamodb.PutRequest{Item: item},
+			})
+		}
+
+		for _, token := range previous[product.ProductID] {
+			if !current[token] {
+				requests = append(requests, deleteEntryRequest(token, product.ProductID))
+			}
+		}
+
+		item, err := dynamodbattribute.MarshalMap(tokens)
+		if err != nil {
+			logrus.WithError(err).Errorf("[SearchIndexRepositoryImpl.Index] error marshalling tokens of product %s", product.ProductID)
+			batchErr.Total++
+			batchErr.add(product.ProductID, errors.New("error marshalling indexed tokens"))
+			continue
+		}
+		requests = append(requests, &dynamodb.WriteRequest{
+			PutRequest: &dynamodb.PutRequest{Item: item},
+		})
+	}
+
+	batchWrite(ctx, s.db, s.tableName, requests, searchEntryKey, batchErr)
+	return batchErr.errOrNil()
+}
+
+// Remove implements SearchIndexRepository. Borra las entradas de los tokens
+// indexados y las que salen del nombre y la categoria actuales, por si el
+// producto se indexo antes de que se guardaran sus tokens.
+func (s *SearchIndexRepositoryImpl) Remove(ctx context.Context, products []models.Product) error {
+	previous, err := s.getIndexedTokens(ctx, products)
+	if err != nil {
+		return err
+	}
+
+	var requests []*dynamodb.WriteRequest
+	for _, product := range products {
+		tokens := previous[product.ProductID]
+		for _, entry := range search.Entries(product) {
+			tokens = append(tokens, entry.Token)
+		}
+
+		for _, token := range tokens {
+			requests = append(requests, deleteEntryRequest(token, product.ProductID))
+		}
+		requests = append(requests, &dynamodb.WriteRequest{
+			DeleteRequest: &dynamodb.DeleteRequest{
+				Key: map[string]*dynamodb.AttributeValue{
+					"Prefix": {S: aws.String(tokensPrefix)},
+					"Key":    {S: aws.String(product.ProductID)},
+				},
+			},
+		})
+	}
+
+	batchErr := &BatchError{}
+	batchWrite(ctx, s.db, s.tableName, requests, searchEntryKey, batchErr)
+	return batchErr.errOrNil()
+}
+
+// getIndexedTokens devuelve los tokens indexados de cada producto por su id.
+// Los productos que nunca se indexaron no aparecen.
+func (s *SearchIndexRepositoryImpl) getIndexedTokens(ctx context.Context, products []models.Product) (map[string][]string, error) {
+	previous := make(map[string][]string)
+	for start := 0; start < len(products); start += maxBatchGetSize {
+		var keys []map[string]*dynamodb.AttributeValue
+		for _, product := range products[start:min(start+maxBatchGetSize, len(products))] {
+			keys = append(keys, map[string]*dynamodb.AttributeValue{
+				"Prefix": {S: aws.String(tokensPrefix)},
+				"Key":    {S: aws.String(product.ProductID)},
+			})
+		}
+
+		for attempt := 0; len(keys) > 0; attempt++ {
+			if attempt > 0 {
+				if attempt > batchBackoff.MaxRetries {
+					logrus.WithField("unprocessed", len(keys)).Error("[SearchIndexRepositoryImpl.getIndexedTokens] unprocessed keys after retries")
+					return nil, errUnprocessed
+				}
+				if err := sleepContext(ctx, backoffDelay(attempt)); err != nil {
+					return nil, err
+				}
+			}
+
+			result, err := s.db.BatchGetItemWithContext(ctx, &dynamodb.BatchGetItemInput{
+				RequestItems: map[string]*dynamodb.KeysAndAttributes{
+					s.tableName: {Keys: keys},
+				},
+			})
+			if err != nil {
+				logrus.WithError(err).Error("[SearchIndexRepositoryImpl.getIndexedTokens] error getting indexed tokens")
+				return nil, errors.New("error getting indexed tokens")
+			}
+
+			var found []indexedTokens
+			err = dynamodbattribute.UnmarshalListOfMaps(result.Responses[s.tableName], &found)
+			if err != nil {
+				logrus.WithError(err).Error("[SearchIndexRepositoryImpl.getIndexedTokens] error unmarshalling indexed tokens")
+				return nil, errors.New("error getting indexed tokens")
+			}
+			for _, tokens := range found {
+				previous[tokens.Key] = tokens.Tokens
+			}
+
+			keys = nil
+			if unprocessed, ok := result.UnprocessedKeys[s.tableName]; ok {
+				keys = unprocessed.Keys
+			}
+		}
+	}
+
+	return previous, nil
+}
+
+// deleteEntryRequest borra la entrada de un token de un producto
+func deleteEntryRequest(token string, productID string) *dynamodb.WriteRequest {
+	return &dynamodb.WriteRequest{
+		DeleteRequest: &dynamodb.DeleteRequest{
+			Key: map[string]*dynamodb.AttributeValue{
+				"Prefix": {S: aws.String(search.Prefix(token))},
+				"Key":    {S: aws.String(token + "#" + productID)},
+			},
+		},
+	}
+}
+
+// searchEntryKey identifica una entrada por su Key, que ya incluye el token
+func searchEntryKey(item map[string]*dynamodb.AttributeValue) string {
+	return aws.StringValue(item["Key"].S)
+}
+
+func NewSearchIndexRepositoryImpl(db dynamodbiface.DynamoDBAPI, tableName string) SearchIndexRepository {
+	return &SearchIndexRepositoryImpl{
+		db:        db,
+		tableName: tableName,
+	}
+}
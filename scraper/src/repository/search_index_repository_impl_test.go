@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/dieg0code/shared/mocks"
+	"github.com/dieg0code/shared/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// writeRequests junta las escrituras de todas las llamadas a BatchWriteItem
+func writeRequests(mockDB *mocks.MockDynamoDB) []*dynamodb.WriteRequest {
+	var requests []*dynamodb.WriteRequest
+	for _, call := range mockDB.Calls {
+		if call.Method == "BatchWriteItem" {
+			requests = append(requests, batchRequests(call)...)
+		}
+	}
+	return requests
+}
+
+// indexedTokensItem es el item con los tokens indexados de un producto
+func indexedTokensItem(productID string, tokens ...string) map[string]*dynamodb.AttributeValue {
+	var list []*dynamodb.AttributeValue
+	for _, token := range tokens {
+		list = append(list, &dynamodb.AttributeValue{S: aws.String(token)})
+	}
+	return map[string]*dynamodb.AttributeValue{
+		"Prefix": {S: aws.String(tokensPrefix)},
+		"Key":    {S: aws.String(productID)},
+		"Tokens": {L: list},
+	}
+}
+
+func TestSearchIndexRepository_Index(t *testing.T) {
+	t.Run("Index_Success", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewSearchIndexRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("BatchGetItem", mock.Anything).Return(&dynamodb.BatchGetItemOutput{}, nil)
+		mockDB.On("BatchWriteItem", mock.Anything).Return(&dynamodb.BatchWriteItemOutput{}, nil)
+
+		err := repo.Index(context.Background(), []models.Product{
+			{ProductID: "p1", Name: "Café Nescafé", Category: "Café"},
+			{ProductID: "p2", Name: "Leche Entera", CategorySlug: "lacteos"},
+		})
+		assert.NoError(t, err, "Expected no error indexing products")
+
+		requests := writeRequests(mockDB)
+		var keys []string
+		for _, request := range requests {
+			assert.NotNil(t, request.PutRequest, "Expected only puts")
+			keys = append(keys, aws.StringValue(request.PutRequest.Item["Key"].S))
+		}
+		assert.Equal(t, []string{"cafe#p1", "nescafe#p1", "p1", "leche#p2", "entera#p2", "lacteo#p2", "p2"}, keys, "Expected one entry per token and the indexed tokens of each product")
+		assert.Equal(t, "ca", aws.StringValue(requests[0].PutRequest.Item["Prefix"].S), "Expected the token prefix as partition")
+		assert.Equal(t, tokensPrefix, aws.StringValue(requests[2].PutRequest.Item["Prefix"].S), "Expected the indexed tokens in their own partition")
+
+		mockDB.AssertNumberOfCalls(t, "BatchWriteItem", 1)
+	})
+
+	t.Run("Index_RemovesStaleTokens", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewSearchIndexRepositoryImpl(mockDB, "test-table")
+
+		// El producto se llamaba "Cafe Molido" y ahora "Cafe Nescafe"
+		mockDB.On("BatchGetItem", mock.MatchedBy(func(input *dynamodb.BatchGetItemInput) bool {
+			keys := input.RequestItems["test-table"].Keys
+			return len(keys) == 1 &&
+				aws.StringValue(keys[0]["Prefix"].S) == tokensPrefix &&
+				aws.StringValue(keys[0]["Key"].S) == "p1"
+		})).Return(&dynamodb.BatchGetItemOutput{
+			Responses: map[string][]map[string]*dynamodb.AttributeValue{
+				"test-table": {indexedTokensItem("p1", "cafe", "molido")},
+			},
+		}, nil)
+		mockDB.On("BatchWriteItem", mock.Anything).Return(&dynamodb.BatchWriteItemOutput{}, nil)
+
+		err := repo.Index(context.Background(), []models.Product{{ProductID: "p1", Name: "Café Nescafé"}})
+		assert.NoError(t, err, "Expected no error indexing products")
+
+		var deleted []string
+		for _, request := range writeRequests(mockDB) {
+			if request.DeleteRequest != nil {
+				deleted = append(deleted, aws.StringValue(request.DeleteRequest.Key["Key"].S))
+				assert.Equal(t, "mo", aws.StringValue(request.DeleteRequest.Key["Prefix"].S), "Expected the partition of the stale token")
+			}
+		}
+		assert.Equal(t, []string{"molido#p1"}, deleted, "Expected only the entries of the tokens that are gone")
+	})
+
+	t.Run("Index_ErrorGettingIndexedTokens", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewSearchIndexRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("BatchGetItem", mock.Anything).Return(&dynamodb.BatchGetItemOutput{}, assert.AnError)
+
+		err := repo.Index(context.Background(), []models.Product{{ProductID: "p1", Name: "Café"}})
+
+		assert.Error(t, err, "Expected an error getting the indexed tokens")
+		mockDB.AssertNotCalled(t, "BatchWriteItem", mock.Anything)
+	})
+
+	t.Run("Index_Error", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewSearchIndexRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("BatchGetItem", mock.Anything).Return(&dynamodb.BatchGetItemOutput{}, nil)
+		mockDB.On("BatchWriteItem", mock.Anything).Return(&dynamodb.BatchWriteItemOutput{}, assert.AnError)
+
+		err := repo.Index(context.Background(), []models.Product{{ProductID: "p1", Name: "Café"}})
+
+		var batchErr *BatchError
+		assert.ErrorAs(t, err, &batchErr, "Expected a batch error")
+		assert.Equal(t, []ItemFailure{{ID: "cafe#p1", Err: assert.AnError}, {ID: "p1", Err: assert.AnError}}, batchErr.Failures, "Expected the failed entries")
+	})
+}
+
+func TestSearchIndexRepository_Remove(t *testing.T) {
+	t.Run("Remove_Success", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewSearchIndexRepositoryImpl(mockDB, "test-table")
+
+		// Los tokens indexados incluyen un nombre anterior del producto
+		mockDB.On("BatchGetItem", mock.Anything).Return(&dynamodb.BatchGetItemOutput{
+			Responses: map[string][]map[string]*dynamodb.AttributeValue{
+				"test-table": {indexedTokensItem("p1", "cafe", "molido")},
+			},
+		}, nil)
+		mockDB.On("BatchWriteItem", mock.Anything).Return(&dynamodb.BatchWriteItemOutput{}, nil)
+
+		err := repo.Remove(context.Background(), []models.Product{{ProductID: "p1", Name: "Café Nescafé"}})
+		assert.NoError(t, err, "Expected no error removing products")
+
+		var keys []string
+		for _, request := range writeRequests(mockDB) {
+			assert.NotNil(t, request.DeleteRequest, "Expected only deletes")
+			keys = append(keys, aws.StringValue(request.DeleteRequest.Key["Prefix"].S)+"/"+aws.StringValue(request.DeleteRequest.Key["Key"].S))
+		}
+		assert.Equal(t, []string{"ca/cafe#p1", "mo/molido#p1", "ne/nescafe#p1", tokensPrefix + "/p1"}, keys, "Expected the entries of every indexed token and the indexed tokens")
+	})
+
+	t.Run("Remove_ErrorGettingIndexedTokens", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		repo := NewSearchIndexRepositoryImpl(mockDB, "test-table")
+
+		mockDB.On("BatchGetItem", mock.Anything).Return(&dynamodb.BatchGetItemOutput{}, assert.AnError)
+
+		err := repo.Remove(context.Background(), []models.Product{{ProductID: "p1", Name: "Café"}})
+
+		assert.Error(t, err, "Expected an error getting the indexed tokens")
+		mockDB.AssertNotCalled(t, "BatchWriteItem", mock.Anything)
+	})
+}
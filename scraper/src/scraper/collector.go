@@ -0,0 +1,71 @@
+package scraper
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gocolly/colly"
+)
+
+// CollectorConfig configura el collector compartido por los scrapers
+type CollectorConfig struct {
+	// UserAgent se manda en todas las requests, vacio usa el de colly
+	UserAgent string
+	// RespectRobotsTxt no visita las URLs que robots.txt no permite
+	RespectRobotsTxt bool
+	// Timeout limita cada request, sin el una tienda colgada bloquea la categoria
+	Timeout time.Duration
+	// RateLimit es el limite por defecto de cada tienda
+	RateLimit RateLimit
+}
+
+// RateLimit limita las requests simultaneas y la espera entre requests a un
+// dominio. Los valores en cero usan los del limite por defecto.
+type RateLimit struct {
+	Parallelism int           `yaml:"parallelism,omitempty"`
+	Delay       time.Duration `yaml:"delay,omitempty"`
+	RandomDelay time.Duration `yaml:"random_delay,omitempty"`
+}
+
+// orDefault completa los valores en cero con los de defaults
+func (r RateLimit) orDefault(defaults RateLimit) RateLimit {
+	if r.Parallelism == 0 {
+		r.Parallelism = defaults.Parallelism
+	}
+	if r.Delay == 0 {
+		r.Delay = defaults.Delay
+	}
+	if r.RandomDelay == 0 {
+		r.RandomDelay = defaults.RandomDelay
+	}
+	return r
+}
+
+// NewCollector crea el collector con un limite por dominio para cada tienda.
+// Los clones que usa ScraperImpl comparten estos limites.
+func NewCollector(config CollectorConfig, stores []SelectorConfig) (*colly.Collector, error) {
+	collector := colly.NewCollector(colly.Async(true))
+	collector.IgnoreRobotsTxt = !config.RespectRobotsTxt
+	if config.UserAgent != "" {
+		collector.UserAgent = config.UserAgent
+	}
+	if config.Timeout > 0 {
+		collector.SetRequestTimeout(config.Timeout)
+	}
+
+	for _, store := range stores {
+		limit := store.RateLimit.orDefault(config.RateLimit)
+
+		err := collector.Limit(&colly.LimitRule{
+			DomainGlob:  "*" + store.Store + "*",
+			Parallelism: limit.Parallelism,
+			Delay:       limit.Delay,
+			RandomDelay: limit.RandomDelay,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error setting rate limit for store %s: %w", store.Store, err)
+		}
+	}
+
+	return collector, nil
+}
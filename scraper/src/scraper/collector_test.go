@@ -0,0 +1,69 @@
+package scraper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gocolly/colly"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCollector(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			_, err := w.Write([]byte("User-agent: *\nDisallow: /privado/\n"))
+			assert.NoError(nil, err, "Expected no error writing response")
+		case "/lento/":
+			time.Sleep(200 * time.Millisecond)
+		default:
+			assert.Equal(t, "test-agent", r.Header.Get("User-Agent"), "Expected configured user agent")
+		}
+	}))
+	defer ts.Close()
+
+	stores := []SelectorConfig{{Store: "127.0.0.1"}}
+
+	t.Run("Collector_RespectsRobotsTxt", func(t *testing.T) {
+		collector, err := NewCollector(CollectorConfig{UserAgent: "test-agent", RespectRobotsTxt: true}, stores)
+		assert.NoError(t, err, "Expected no error creating collector")
+		collector.Async = false
+
+		err = collector.Visit(ts.URL + "/privado/")
+		assert.ErrorIs(t, err, colly.ErrRobotsTxtBlocked, "Expected robots.txt to block the URL")
+
+		err = collector.Visit(ts.URL + "/categoria-producto/despensa/")
+		assert.NoError(t, err, "Expected allowed URLs to be visited")
+	})
+
+	t.Run("Collector_IgnoresRobotsTxt", func(t *testing.T) {
+		collector, err := NewCollector(CollectorConfig{UserAgent: "test-agent"}, stores)
+		assert.NoError(t, err, "Expected no error creating collector")
+		collector.Async = false
+
+		err = collector.Visit(ts.URL + "/privado/")
+		assert.NoError(t, err, "Expected robots.txt to be ignored")
+	})
+
+	t.Run("Collector_Timeout", func(t *testing.T) {
+		collector, err := NewCollector(CollectorConfig{Timeout: 50 * time.Millisecond}, stores)
+		assert.NoError(t, err, "Expected no error creating collector")
+		collector.Async = false
+
+		err = collector.Visit(ts.URL + "/lento/")
+		assert.Error(t, err, "Expected the request to time out")
+	})
+}
+
+func TestRateLimit_OrDefault(t *testing.T) {
+	defaults := RateLimit{Parallelism: 4, Delay: 500 * time.Millisecond, RandomDelay: 250 * time.Millisecond}
+
+	assert.Equal(t, defaults, RateLimit{}.orDefault(defaults), "Expected defaults for an empty limit")
+	assert.Equal(t,
+		RateLimit{Parallelism: 1, Delay: 2 * time.Second, RandomDelay: 250 * time.Millisecond},
+		RateLimit{Parallelism: 1, Delay: 2 * time.Second}.orDefault(defaults),
+		"Expected store values to override the defaults",
+	)
+}
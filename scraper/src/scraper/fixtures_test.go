@@ -0,0 +1,86 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gocolly/colly"
+	"github.com/stretchr/testify/assert"
+)
+
+// update regenera los golden files: go test ./src/scraper -run TestFixtures -update
+var update = flag.Bool("update", false, "rewrite golden files from the fixtures corpus")
+
+// TestFixtures reproduce las paginas de testdata/fixtures/<store>, grabadas
+// con cmd/record o armadas a mano mientras no haya una grabacion, y compara
+// los productos extraidos con testdata/golden/<store>/<category>.json. Un
+// cambio en el HTML de la tienda o en los selectores aparece como diff del
+// golden file.
+func TestFixtures(t *testing.T) {
+	configs, err := DefaultSelectorConfigs()
+	assert.NoError(t, err, "Expected embedded stores config to be valid")
+
+	for _, config := range configs {
+		storeDir := filepath.Join("testdata", "fixtures", config.Store)
+		categoriesDir := filepath.Join(storeDir, filepath.FromSlash(strings.Trim(config.CategoryPath, "/")))
+
+		entries, err := os.ReadDir(categoriesDir)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		assert.NoError(t, err, "Expected fixtures directory to be readable")
+
+		// Las paginas quedan en el mismo path que tenian en la tienda
+		ts := httptest.NewServer(http.FileServer(http.Dir(storeDir)))
+		defer ts.Close()
+
+		baseURL := strings.TrimSuffix(config.BaseURL, "/")
+		config.BaseURL = ts.URL
+		scraper := NewScraperImpl(colly.NewCollector(), NewWooCommerceAdapter(config), RetryPolicy{})
+
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			category := entry.Name()
+
+			t.Run(config.Store+"/"+category, func(t *testing.T) {
+				result, err := scraper.ScrapeData(context.Background(), 1, 0, category)
+				assert.NoError(t, err, "Expected no error replaying fixtures")
+				assert.Empty(t, result.Errors, "Expected every recorded page to be scraped")
+
+				// Los links relativos se resuelven contra el servidor local
+				for i := range result.Products {
+					result.Products[i].URL = strings.Replace(result.Products[i].URL, ts.URL, baseURL, 1)
+					result.Products[i].ImageURL = strings.Replace(result.Products[i].ImageURL, ts.URL, baseURL, 1)
+				}
+
+				actual, err := json.MarshalIndent(result.Products, "", "  ")
+				assert.NoError(t, err, "Expected products to marshal")
+
+				golden := filepath.Join("testdata", "golden", config.Store, category+".json")
+				if *update {
+					err = os.MkdirAll(filepath.Dir(golden), 0o755)
+					assert.NoError(t, err, "Expected golden directory to be created")
+					err = os.WriteFile(golden, append(actual, '\n'), 0o644)
+					assert.NoError(t, err, "Expected golden file to be written")
+				}
+
+				expected, err := os.ReadFile(golden)
+				if !assert.NoError(t, err, "Expected golden file, run the test with -update to create it") {
+					return
+				}
+				assert.JSONEq(t, string(expected), string(actual), "Expected products to match %s", golden)
+			})
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package scraper
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	UnitKilogram = "kg"
+	UnitLiter    = "l"
+	UnitPiece    = "un"
+)
+
+// packageSizeRegex captura el tamaño del envase en el nombre del producto,
+// con un pack opcional antes ("6 x 350 ml") o despues ("5 kg x 2").
+// EJ: "Arroz 5 kg x 2" -> cantidad 5, unidad kg, pack 2
+var packageSizeRegex = regexp.MustCompile(`(?i)(?:(\d+)\s*x\s*)?(\d+(?:[.,]\d+)?(?:/\d+)?)\s*(kilos?|kgs?|gramos?|grs?|g|mililitros?|ml|cc|litros?|lts?|l|unidades?|unid|und|un)\b\.?(?:\s*x\s*(\d+)\b)?`)
+
+// unitFactors convierte cada unidad a kg, litros o unidades
+var unitFactors = map[string]struct {
+	unit   string
+	factor float64
+}{
+	"kilo": {UnitKilogram, 1}, "kilos": {UnitKilogram, 1}, "kg": {UnitKilogram, 1}, "kgs": {UnitKilogram, 1},
+	"gramo": {UnitKilogram, 0.001}, "gramos": {UnitKilogram, 0.001}, "gr": {UnitKilogram, 0.001}, "grs": {UnitKilogram, 0.001}, "g": {UnitKilogram, 0.001},
+	"mililitro": {UnitLiter, 0.001}, "mililitros": {UnitLiter, 0.001}, "ml": {UnitLiter, 0.001}, "cc": {UnitLiter, 0.001},
+	"litro": {UnitLiter, 1}, "litros": {UnitLiter, 1}, "lt": {UnitLiter, 1}, "lts": {UnitLiter, 1}, "l": {UnitLiter, 1},
+	"unidad": {UnitPiece, 1}, "unidades": {UnitPiece, 1}, "unid": {UnitPiece, 1}, "und": {UnitPiece, 1}, "un": {UnitPiece, 1},
+}
+
+// PackageSize es el tamaño de un producto normalizado a kg, litros o unidades
+type PackageSize struct {
+	// Quantity es el contenido de cada envase en Unit
+	Quantity float64
+	Unit     string
+	// Count es la cantidad de envases del pack, 1 si no es pack
+	Count int
+}
+
+// Total es el contenido de todo el pack en Unit
+func (p PackageSize) Total() float64 {
+	return p.Quantity * float64(p.Count)
+}
+
+// UnitPrice es el precio por kg, litro o unidad, redondeado a pesos
+func (p PackageSize) UnitPrice(price int) int {
+	total := p.Total()
+	if price <= 0 || total <= 0 {
+		return 0
+	}
+	return int(math.Round(float64(price) / total))
+}
+
+// ParsePackageSize extrae el tamaño del envase del nombre del producto. Si el
+// nombre trae varios tamaños se usa el primero.
+// EJ: "Leche Entera 1 L" -> {1 l 1}, "Bebida 6 x 350 cc" -> {0.35 l 6}
+func ParsePackageSize(name string) (PackageSize, bool) {
+	match := packageSizeRegex.FindStringSubmatch(name)
+	if match == nil {
+		return PackageSize{}, false
+	}
+
+	quantity, ok := parseQuantity(match[2])
+	if !ok || quantity <= 0 {
+		return PackageSize{}, false
+	}
+
+	unit, ok := unitFactors[strings.ToLower(match[3])]
+	if !ok {
+		return PackageSize{}, false
+	}
+
+	count := 1
+	for _, group := range []string{match[1], match[4]} {
+		if group == "" {
+			continue
+		}
+		n, err := strconv.Atoi(group)
+		if err != nil || n <= 0 {
+			return PackageSize{}, false
+		}
+		count *= n
+	}
+
+	return PackageSize{
+		// Redondeo para evitar ruido de punto flotante, ej: 350 * 0.001
+		Quantity: math.Round(quantity*unit.factor*1e6) / 1e6,
+		Unit:     unit.unit,
+		Count:    count,
+	}, true
+}
+
+// parseQuantity entiende decimales con coma o punto, separador de miles con
+// punto ("1.000 g") y fracciones ("1/2 kg").
+func parseQuantity(quantity string) (float64, bool) {
+	if numerator, denominator, ok := strings.Cut(quantity, "/"); ok {
+		n, okN := parseQuantity(numerator)
+		d, okD := parseQuantity(denominator)
+		if !okN || !okD || d == 0 {
+			return 0, false
+		}
+		return n / d, true
+	}
+
+	// Un punto seguido de exactamente 3 digitos es separador de miles
+	if i := strings.Index(quantity, "."); i >= 0 && len(quantity)-i-1 == 3 {
+		quantity = strings.ReplaceAll(quantity, ".", "")
+	}
+	quantity = strings.ReplaceAll(quantity, ",", ".")
+
+	value, err := strconv.ParseFloat(quantity, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return value, true
+}
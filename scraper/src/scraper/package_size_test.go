@@ -0,0 +1,99 @@
+package scraper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePackageSize(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected PackageSize
+		ok       bool
+	}{
+		// Litros
+		{"Leche Entera 1 L", PackageSize{1, UnitLiter, 1}, true},
+		{"Leche Entera 1L", PackageSize{1, UnitLiter, 1}, true},
+		{"Leche Descremada 1 Lt.", PackageSize{1, UnitLiter, 1}, true},
+		{"Aceite Maravilla 900 ml", PackageSize{0.9, UnitLiter, 1}, true},
+		{"Bebida Cola 1,5 L", PackageSize{1.5, UnitLiter, 1}, true},
+		{"Bebida Cola 1.5 lts", PackageSize{1.5, UnitLiter, 1}, true},
+		{"Jugo Néctar 1 litro", PackageSize{1, UnitLiter, 1}, true},
+		{"Agua Mineral 3 Litros", PackageSize{3, UnitLiter, 1}, true},
+		{"Yogurt Batido 125cc", PackageSize{0.125, UnitLiter, 1}, true},
+		{"Vino Tinto 750 CC", PackageSize{0.75, UnitLiter, 1}, true},
+		{"Crema 200 mililitros", PackageSize{0.2, UnitLiter, 1}, true},
+		// Kilos y gramos
+		{"Arroz Grado 1 5 kg", PackageSize{5, UnitKilogram, 1}, true},
+		{"Azúcar 1 Kg", PackageSize{1, UnitKilogram, 1}, true},
+		{"Harina 1 kilo", PackageSize{1, UnitKilogram, 1}, true},
+		{"Papas 2 kilos", PackageSize{2, UnitKilogram, 1}, true},
+		{"Carne Molida 500 g", PackageSize{0.5, UnitKilogram, 1}, true},
+		{"Café Nescafé 170g.", PackageSize{0.17, UnitKilogram, 1}, true},
+		{"Galletas 120 gr", PackageSize{0.12, UnitKilogram, 1}, true},
+		{"Queso Laminado 250 grs", PackageSize{0.25, UnitKilogram, 1}, true},
+		{"Mantequilla 250 gramos", PackageSize{0.25, UnitKilogram, 1}, true},
+		{"Detergente en Polvo 1.000 g", PackageSize{1, UnitKilogram, 1}, true},
+		{"Pollo Entero 2,5 kg", PackageSize{2.5, UnitKilogram, 1}, true},
+		{"Palta Hass 1/2 kg", PackageSize{0.5, UnitKilogram, 1}, true},
+		// Packs
+		{"Arroz 5 kg x 2", PackageSize{5, UnitKilogram, 2}, true},
+		{"Arroz 5 kg x2", PackageSize{5, UnitKilogram, 2}, true},
+		{"Cerveza 6 x 350 ml", PackageSize{0.35, UnitLiter, 6}, true},
+		{"Cerveza Pack 6x350cc", PackageSize{0.35, UnitLiter, 6}, true},
+		{"Leche Entera 1 L x 12", PackageSize{1, UnitLiter, 12}, true},
+		{"Jugo 4 X 200 ml", PackageSize{0.2, UnitLiter, 4}, true},
+		{"Atún 3 x 160 g", PackageSize{0.16, UnitKilogram, 3}, true},
+		// Unidades
+		{"Huevos Blancos 12 un", PackageSize{12, UnitPiece, 1}, true},
+		{"Huevos Color 30 unidades", PackageSize{30, UnitPiece, 1}, true},
+		{"Pañales 40 und", PackageSize{40, UnitPiece, 1}, true},
+		{"Bolsas de Basura 10 unid.", PackageSize{10, UnitPiece, 1}, true},
+		{"Toalla Húmeda 3 x 80 un", PackageSize{80, UnitPiece, 3}, true},
+		// Primer tamaño cuando hay varios
+		{"Detergente 3 L + 500 ml gratis", PackageSize{3, UnitLiter, 1}, true},
+		// Sin tamaño
+		{"Pan Amasado", PackageSize{}, false},
+		{"Vino Reserva 2019", PackageSize{}, false},
+		{"Set de Ollas", PackageSize{}, false},
+		{"Gel 0 ml", PackageSize{}, false},
+		{"", PackageSize{}, false},
+		// Palabras que empiezan como una unidad no son unidades
+		{"Galletas 3 Gustos", PackageSize{}, false},
+		{"Cerveza 4 Latas", PackageSize{}, false},
+		{"Pizza 2 Unicornios", PackageSize{}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			size, ok := ParsePackageSize(test.name)
+
+			assert.Equal(t, test.ok, ok, "Expected package size match")
+			assert.Equal(t, test.expected, size, "Expected package size to match")
+		})
+	}
+}
+
+func TestPackageSize_UnitPrice(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     PackageSize
+		price    int
+		expected int
+	}{
+		{"OneLiter", PackageSize{1, UnitLiter, 1}, 1090, 1090},
+		{"HalfKilo", PackageSize{0.5, UnitKilogram, 1}, 2990, 5980},
+		{"Multipack", PackageSize{5, UnitKilogram, 2}, 9990, 999},
+		{"Cans", PackageSize{0.35, UnitLiter, 6}, 4990, 2376},
+		{"Pieces", PackageSize{12, UnitPiece, 1}, 3600, 300},
+		{"NoPrice", PackageSize{1, UnitLiter, 1}, 0, 0},
+		{"NoSize", PackageSize{}, 1000, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.size.UnitPrice(test.price), "Expected unit price to match")
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package scraper
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dieg0code/shared/models"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultPriceParser reconoce numeros con punto como separador de miles
+var defaultPriceParser = mustPriceParser(PriceRule{
+	// Captura numeros entre 1 y 3 digitos d{1,3} seguidos de 0 o más
+	// grupos de 3 digitos (?:\.\d{3})* antesedidos por un punto
+	// EJ: 123.456.789 sería \d{1,3} = 123 y (?:\.\d{3})* = .456.789
+	Pattern:            `\d{1,3}(?:\.\d{3})*`,
+	ThousandsSeparator: ".",
+})
+
+// priceParser extrae todos los precios de un texto, ej: "$1.000 – $2.000"
+type priceParser struct {
+	pattern            *regexp.Regexp
+	multiBuy           *regexp.Regexp
+	thousandsSeparator string
+}
+
+// priceRange es el precio leido del listado. Los productos variables muestran
+// un rango "$1.000 – $2.000", el resto tiene Min igual a Max.
+type priceRange struct {
+	Min int
+	Max int
+	// Promotion es la promocion que venia en el texto, ej: "2x$3.000"
+	Promotion *models.Promotion
+}
+
+func newPriceParser(rule PriceRule) (priceParser, error) {
+	pattern, err := regexp.Compile(rule.Pattern)
+	if err != nil {
+		return priceParser{}, err
+	}
+
+	multiBuy, err := regexp.Compile(multiBuyPrefix + `(?P<price>` + rule.Pattern + `)`)
+	if err != nil {
+		return priceParser{}, err
+	}
+
+	return priceParser{
+		pattern:            pattern,
+		multiBuy:           multiBuy,
+		thousandsSeparator: rule.ThousandsSeparator,
+	}, nil
+}
+
+func mustPriceParser(rule PriceRule) priceParser {
+	parser, err := newPriceParser(rule)
+	if err != nil {
+		panic(err)
+	}
+	return parser
+}
+
+// parseRange lee un precio, un rango o una promocion. Las promociones y sus
+// fechas se sacan del texto antes de buscar los precios para que "2x$3.000"
+// no se lea como dos precios. Sin precios devuelve un rango vacio.
+func (p priceParser) parseRange(text string) priceRange {
+	var result priceRange
+
+	promotion, rest := p.parsePromotion(text)
+	result.Promotion = promotion
+
+	if strings.TrimSpace(rest) == "" || !p.pattern.MatchString(rest) {
+		return result
+	}
+
+	prices, err := p.parse(rest)
+	if err != nil {
+		return result
+	}
+
+	result.Min, result.Max = prices[0], prices[0]
+	for _, price := range prices[1:] {
+		result.Min = min(result.Min, price)
+		result.Max = max(result.Max, price)
+	}
+
+	return result
+}
+
+// toInt remueve los separadores de miles y convierte a entero
+func (p priceParser) toInt(match string) (int, error) {
+	cleaned := match
+	if p.thousandsSeparator != "" {
+		cleaned = strings.ReplaceAll(match, p.thousandsSeparator, "")
+	}
+	return strconv.Atoi(cleaned)
+}
+
+func (p priceParser) parse(price string) ([]int, error) {
+	// Encontrar todas las coincidencias
+	matches := p.pattern.FindAllString(price, -1)
+	if len(matches) == 0 {
+		logrus.Error("no prices found in string")
+		return nil, errors.New("no prices found in string")
+	}
+
+	var prices []int
+	for _, match := range matches {
+		price, err := p.toInt(match)
+		if err != nil {
+			logrus.WithError(err).Error("error converting price to int")
+			return nil, errors.New("error converting price to int")
+		}
+		prices = append(prices, price)
+	}
+
+	return prices, nil
+}
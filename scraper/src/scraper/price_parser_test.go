@@ -0,0 +1,32 @@
+package scraper
+
+import (
+	"testing"
+
+	"github.com/dieg0code/shared/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceParser_ParseRange(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected priceRange
+	}{
+		{"$1.990", priceRange{Min: 1990, Max: 1990}},
+		{"$1.000 – $2.000", priceRange{Min: 1000, Max: 2000}},
+		{"$2.000$1.000", priceRange{Min: 1000, Max: 2000}},
+		{"2x$3.000", priceRange{Promotion: &models.Promotion{Kind: models.PromotionMultiBuy, Quantity: 2, Price: 3000}}},
+		{"$1.790 2x$3.000", priceRange{Min: 1790, Max: 1790, Promotion: &models.Promotion{Kind: models.PromotionMultiBuy, Quantity: 2, Price: 3000}}},
+		{"Lleva 3 X $ 5.000", priceRange{Promotion: &models.Promotion{Kind: models.PromotionMultiBuy, Quantity: 3, Price: 5000}}},
+		// Con una unidad no es promocion por cantidad
+		{"1x$990", priceRange{Min: 1, Max: 990}},
+		{"Oferta", priceRange{}},
+		{"", priceRange{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			assert.Equal(t, test.expected, defaultPriceParser.parseRange(test.text), "Expected price range to match")
+		})
+	}
+}
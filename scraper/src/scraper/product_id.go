@@ -0,0 +1,49 @@
+package scraper
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeName deja el nombre en minusculas, sin tildes y con los
+// separadores colapsados a un solo espacio.
+// EJ: "  Café  Nescafé 170g. " -> "cafe nescafe 170g"
+func NormalizeName(name string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, name)
+	if err != nil {
+		folded = name
+	}
+
+	fields := strings.FieldsFunc(strings.ToLower(folded), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	return strings.Join(fields, " ")
+}
+
+// ProductID genera un ID estable (UUID v5) a partir de la tienda y la
+// identidad del producto en la tienda, asi el mismo producto mantiene su ID
+// entre scrapeos y entre las categorias donde aparece (por ejemplo una de
+// temporada ademas de la suya). La identidad es el SKU, si no la URL y solo
+// si no hay ninguno de los dos el nombre normalizado; como el nombre puede
+// repetirse entre productos distintos, en ese caso se agrega la categoria.
+func ProductID(store string, category string, sku string, productURL string, name string) string {
+	var parts []string
+	switch {
+	case sku != "":
+		parts = []string{store, "sku:" + sku}
+	case productURL != "":
+		parts = []string{store, "url:" + productURL}
+	default:
+		parts = []string{store, category, "name:" + NormalizeName(name)}
+	}
+
+	key := strings.Join(parts, "/")
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(key)).String()
+}
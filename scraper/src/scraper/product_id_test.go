@@ -0,0 +1,97 @@
+package scraper
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Leche Entera 1 L", "leche entera 1 l"},
+		{"  Café  Nescafé 170g. ", "cafe nescafe 170g"},
+		{"ÑANDÚ - Pingüino", "nandu pinguino"},
+		{"Arroz 5 kg x 2", "arroz 5 kg x 2"},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			assert.Equal(t, test.expected, NormalizeName(test.input), "Expected normalized name to match")
+		})
+	}
+}
+
+func TestProductID(t *testing.T) {
+	t.Run("ProductID_Stable", func(t *testing.T) {
+		first := ProductID("cugat.cl", "despensa", "", "", "Café Nescafé 170g")
+		second := ProductID("cugat.cl", "despensa", "", "", "  cafe nescafe  170G ")
+
+		assert.Equal(t, first, second, "Expected same ID for the same normalized product")
+
+		_, err := uuid.Parse(first)
+		assert.NoError(t, err, "Expected ID to be a valid UUID")
+	})
+
+	t.Run("ProductID_DifferentCategory", func(t *testing.T) {
+		first := ProductID("cugat.cl", "despensa", "", "", "Café Nescafé 170g")
+		second := ProductID("cugat.cl", "desayuno", "", "", "Café Nescafé 170g")
+
+		assert.NotEqual(t, first, second, "Expected different IDs for different categories when only the name identifies the product")
+	})
+
+	t.Run("ProductID_DifferentStore", func(t *testing.T) {
+		first := ProductID("cugat.cl", "despensa", "", "", "Café Nescafé 170g")
+		second := ProductID("otra.cl", "despensa", "", "", "Café Nescafé 170g")
+
+		assert.NotEqual(t, first, second, "Expected different IDs for different stores")
+	})
+
+	t.Run("ProductID_SameSKUDifferentCategory", func(t *testing.T) {
+		first := ProductID("cugat.cl", "despensa", "101", "https://cugat.cl/producto/arroz/", "Arroz Grado 1")
+		second := ProductID("cugat.cl", "navidad", "101", "https://cugat.cl/producto/arroz/", "Arroz Grado 1")
+
+		assert.Equal(t, first, second, "Expected the same ID in every category the product appears in")
+	})
+
+	t.Run("ProductID_SameURLDifferentCategory", func(t *testing.T) {
+		first := ProductID("cugat.cl", "despensa", "", "https://cugat.cl/producto/arroz/", "Arroz Grado 1")
+		second := ProductID("cugat.cl", "la-gran-feria-cugat", "", "https://cugat.cl/producto/arroz/", "Arroz Grado 1")
+
+		assert.Equal(t, first, second, "Expected the same ID in every category the product appears in")
+	})
+
+	t.Run("ProductID_SameSKUDifferentStore", func(t *testing.T) {
+		first := ProductID("cugat.cl", "despensa", "101", "", "Arroz Grado 1")
+		second := ProductID("otra.cl", "despensa", "101", "", "Arroz Grado 1")
+
+		assert.NotEqual(t, first, second, "Expected different IDs for the same SKU in different stores")
+	})
+
+	t.Run("ProductID_SameNameDifferentSKU", func(t *testing.T) {
+		first := ProductID("cugat.cl", "despensa", "101", "https://cugat.cl/producto/arroz-1/", "Arroz Grado 1")
+		second := ProductID("cugat.cl", "despensa", "102", "https://cugat.cl/producto/arroz-2/", "Arroz Grado 1")
+
+		assert.NotEqual(t, first, second, "Expected different IDs for products with the same name and different SKUs")
+	})
+
+	t.Run("ProductID_SKUOverName", func(t *testing.T) {
+		first := ProductID("cugat.cl", "despensa", "101", "https://cugat.cl/producto/arroz/", "Arroz Grado 1")
+		second := ProductID("cugat.cl", "despensa", "101", "https://cugat.cl/producto/arroz-grado-1/", "Arroz Grado 1 (Oferta)")
+
+		assert.Equal(t, first, second, "Expected the SKU to keep the ID when the name or URL change")
+	})
+
+	t.Run("ProductID_URLWithoutSKU", func(t *testing.T) {
+		first := ProductID("cugat.cl", "despensa", "", "https://cugat.cl/producto/arroz-1/", "Arroz Grado 1")
+		second := ProductID("cugat.cl", "despensa", "", "https://cugat.cl/producto/arroz-2/", "Arroz Grado 1")
+		byName := ProductID("cugat.cl", "despensa", "", "", "Arroz Grado 1")
+
+		assert.NotEqual(t, first, second, "Expected the URL to tell apart products with the same name")
+		assert.NotEqual(t, byName, first, "Expected the name only as the last resort")
+	})
+}
@@ -0,0 +1,178 @@
+package scraper
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dieg0code/shared/models"
+)
+
+// multiBuyPrefix reconoce la cantidad de una promocion "2x$3.000" o "lleva 2
+// por $3.000", el precio que sigue lo reconoce el patron de la tienda
+const multiBuyPrefix = `(?i)(?:lleva\s+)?(?P<quantity>\d+)\s*(?:x|por)\s*\$?\s*`
+
+var (
+	// buyXPayYRegex reconoce "3x2", "3 por 2" y "lleva 3 paga 2". Los numeros no pueden
+	// seguir con digitos o separadores para no confundirse con "2x3.000".
+	buyXPayYRegex = regexp.MustCompile(`(?i)(?:^|[^\d.,$])(?:lleva\s+)?(\d{1,2})\s*(?:x|por|paga)\s*(\d{1,2})(?:$|[^\d.,%])`)
+	// percentOffRegex reconoce descuentos como "-20%" o "20% dcto"
+	percentOffRegex = regexp.MustCompile(`-?\s*(\d{1,2})\s*%`)
+	// validityRegex reconoce vigencias como "hasta el 31/12" o "desde 01-12-2026"
+	validityRegex = regexp.MustCompile(`(?i)(desde|hasta)\s+(?:el\s+)?(\d{1,2})[/-](\d{1,2})(?:[/-](\d{4}|\d{2}))?`)
+)
+
+// parsePromotion busca una promocion en el texto y devuelve el texto sin ella
+// ni sus fechas. Se prueban primero los formatos mas especificos: "3x2" antes
+// que "2x$3.000" y este antes que "-20%".
+func (p priceParser) parsePromotion(text string) (*models.Promotion, string) {
+	validFrom, validUntil, text := parseValidity(text, time.Now())
+
+	var promotion *models.Promotion
+	promotion, text = parseBuyXPayY(text)
+	if promotion == nil {
+		promotion, text = p.parseMultiBuy(text)
+	}
+	if promotion == nil {
+		promotion, text = parsePercentOff(text)
+	}
+	if promotion == nil {
+		return nil, text
+	}
+
+	promotion.ValidFrom = validFrom
+	promotion.ValidUntil = validUntil
+
+	return promotion, text
+}
+
+func parseBuyXPayY(text string) (*models.Promotion, string) {
+	match := buyXPayYRegex.FindStringSubmatchIndex(text)
+	if match == nil {
+		return nil, text
+	}
+
+	quantity, err := strconv.Atoi(text[match[2]:match[3]])
+	if err != nil {
+		return nil, text
+	}
+	pay, err := strconv.Atoi(text[match[4]:match[5]])
+	if err != nil || pay == 0 || pay >= quantity {
+		return nil, text
+	}
+
+	promotion := &models.Promotion{
+		Kind:        models.PromotionBuyXPayY,
+		Quantity:    quantity,
+		PayQuantity: pay,
+	}
+
+	return promotion, text[:match[2]] + " " + text[match[5]:]
+}
+
+func (p priceParser) parseMultiBuy(text string) (*models.Promotion, string) {
+	match := p.multiBuy.FindStringSubmatchIndex(text)
+	if match == nil {
+		return nil, text
+	}
+
+	quantityIndex := p.multiBuy.SubexpIndex("quantity")
+	priceIndex := p.multiBuy.SubexpIndex("price")
+
+	quantity, err := strconv.Atoi(text[match[2*quantityIndex]:match[2*quantityIndex+1]])
+	if err != nil || quantity < 2 {
+		return nil, text
+	}
+
+	price, err := p.toInt(text[match[2*priceIndex]:match[2*priceIndex+1]])
+	if err != nil || price == 0 {
+		return nil, text
+	}
+
+	promotion := &models.Promotion{
+		Kind:     models.PromotionMultiBuy,
+		Quantity: quantity,
+		Price:    price,
+	}
+
+	return promotion, text[:match[0]] + " " + text[match[1]:]
+}
+
+func parsePercentOff(text string) (*models.Promotion, string) {
+	match := percentOffRegex.FindStringSubmatchIndex(text)
+	if match == nil {
+		return nil, text
+	}
+
+	percent, err := strconv.Atoi(text[match[2]:match[3]])
+	if err != nil || percent == 0 {
+		return nil, text
+	}
+
+	promotion := &models.Promotion{
+		Kind:     models.PromotionPercentOff,
+		Quantity: 1,
+		Percent:  percent,
+	}
+
+	return promotion, text[:match[0]] + " " + text[match[1]:]
+}
+
+// parseValidity saca las fechas de vigencia del texto. Sin año se asume el
+// del dia del scraping.
+func parseValidity(text string, now time.Time) (string, string, string) {
+	var validFrom, validUntil string
+
+	for _, match := range validityRegex.FindAllStringSubmatch(text, -1) {
+		date, ok := validityDate(match[2], match[3], match[4], now)
+		if !ok {
+			continue
+		}
+
+		if strings.EqualFold(match[1], "desde") {
+			validFrom = date
+		} else {
+			validUntil = date
+		}
+	}
+
+	return validFrom, validUntil, validityRegex.ReplaceAllString(text, " ")
+}
+
+func validityDate(day, month, year string, now time.Time) (string, bool) {
+	if year == "" {
+		year = strconv.Itoa(now.Year())
+	} else if len(year) == 2 {
+		year = "20" + year
+	}
+
+	date, err := time.Parse("2006-1-2", fmt.Sprintf("%s-%s-%s", year, month, day))
+	if err != nil {
+		return "", false
+	}
+
+	return date.Format("2006-01-02"), true
+}
+
+// promotionUnitPrice es el precio de cada unidad aplicando la promocion al
+// producto
+func promotionUnitPrice(promotion models.Promotion, product models.Product) int {
+	switch promotion.Kind {
+	case models.PromotionMultiBuy:
+		return int(math.Round(float64(promotion.Price) / float64(promotion.Quantity)))
+	case models.PromotionBuyXPayY:
+		return int(math.Round(float64(product.CurrentPrice()*promotion.PayQuantity) / float64(promotion.Quantity)))
+	case models.PromotionPercentOff:
+		// Si hay precio de oferta el porcentaje ya esta aplicado
+		if product.DiscountedPrice > 0 {
+			return product.DiscountedPrice
+		}
+		return int(math.Round(float64(product.OriginalPrice*(100-promotion.Percent)) / 100))
+	case models.PromotionMemberPrice:
+		return promotion.Price
+	}
+	return product.CurrentPrice()
+}
@@ -0,0 +1,62 @@
+package scraper
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/dieg0code/shared/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceParser_ParsePromotion(t *testing.T) {
+	year := strconv.Itoa(time.Now().Year())
+
+	tests := []struct {
+		text     string
+		expected *models.Promotion
+	}{
+		{"3x2", &models.Promotion{Kind: models.PromotionBuyXPayY, Quantity: 3, PayQuantity: 2}},
+		{"Lleva 3 paga 2", &models.Promotion{Kind: models.PromotionBuyXPayY, Quantity: 3, PayQuantity: 2}},
+		{"4 por 3", &models.Promotion{Kind: models.PromotionBuyXPayY, Quantity: 4, PayQuantity: 3}},
+		{"2x$3.000", &models.Promotion{Kind: models.PromotionMultiBuy, Quantity: 2, Price: 3000}},
+		{"2x3.000", &models.Promotion{Kind: models.PromotionMultiBuy, Quantity: 2, Price: 3000}},
+		{"lleva 2 por $3.000", &models.Promotion{Kind: models.PromotionMultiBuy, Quantity: 2, Price: 3000}},
+		{"-20%", &models.Promotion{Kind: models.PromotionPercentOff, Quantity: 1, Percent: 20}},
+		{"15 % dcto", &models.Promotion{Kind: models.PromotionPercentOff, Quantity: 1, Percent: 15}},
+		{"3x2 hasta el 31/12/2026", &models.Promotion{Kind: models.PromotionBuyXPayY, Quantity: 3, PayQuantity: 2, ValidUntil: "2026-12-31"}},
+		{"2x$3.000 desde 01-12 hasta 15-12", &models.Promotion{Kind: models.PromotionMultiBuy, Quantity: 2, Price: 3000, ValidFrom: year + "-12-01", ValidUntil: year + "-12-15"}},
+		// Pagar mas de lo que se lleva no es un NxM, se lee como "lleva 2 por $3"
+		{"2x3", &models.Promotion{Kind: models.PromotionMultiBuy, Quantity: 2, Price: 3}},
+		{"Oferta", nil},
+		{"$1.990", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			promotion, _ := defaultPriceParser.parsePromotion(test.text)
+			assert.Equal(t, test.expected, promotion, "Expected promotion to match")
+		})
+	}
+}
+
+func TestPromotionUnitPrice(t *testing.T) {
+	tests := []struct {
+		name      string
+		promotion models.Promotion
+		product   models.Product
+		expected  int
+	}{
+		{"MultiBuy", models.Promotion{Kind: models.PromotionMultiBuy, Quantity: 3, Price: 5000}, models.Product{OriginalPrice: 1990}, 1667},
+		{"BuyXPayY", models.Promotion{Kind: models.PromotionBuyXPayY, Quantity: 3, PayQuantity: 2}, models.Product{OriginalPrice: 1000, DiscountedPrice: 900}, 600},
+		{"PercentOff", models.Promotion{Kind: models.PromotionPercentOff, Quantity: 1, Percent: 25}, models.Product{OriginalPrice: 1990}, 1493},
+		{"PercentOffAlreadyApplied", models.Promotion{Kind: models.PromotionPercentOff, Quantity: 1, Percent: 20}, models.Product{OriginalPrice: 1000, DiscountedPrice: 800}, 800},
+		{"MemberPrice", models.Promotion{Kind: models.PromotionMemberPrice, Quantity: 1, Price: 4990}, models.Product{OriginalPrice: 5990}, 4990},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, promotionUnitPrice(test.promotion, test.product), "Expected unit price to match")
+		})
+	}
+}
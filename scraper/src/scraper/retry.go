@@ -0,0 +1,126 @@
+package scraper
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocolly/colly"
+	"github.com/sirupsen/logrus"
+)
+
+// RetryPolicy indica cuantas veces y con que espera se reintenta una pagina
+// que fallo por un error transitorio. Sin MaxRetries no se reintenta.
+type RetryPolicy struct {
+	MaxRetries int
+	// BaseDelay es la espera del primer reintento, se duplica en cada intento
+	BaseDelay time.Duration
+	// MaxDelay limita la espera, incluida la que pide Retry-After
+	MaxDelay time.Duration
+}
+
+// retryableStatus indica si vale la pena reintentar: 429, errores 5xx o
+// errores sin respuesta como timeouts y conexiones cortadas (status 0)
+func retryableStatus(statusCode int) bool {
+	return statusCode == 0 || statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
+
+// Delay es la espera antes del reintento numero attempt (desde 1). Si el
+// servidor manda Retry-After se respeta; si no, backoff exponencial con
+// jitter para que los reintentos paralelos no lleguen juntos.
+func (p RetryPolicy) Delay(attempt int, retryAfter string, now time.Time) time.Duration {
+	if delay, ok := parseRetryAfter(retryAfter, now); ok {
+		return p.capDelay(delay)
+	}
+
+	backoff := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || backoff < p.MaxDelay); i++ {
+		backoff *= 2
+	}
+	backoff = p.capDelay(backoff)
+	if backoff <= 0 {
+		return 0
+	}
+
+	// Entre la mitad y el total del backoff
+	half := backoff / 2
+	return half + time.Duration(rand.Int64N(int64(backoff-half)+1))
+}
+
+func (p RetryPolicy) capDelay(delay time.Duration) time.Duration {
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		return p.MaxDelay
+	}
+	return delay
+}
+
+// parseRetryAfter lee Retry-After en segundos o como fecha HTTP
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	seconds, err := strconv.Atoi(value)
+	if err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if date.Before(now) {
+		return 0, true
+	}
+	return date.Sub(now), true
+}
+
+// retry vuelve a pedir la URL de la respuesta fallida si el error es
+// transitorio y quedan intentos. El numero de intento viaja en el Ctx de la
+// request, que se comparte con el reintento junto con el numero de pagina.
+// Si ctx se cancela durante la espera no se reintenta.
+func (s *ScraperImpl) retry(ctx context.Context, collector *colly.Collector, r *colly.Response) bool {
+	if ctx.Err() != nil || !retryableStatus(r.StatusCode) {
+		return false
+	}
+
+	attempt, ok := r.Ctx.GetAny("attempt").(int)
+	if !ok {
+		attempt = 0
+	}
+	if attempt >= s.Retry.MaxRetries {
+		return false
+	}
+	attempt++
+	r.Ctx.Put("attempt", attempt)
+
+	var retryAfter string
+	if r.Headers != nil {
+		retryAfter = r.Headers.Get("Retry-After")
+	}
+	delay := s.Retry.Delay(attempt, retryAfter, time.Now())
+
+	logrus.Warnf("Retrying URL %s in %s (attempt %d of %d, status %d)", r.Request.URL, delay, attempt, s.Retry.MaxRetries, r.StatusCode)
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+	}
+
+	err := collector.Request(r.Request.Method, r.Request.URL.String(), nil, r.Ctx, nil)
+	if err != nil {
+		logrus.WithError(err).Errorf("Failed to retry URL %s", r.Request.URL)
+		return false
+	}
+
+	return true
+}
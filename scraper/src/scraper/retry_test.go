@@ -0,0 +1,86 @@
+package scraper
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	policy := RetryPolicy{MaxRetries: 5, BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+
+	t.Run("Delay_ExponentialWithJitter", func(t *testing.T) {
+		tests := []struct {
+			attempt int
+			min     time.Duration
+			max     time.Duration
+		}{
+			{1, 500 * time.Millisecond, time.Second},
+			{2, time.Second, 2 * time.Second},
+			{3, 2 * time.Second, 4 * time.Second},
+			{4, 4 * time.Second, 8 * time.Second},
+			// El backoff no pasa de MaxDelay
+			{5, 5 * time.Second, 10 * time.Second},
+			{30, 5 * time.Second, 10 * time.Second},
+		}
+
+		for _, test := range tests {
+			for i := 0; i < 20; i++ {
+				delay := policy.Delay(test.attempt, "", now)
+				assert.GreaterOrEqual(t, delay, test.min, "Expected delay of attempt %d over the minimum", test.attempt)
+				assert.LessOrEqual(t, delay, test.max, "Expected delay of attempt %d under the maximum", test.attempt)
+			}
+		}
+	})
+
+	t.Run("Delay_RetryAfter", func(t *testing.T) {
+		tests := []struct {
+			retryAfter string
+			expected   time.Duration
+		}{
+			{"3", 3 * time.Second},
+			{"0", 0},
+			{now.Add(7 * time.Second).Format(http.TimeFormat), 7 * time.Second},
+			{now.Add(-time.Minute).Format(http.TimeFormat), 0},
+			// Retry-After tambien queda limitado por MaxDelay
+			{"120", 10 * time.Second},
+		}
+
+		for _, test := range tests {
+			t.Run(test.retryAfter, func(t *testing.T) {
+				assert.Equal(t, test.expected, policy.Delay(1, test.retryAfter, now), "Expected Retry-After to be honored")
+			})
+		}
+	})
+
+	t.Run("Delay_InvalidRetryAfter", func(t *testing.T) {
+		delay := policy.Delay(1, "soon", now)
+
+		assert.GreaterOrEqual(t, delay, 500*time.Millisecond, "Expected backoff when Retry-After is invalid")
+		assert.LessOrEqual(t, delay, time.Second, "Expected backoff when Retry-After is invalid")
+	})
+}
+
+func TestRetryableStatus(t *testing.T) {
+	tests := []struct {
+		status   int
+		expected bool
+	}{
+		{0, true},
+		{http.StatusTooManyRequests, true},
+		{http.StatusInternalServerError, true},
+		{http.StatusBadGateway, true},
+		{http.StatusServiceUnavailable, true},
+		{http.StatusNotFound, false},
+		{http.StatusForbidden, false},
+	}
+
+	for _, test := range tests {
+		t.Run(http.StatusText(test.status), func(t *testing.T) {
+			assert.Equal(t, test.expected, retryableStatus(test.status), "Expected retryable status to match")
+		})
+	}
+}
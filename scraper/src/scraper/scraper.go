@@ -1,8 +1,15 @@
 package scraper
 
-import "github.com/dieg0code/shared/models"
+import (
+	"context"
+
+	"github.com/dieg0code/shared/models"
+)
 
 type Scraper interface {
-	ScrapeData(protocol string, baseURL string, maxPage int, category string) ([]models.Product, error)
+	Store() string
+	Categories() []CategoryInfo
+	ScrapeData(ctx context.Context, startPage int, maxPage int, category string) (models.ScrapeResult, error)
+	DiscoverCategories(ctx context.Context) ([]models.Category, error)
 	CleanPrice(price string) ([]int, error)
 }
@@ -1,11 +1,12 @@
 package scraper
 
 import (
-	"errors"
-	"fmt"
-	"regexp"
-	"strconv"
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/dieg0code/shared/models"
 	"github.com/gocolly/colly"
@@ -14,97 +15,325 @@ import (
 
 type ScraperImpl struct {
 	Collector *colly.Collector
+	Adapter   StoreAdapter
+	Retry     RetryPolicy
 }
 
 // CleanPrice implements Scraper.
 func (s *ScraperImpl) CleanPrice(price string) ([]int, error) {
-	// Captura numeros entre 1 y 3 digitos d{1,3} seguidos de 0 o más
-	// grupos de 3 digitos (?:\.\d{3})* antesedidos por un punto
-	// EJ: 123.456.789 sería \d{1,3} = 123 y (?:\.\d{3})* = .456.789
-	re := regexp.MustCompile(`\d{1,3}(?:\.\d{3})*`)
-
-	// Encontrar todas las coincidencias
-	matches := re.FindAllString(price, -1)
-	if len(matches) == 0 {
-		logrus.Error("no prices found in string")
-		return nil, errors.New("no prices found in string")
+	return defaultPriceParser.parse(price)
+}
+
+// Store implements Scraper.
+func (s *ScraperImpl) Store() string {
+	return s.Adapter.Store()
+}
+
+// Categories implements Scraper.
+func (s *ScraperImpl) Categories() []CategoryInfo {
+	return s.Adapter.Categories()
+}
+
+// ScrapeData implements Scraper. Las paginas se descubren desde startPage
+// siguiendo el widget de paginacion y el link "siguiente"; las anteriores a
+// startPage se ignoran. maxPage es solo un limite de seguridad y con 0 no hay
+// limite. Si ctx se cancela no se piden mas paginas: el resultado trae las
+// paginas anteriores a la primera que falto, en NextPage, junto al error de ctx.
+func (s *ScraperImpl) ScrapeData(ctx context.Context, startPage int, maxPage int, category string) (models.ScrapeResult, error) {
+	if startPage < 1 {
+		startPage = 1
 	}
 
-	var prices []int
-	for _, match := range matches {
-		// Remover los puntos separadores de miles y convertir a entero
-		cleaned := strings.ReplaceAll(match, ".", "")
-		price, err := strconv.Atoi(cleaned)
-		if err != nil {
-			logrus.WithError(err).Error("error converting price to int")
-			return nil, errors.New("error converting price to int")
+	// Cada llamada usa su propio clon del collector para no acumular callbacks
+	// y poder scrapear varias categorias en paralelo. El clon comparte las
+	// reglas de limite por dominio del collector original.
+	collector := s.Collector.Clone()
+	collector.Async = true
+	// El clon comparte el registro de URLs visitadas con el original; las
+	// paginas repetidas ya se filtran con scheduled y asi una Lambda reutilizada
+	// puede volver a scrapear la misma categoria
+	collector.AllowURLRevisit = true
+
+	var mu sync.Mutex
+	pages := make(map[int][]models.Product)
+	pageErrors := make(map[int]models.ScrapeError)
+	visited := make(map[int]bool)
+	scheduled := make(map[int]bool)
+	skipped := make(map[int]bool)
+	capReached := false
+
+	pageURL := func(page int) string {
+		return s.Adapter.PageURL(category, page)
+	}
+
+	// visit encola una pagina una sola vez y respetando startPage y maxPage
+	visit := func(page int) error {
+		mu.Lock()
+		if scheduled[page] || page < startPage {
+			mu.Unlock()
+			return nil
+		}
+		if maxPage > 0 && page > maxPage {
+			if !capReached {
+				capReached = true
+				logrus.Warnf("category %s has more than %d pages, skipping the rest", category, maxPage)
+			}
+			mu.Unlock()
+			return nil
 		}
-		prices = append(prices, price)
+		if ctx.Err() != nil {
+			skipped[page] = true
+			mu.Unlock()
+			return nil
+		}
+		scheduled[page] = true
+		mu.Unlock()
+
+		requestCtx := colly.NewContext()
+		requestCtx.Put("page", page)
+
+		return collector.Request("GET", pageURL(page), nil, requestCtx, nil)
 	}
 
-	return prices, nil
-}
+	// Las paginas que ya estaban en cola cuando se cancelo ctx no se piden
+	collector.OnRequest(func(r *colly.Request) {
+		if ctx.Err() == nil {
+			return
+		}
+		r.Abort()
 
-// ScrapeData implements Scraper.
-func (s *ScraperImpl) ScrapeData(protocol string, baseURL string, maxPage int, category string) ([]models.Product, error) {
-	var products []models.Product
+		page, ok := r.Ctx.GetAny("page").(int)
+		if !ok {
+			return
+		}
 
-	s.Collector.OnHTML(".product-small.box", func(e *colly.HTMLElement) {
-		name := e.ChildText(".name.product-title a")
-		category := e.ChildText(".category")
-		originalPriceStr := e.ChildText(".price del .woocommerce-Price-amount.amount")
-		discountPriceStr := e.ChildText(".price ins .woocommerce-Price-amount.amount")
+		mu.Lock()
+		defer mu.Unlock()
+		skipped[page] = true
+	})
 
-		if discountPriceStr == "" {
-			originalPriceStr = e.ChildText(".price .woocommerce-Price-amount.amount")
+	collector.OnHTML(s.Adapter.ProductSelector(), func(e *colly.HTMLElement) {
+		page, ok := e.Request.Ctx.GetAny("page").(int)
+		if !ok {
+			logrus.Errorf("missing page number for URL %s", e.Request.URL)
+			return
 		}
 
-		// Limpiar precios originales
-		originalPrices, err := s.CleanPrice(originalPriceStr)
-		if err != nil || len(originalPrices) == 0 {
-			logrus.WithError(err).Error("error cleaning original price")
-			originalPrices = []int{0}
+		product := s.Adapter.ExtractProduct(e)
+
+		mu.Lock()
+		defer mu.Unlock()
+		pages[page] = append(pages[page], product)
+	})
+
+	// Cada pagina encontrada en la paginacion se encola en paralelo
+	collector.OnHTML(s.Adapter.PaginationSelector(), func(e *colly.HTMLElement) {
+		link, err := url.Parse(e.Request.AbsoluteURL(e.Attr("href")))
+		if err != nil {
+			return
 		}
 
-		// Limpiar precios con descuento
-		discountPrices, err := s.CleanPrice(discountPriceStr)
-		if err != nil || len(discountPrices) == 0 {
-			logrus.WithError(err).Error("error cleaning discount price")
-			discountPrices = []int{0}
+		page, ok := s.Adapter.PageNumber(link)
+		if !ok {
+			return
 		}
 
-		// Crear una entrada por cada precio original
-		for _, originalPrice := range originalPrices {
-			for _, discountPrice := range discountPrices {
-				product := models.Product{
-					Name:            name,
-					Category:        category,
-					OriginalPrice:   originalPrice,
-					DiscountedPrice: discountPrice,
-				}
-				products = append(products, product)
+		err = visit(page)
+		if err != nil {
+			logrus.WithError(err).Errorf("Failed to visit page %d at URL %s", page, pageURL(page))
+			mu.Lock()
+			defer mu.Unlock()
+			pageErrors[page] = models.ScrapeError{
+				Category: category,
+				PageURL:  pageURL(page),
+				Error:    err.Error(),
 			}
 		}
 	})
 
-	for i := 1; i <= maxPage; i++ {
-		url := fmt.Sprintf("%s://%s/%s/page/%d/", protocol, baseURL, category, i)
-		err := s.Collector.Visit(url)
+	collector.OnScraped(func(r *colly.Response) {
+		page, ok := r.Ctx.GetAny("page").(int)
+		if !ok {
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		visited[page] = true
+	})
+
+	collector.OnError(func(r *colly.Response, err error) {
+		// Las paginas que no existen no cuentan como fallo
+		if r.StatusCode == http.StatusNotFound {
+			return
+		}
+
+		// Los errores transitorios se reintentan antes de contar como fallo
+		if s.retry(ctx, collector, r) {
+			return
+		}
+
+		page, ok := r.Ctx.GetAny("page").(int)
+
+		// Un error transitorio que ya no se alcanza a reintentar queda
+		// pendiente para el siguiente scrapeo en vez de contar como fallo
+		if ctx.Err() != nil && retryableStatus(r.StatusCode) {
+			if ok {
+				mu.Lock()
+				defer mu.Unlock()
+				skipped[page] = true
+			}
+			return
+		}
+
+		logrus.WithError(err).Errorf("Failed to visit URL %s", r.Request.URL)
+		if !ok {
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		pageErrors[page] = models.ScrapeError{
+			Category:   category,
+			PageURL:    r.Request.URL.String(),
+			StatusCode: r.StatusCode,
+			Error:      err.Error(),
+		}
+	})
+
+	err := visit(startPage)
+	if err != nil {
+		logrus.WithError(err).Errorf("Failed to visit page %d at URL %s", startPage, pageURL(startPage))
+		return models.ScrapeResult{}, err
+	}
+
+	collector.Wait()
+
+	// Si se interrumpio, el scrapeo sigue desde la primera pagina que falto y
+	// las siguientes se descartan aunque hayan respondido
+	nextPage := 0
+	for page := range skipped {
+		if nextPage == 0 || page < nextPage {
+			nextPage = page
+		}
+	}
+
+	// Unir las paginas en orden para obtener el mismo resultado que un scrapeo
+	// secuencial. Las paginas con error se reportan y se omiten.
+	pageNumbers := make([]int, 0, len(scheduled))
+	for page := range scheduled {
+		pageNumbers = append(pageNumbers, page)
+	}
+	sort.Ints(pageNumbers)
+
+	result := models.ScrapeResult{NextPage: nextPage}
+	for _, page := range pageNumbers {
+		if nextPage > 0 && page >= nextPage {
+			break
+		}
+		if pageError, failed := pageErrors[page]; failed {
+			result.Pages++
+			result.Errors = append(result.Errors, pageError)
+			continue
+		}
+		if visited[page] {
+			result.Pages++
+			result.Products = append(result.Products, pages[page]...)
+		}
+	}
+
+	if nextPage > 0 {
+		logrus.Warnf("category %s interrupted, next page is %d", category, nextPage)
+		return result, ctx.Err()
+	}
+
+	return result, nil
+}
+
+// DiscoverCategories implements Scraper. Recorre los links del menu de la
+// tienda y devuelve las categorias que reconoce el adapter, con su padre si
+// es una subcategoria.
+func (s *ScraperImpl) DiscoverCategories(ctx context.Context) ([]models.Category, error) {
+	collector := s.Collector.Clone()
+	// Asincrono para que el error de un intento que se reintenta no llegue
+	// como error de Visit; los errores quedan en visitErr
+	collector.Async = true
+	collector.AllowURLRevisit = true
+
+	var mu sync.Mutex
+	var categories []models.Category
+	var visitErr error
+	index := make(map[string]int)
+
+	collector.OnRequest(func(r *colly.Request) {
+		if ctx.Err() == nil {
+			return
+		}
+		r.Abort()
+
+		mu.Lock()
+		defer mu.Unlock()
+		visitErr = ctx.Err()
+	})
+
+	collector.OnHTML("a[href]", func(e *colly.HTMLElement) {
+		link, err := url.Parse(e.Request.AbsoluteURL(e.Attr("href")))
 		if err != nil {
-			logrus.WithError(err).Errorf("Failed to visit page %d at URL %s", i, url)
-			if err.Error() == "Not Found" {
-				continue
+			return
+		}
+
+		category, ok := s.Adapter.CategoryFromURL(link)
+		if !ok {
+			return
+		}
+		category.Name = strings.Join(strings.Fields(e.Text), " ")
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		// El menu suele repetir links (escritorio y movil); se completa el
+		// nombre si el primer link era solo un icono
+		if i, ok := index[category.Slug]; ok {
+			if categories[i].Name == "" {
+				categories[i].Name = category.Name
 			}
+			return
+		}
+
+		index[category.Slug] = len(categories)
+		categories = append(categories, category)
+	})
 
-			return nil, err
+	collector.OnError(func(r *colly.Response, err error) {
+		if s.retry(ctx, collector, r) {
+			return
 		}
+
+		logrus.WithError(err).Errorf("Failed to visit URL %s", r.Request.URL)
+		mu.Lock()
+		defer mu.Unlock()
+		visitErr = err
+	})
+
+	err := collector.Visit(s.Adapter.CategoryMenuURL())
+	if err != nil {
+		logrus.WithError(err).Errorf("Failed to visit store %s", s.Adapter.Store())
+		return nil, err
+	}
+
+	collector.Wait()
+
+	if visitErr != nil {
+		return nil, visitErr
 	}
 
-	return products, nil
+	return categories, nil
 }
 
-func NewScraperImpl(collector *colly.Collector) Scraper {
+func NewScraperImpl(collector *colly.Collector, adapter StoreAdapter, retry RetryPolicy) Scraper {
 	return &ScraperImpl{
 		Collector: collector,
+		Adapter:   adapter,
+		Retry:     retry,
 	}
 }
@@ -1,10 +1,14 @@
 package scraper
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/dieg0code/shared/models"
 	"github.com/gocolly/colly"
@@ -49,25 +53,572 @@ func TestScrapeData(t *testing.T) {
 
 		// Crear un nuevo scraper
 		collector := colly.NewCollector()
-		scraper := NewScraperImpl(collector)
-
-		baseURL := strings.TrimPrefix(ts.URL, "http://")
+		scraper := NewScraperImpl(collector, cugatAdapter(t, ts.URL), RetryPolicy{})
 
 		// Usa http:// para el servidor de prueba
-		products, err := scraper.ScrapeData("http", baseURL, 1, "category")
+		result, err := scraper.ScrapeData(context.Background(), 1, 1, "category")
+		products := result.Products
+
+		assert.NoError(t, err, "Expected no error scraping data")
+		assert.Len(t, products, 2, "Expected 2 products")
+		assert.Equal(t, 1, result.Pages, "Expected 1 page visited")
+		assert.Empty(t, result.Errors, "Expected no page errors")
+
+		expectedProducts := []models.Product{
+			{
+				Name:            "Test Product",
+				Category:        "category",
+				OriginalPrice:   123456,
+				DiscountedPrice: 7890,
+				URL:             ts.URL + "/producto/test-product/",
+				ImageURL:        ts.URL + "/wp-content/uploads/test-product.jpg",
+				SKU:             "1234",
+				StockStatus:     models.StockInStock,
+			},
+			{
+				Name:          "Sold Out Product",
+				Category:      "category",
+				OriginalPrice: 990,
+				URL:           ts.URL + "/producto/sold-out-product/",
+				ImageURL:      ts.URL + "/wp-content/uploads/sold-out-product.jpg",
+				SKU:           "5678",
+				StockStatus:   models.StockOutOfStock,
+			},
+		}
+
+		assert.Equal(t, expectedProducts, products, "Expected products to match")
+	})
+
+	t.Run("Scrape_ParallelPagesKeepOrder", func(t *testing.T) {
+		ts := createPagedTestServer()
+		defer ts.Close()
+
+		collector := colly.NewCollector(colly.Async(true))
+		err := collector.Limit(&colly.LimitRule{DomainGlob: "*", Parallelism: 3})
+		assert.NoError(t, err, "Expected no error setting limit rule")
+
+		scraper := NewScraperImpl(collector, cugatAdapter(t, ts.URL), RetryPolicy{})
+
+		result, err := scraper.ScrapeData(context.Background(), 1, 0, "category")
+		products := result.Products
+
+		assert.NoError(t, err, "Expected no error scraping data")
+		assert.Len(t, products, 4, "Expected one product per discovered page")
+		assert.Equal(t, 4, result.Pages, "Expected 4 pages discovered from the pagination widget")
+		assert.Empty(t, result.Errors, "Expected no page errors")
+
+		for i, product := range products {
+			assert.Equal(t, fmt.Sprintf("Product page %d", i+1), product.Name, "Expected products in page order")
+		}
+	})
+
+	t.Run("Scrape_StartPage", func(t *testing.T) {
+		ts := createPagedTestServer()
+		defer ts.Close()
+
+		scraper := NewScraperImpl(colly.NewCollector(), cugatAdapter(t, ts.URL), RetryPolicy{})
+
+		result, err := scraper.ScrapeData(context.Background(), 3, 0, "category")
 
 		assert.NoError(t, err, "Expected no error scraping data")
-		assert.Len(t, products, 1, "Expected 1 product")
+		assert.Equal(t, 2, result.Pages, "Expected the pages before the start page to be skipped")
+		assert.Zero(t, result.NextPage, "Expected no pending pages")
+		if assert.Len(t, result.Products, 2, "Expected one product per visited page") {
+			assert.Equal(t, "Product page 3", result.Products[0].Name, "Expected to start at page 3")
+			assert.Equal(t, "Product page 4", result.Products[1].Name, "Expected pages in order")
+		}
+	})
+
+	t.Run("Scrape_MaxPageIsSafetyCap", func(t *testing.T) {
+		ts := createPagedTestServer()
+		defer ts.Close()
+
+		collector := colly.NewCollector()
+		scraper := NewScraperImpl(collector, cugatAdapter(t, ts.URL), RetryPolicy{})
+
+		result, err := scraper.ScrapeData(context.Background(), 1, 2, "category")
+
+		assert.NoError(t, err, "Expected no error scraping data")
+		assert.Equal(t, 2, result.Pages, "Expected pages beyond the cap to be skipped")
+		assert.Len(t, result.Products, 2, "Expected products from the first 2 pages")
+	})
+
+	t.Run("Scrape_FollowsNextLink", func(t *testing.T) {
+		ts := createPagedTestServer()
+		defer ts.Close()
+
+		collector := colly.NewCollector()
+		scraper := NewScraperImpl(collector, cugatAdapter(t, ts.URL), RetryPolicy{})
+
+		result, err := scraper.ScrapeData(context.Background(), 1, 0, "nextonly")
+
+		assert.NoError(t, err, "Expected no error scraping data")
+		assert.Equal(t, 3, result.Pages, "Expected pages discovered through rel=next")
+		assert.Len(t, result.Products, 3, "Expected one product per page")
+	})
+
+	t.Run("Scrape_StalePaginationLink", func(t *testing.T) {
+		ts := createPagedTestServer()
+		defer ts.Close()
+
+		collector := colly.NewCollector()
+		scraper := NewScraperImpl(collector, cugatAdapter(t, ts.URL), RetryPolicy{})
+
+		result, err := scraper.ScrapeData(context.Background(), 1, 0, "stale")
+
+		assert.NoError(t, err, "Expected no error scraping data")
+		assert.Equal(t, 4, result.Pages, "Expected the missing last page not to be counted")
+		assert.Empty(t, result.Errors, "Expected a missing page not to be reported as an error")
+	})
+
+	t.Run("Scrape_RepeatedCategory", func(t *testing.T) {
+		ts := createPagedTestServer()
+		defer ts.Close()
+
+		collector := colly.NewCollector()
+		scraper := NewScraperImpl(collector, cugatAdapter(t, ts.URL), RetryPolicy{})
+
+		// Una Lambda reutilizada vuelve a scrapear con el mismo collector
+		_, err := scraper.ScrapeData(context.Background(), 1, 0, "category")
+		assert.NoError(t, err, "Expected no error scraping data")
+
+		result, err := scraper.ScrapeData(context.Background(), 1, 0, "category")
+
+		assert.NoError(t, err, "Expected no error scraping the same category again")
+		assert.Len(t, result.Products, 4, "Expected the same products on the second run")
+	})
+
+	t.Run("Scrape_ServerError", func(t *testing.T) {
+		ts := createPagedTestServer()
+		defer ts.Close()
+
+		collector := colly.NewCollector()
+		scraper := NewScraperImpl(collector, cugatAdapter(t, ts.URL), RetryPolicy{})
+
+		result, err := scraper.ScrapeData(context.Background(), 1, 0, "broken")
+
+		assert.NoError(t, err, "Expected page errors to be reported in the result")
+		assert.Nil(t, result.Products, "Expected nil products")
+		assert.Equal(t, 1, result.Pages, "Expected only the first page to be counted")
+		assert.Len(t, result.Errors, 1, "Expected one error per failed page")
+
+		assert.Equal(t, models.ScrapeError{
+			Category:   "broken",
+			PageURL:    ts.URL + "/categoria-producto/broken/page/1/",
+			StatusCode: http.StatusInternalServerError,
+			Error:      "Internal Server Error",
+		}, result.Errors[0], "Expected structured page error")
+	})
+
+	t.Run("Scrape_PartialFailure", func(t *testing.T) {
+		ts := createPagedTestServer()
+		defer ts.Close()
+
+		collector := colly.NewCollector()
+		scraper := NewScraperImpl(collector, cugatAdapter(t, ts.URL), RetryPolicy{})
+
+		result, err := scraper.ScrapeData(context.Background(), 1, 0, "flaky")
+
+		assert.NoError(t, err, "Expected no error scraping data")
+		assert.Equal(t, 4, result.Pages, "Expected 4 pages visited")
+		assert.Len(t, result.Products, 3, "Expected products from the pages that responded")
+		assert.Len(t, result.Errors, 1, "Expected the failing page to be reported")
+		assert.Equal(t, ts.URL+"/categoria-producto/flaky/page/2/", result.Errors[0].PageURL, "Expected failing page URL")
+		assert.Equal(t, http.StatusBadGateway, result.Errors[0].StatusCode, "Expected failing page status")
+
+		for _, product := range result.Products {
+			assert.NotEqual(t, "Product page 2", product.Name, "Expected no products from the failing page")
+		}
+	})
+}
+
+func TestScrapeData_Retry(t *testing.T) {
+	retry := RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+	// failingServer responde failures veces con status antes de devolver la pagina
+	failingServer := func(failures int, status int, header http.Header) (*httptest.Server, *int) {
+		var mu sync.Mutex
+		requests := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			requests++
+			attempt := requests
+			mu.Unlock()
+
+			if attempt <= failures {
+				for key, values := range header {
+					w.Header()[key] = values
+				}
+				w.WriteHeader(status)
+				return
+			}
+
+			w.Header().Set("Content-Type", "text/html")
+			_, err := w.Write([]byte(`<div class="product-small box"><div class="name product-title"><a>Product</a></div><div class="price"><span class="woocommerce-Price-amount amount">990</span></div></div>`))
+			assert.NoError(nil, err, "Expected no error writing response")
+		}))
+		return ts, &requests
+	}
+
+	t.Run("Retry_TransientError", func(t *testing.T) {
+		ts, requests := failingServer(2, http.StatusServiceUnavailable, nil)
+		defer ts.Close()
+
+		scraper := NewScraperImpl(colly.NewCollector(colly.Async(true)), cugatAdapter(t, ts.URL), retry)
+
+		result, err := scraper.ScrapeData(context.Background(), 1, 1, "category")
+
+		assert.NoError(t, err, "Expected no error scraping data")
+		assert.Empty(t, result.Errors, "Expected the page to succeed after retrying")
+		assert.Len(t, result.Products, 1, "Expected products from the retried page")
+		assert.Equal(t, 3, *requests, "Expected two retries")
+	})
+
+	t.Run("Retry_RetryAfter", func(t *testing.T) {
+		ts, requests := failingServer(1, http.StatusTooManyRequests, http.Header{"Retry-After": {"0"}})
+		defer ts.Close()
+
+		scraper := NewScraperImpl(colly.NewCollector(), cugatAdapter(t, ts.URL), retry)
+
+		result, err := scraper.ScrapeData(context.Background(), 1, 1, "category")
+
+		assert.NoError(t, err, "Expected no error scraping data")
+		assert.Empty(t, result.Errors, "Expected the page to succeed after waiting Retry-After")
+		assert.Equal(t, 2, *requests, "Expected one retry")
+	})
+
+	t.Run("Retry_Exhausted", func(t *testing.T) {
+		ts, requests := failingServer(10, http.StatusBadGateway, nil)
+		defer ts.Close()
+
+		scraper := NewScraperImpl(colly.NewCollector(), cugatAdapter(t, ts.URL), retry)
+
+		result, err := scraper.ScrapeData(context.Background(), 1, 1, "category")
+
+		assert.NoError(t, err, "Expected page errors to be reported in the result")
+		assert.Len(t, result.Errors, 1, "Expected the page to fail after the last retry")
+		assert.Equal(t, http.StatusBadGateway, result.Errors[0].StatusCode, "Expected failing page status")
+		assert.Equal(t, 3, *requests, "Expected the first request plus two retries")
+	})
+
+	t.Run("Retry_NotRetryable", func(t *testing.T) {
+		ts, requests := failingServer(10, http.StatusForbidden, nil)
+		defer ts.Close()
+
+		scraper := NewScraperImpl(colly.NewCollector(), cugatAdapter(t, ts.URL), retry)
+
+		result, err := scraper.ScrapeData(context.Background(), 1, 1, "category")
+
+		assert.NoError(t, err, "Expected page errors to be reported in the result")
+		assert.Len(t, result.Errors, 1, "Expected the page to fail")
+		assert.Equal(t, 1, *requests, "Expected no retries for a 403")
+	})
+
+	t.Run("Retry_DiscoverCategories", func(t *testing.T) {
+		ts, requests := failingServer(1, http.StatusInternalServerError, nil)
+		defer ts.Close()
 
-		expectedProduct := models.Product{
-			Name:            "Test Product",
-			Category:        "category",
-			OriginalPrice:   123456,
-			DiscountedPrice: 7890,
+		scraper := NewScraperImpl(colly.NewCollector(), cugatAdapter(t, ts.URL), retry)
+
+		_, err := scraper.DiscoverCategories(context.Background())
+
+		assert.NoError(t, err, "Expected the menu page to succeed after retrying")
+		assert.Equal(t, 2, *requests, "Expected one retry")
+	})
+}
+
+func TestScrapeData_Context(t *testing.T) {
+	t.Run("Context_AlreadyCancelled", func(t *testing.T) {
+		var mu sync.Mutex
+		requests := 0
+		handler := pagedTestHandler()
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			requests++
+			mu.Unlock()
+			handler.ServeHTTP(w, r)
+		}))
+		defer ts.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		scraper := NewScraperImpl(colly.NewCollector(), cugatAdapter(t, ts.URL), RetryPolicy{})
+
+		result, err := scraper.ScrapeData(ctx, 1, 0, "category")
+
+		assert.ErrorIs(t, err, context.Canceled, "Expected the context error")
+		assert.Equal(t, 1, result.NextPage, "Expected to resume from the first page")
+		assert.Zero(t, result.Pages, "Expected no pages visited")
+		assert.Zero(t, requests, "Expected no requests")
+	})
+
+	t.Run("Context_CancelledBetweenPages", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// La tienda responde la primera pagina y el deadline llega antes de
+		// pedir las que encontro en la paginacion
+		handler := pagedTestHandler()
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cancel()
+			handler.ServeHTTP(w, r)
+		}))
+		defer ts.Close()
+
+		scraper := NewScraperImpl(colly.NewCollector(), cugatAdapter(t, ts.URL), RetryPolicy{})
+
+		result, err := scraper.ScrapeData(ctx, 1, 0, "category")
+
+		assert.ErrorIs(t, err, context.Canceled, "Expected the context error")
+		assert.Equal(t, 2, result.NextPage, "Expected to resume from the second page")
+		assert.Equal(t, 1, result.Pages, "Expected only the first page visited")
+		assert.Len(t, result.Products, 1, "Expected the products of the first page")
+		assert.Empty(t, result.Errors, "Expected no page errors")
+	})
+
+	t.Run("Context_NoRetryAfterCancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var mu sync.Mutex
+		requests := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			requests++
+			cancel()
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer ts.Close()
+
+		// Un reintento esperaria una hora si no se respetara ctx
+		retry := RetryPolicy{MaxRetries: 3, BaseDelay: time.Hour, MaxDelay: time.Hour}
+		scraper := NewScraperImpl(colly.NewCollector(), cugatAdapter(t, ts.URL), retry)
+
+		result, err := scraper.ScrapeData(ctx, 1, 0, "category")
+
+		assert.ErrorIs(t, err, context.Canceled, "Expected the context error")
+		assert.Equal(t, 1, result.NextPage, "Expected the failed page to stay pending")
+		assert.Empty(t, result.Errors, "Expected the pending page not to count as failed")
+		assert.Equal(t, 1, requests, "Expected no retries")
+	})
+
+	t.Run("Context_DiscoverCategories", func(t *testing.T) {
+		ts := createStoreTestServer()
+		defer ts.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		scraper := NewScraperImpl(colly.NewCollector(), cugatAdapter(t, ts.URL), RetryPolicy{})
+
+		categories, err := scraper.DiscoverCategories(ctx)
+
+		assert.ErrorIs(t, err, context.Canceled, "Expected the context error")
+		assert.Nil(t, categories, "Expected no categories")
+	})
+}
+
+func TestDiscoverCategories(t *testing.T) {
+	t.Run("Discover_Success", func(t *testing.T) {
+		ts := createStoreTestServer()
+		defer ts.Close()
+
+		collector := colly.NewCollector()
+		scraper := NewScraperImpl(collector, cugatAdapter(t, ts.URL), RetryPolicy{})
+
+		categories, err := scraper.DiscoverCategories(context.Background())
+
+		assert.NoError(t, err, "Expected no error discovering categories")
+		assert.Equal(t, []models.Category{
+			{Store: "cugat.cl", Slug: "despensa", Name: "Despensa"},
+			{Store: "cugat.cl", Slug: "arroz", Name: "Arroz y legumbres", Parent: "despensa"},
+			{Store: "cugat.cl", Slug: "navidad", Name: "Navidad"},
+		}, categories, "Expected categories in menu order without duplicates")
+	})
+
+	t.Run("Discover_StoreDown", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer ts.Close()
+
+		collector := colly.NewCollector()
+		scraper := NewScraperImpl(collector, cugatAdapter(t, ts.URL), RetryPolicy{})
+
+		categories, err := scraper.DiscoverCategories(context.Background())
+
+		assert.Error(t, err, "Expected error discovering categories")
+		assert.Nil(t, categories, "Expected nil categories")
+	})
+
+	t.Run("Discover_InvalidBaseURL", func(t *testing.T) {
+		scraper := NewScraperImpl(colly.NewCollector(), cugatAdapter(t, "cugat.cl"), RetryPolicy{})
+
+		categories, err := scraper.DiscoverCategories(context.Background())
+
+		assert.Error(t, err, "Expected error for a base URL without protocol")
+		assert.Nil(t, categories, "Expected nil categories")
+	})
+}
+
+// cugatAdapter crea el adapter de cugat.cl con la configuracion embebida
+// apuntando a baseURL
+func cugatAdapter(t *testing.T, baseURL string) StoreAdapter {
+	return NewWooCommerceAdapter(cugatConfig(t, baseURL))
+}
+
+// cugatConfig es la configuracion embebida de cugat.cl apuntando a baseURL
+func cugatConfig(t *testing.T, baseURL string) SelectorConfig {
+	configs, err := DefaultSelectorConfigs()
+	assert.NoError(t, err, "Expected embedded stores config to be valid")
+
+	for _, config := range configs {
+		if config.Store == "cugat.cl" {
+			config.BaseURL = baseURL
+			return config
+		}
+	}
+
+	t.Fatal("Expected cugat.cl in embedded stores config")
+	return SelectorConfig{}
+}
+
+// createStoreTestServer simula la portada de la tienda con el menu de categorias
+func createStoreTestServer() *httptest.Server {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, err := w.Write([]byte(`
+			<ul class="nav header-nav">
+				<li class="menu-item"><a href="/categoria-producto/despensa/">Despensa</a>
+					<ul class="sub-menu">
+						<li><a href="/categoria-producto/despensa/arroz/">Arroz y
+							legumbres</a></li>
+					</ul>
+				</li>
+				<li class="menu-item"><a href="/categoria-producto/navidad/"><i class="icon-star"></i></a></li>
+			</ul>
+			<ul class="nav mobile-nav">
+				<li><a href="/categoria-producto/despensa/">Despensa</a></li>
+				<li><a href="/categoria-producto/navidad/">Navidad</a></li>
+				<li><a href="/categoria-producto/despensa/page/2/">2</a></li>
+				<li><a href="/contacto/">Contacto</a></li>
+				<li><a href="https://otra-tienda.cl/categoria-producto/vinos/">Vinos</a></li>
+			</ul>
+		`))
+
+		assert.NoError(nil, err, "Expected no error writing response")
+	})
+
+	return httptest.NewServer(handler)
+}
+
+// pagedCategory describe una categoria del servidor de prueba: cuantas paginas
+// existen, cual anuncia el widget como ultima y si solo usa <link rel="next">
+type pagedCategory struct {
+	pages      int
+	lastLink   int
+	nextOnly   bool
+	failStatus map[int]int
+}
+
+// createPagedTestServer simula categorias de WooCommerce con widget de paginacion.
+// "category" tiene 4 paginas, "nextonly" 3 paginas enlazadas solo con rel=next,
+// "stale" anuncia una pagina 5 que no existe, "broken" responde 500 y "flaky"
+// responde 502 solo en la pagina 2.
+func createPagedTestServer() *httptest.Server {
+	return httptest.NewServer(pagedTestHandler())
+}
+
+// pagedTestHandler sirve categorias paginadas con widget de paginacion y link
+// "siguiente"; algunas categorias tienen paginas que fallan
+func pagedTestHandler() http.Handler {
+	categories := map[string]pagedCategory{
+		"category": {pages: 4, lastLink: 4},
+		"nextonly": {pages: 3, lastLink: 3, nextOnly: true},
+		"stale":    {pages: 4, lastLink: 5},
+		"broken":   {pages: 4, lastLink: 4, failStatus: map[int]int{1: http.StatusInternalServerError}},
+		"flaky":    {pages: 4, lastLink: 4, failStatus: map[int]int{2: http.StatusBadGateway}},
+	}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var name string
+		var page int
+		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		if len(parts) == 4 && parts[0] == "categoria-producto" && parts[2] == "page" {
+			name = parts[1]
+			_, err := fmt.Sscanf(parts[3], "%d", &page)
+			assert.NoError(nil, err, "Expected a page number")
+		}
+
+		category, ok := categories[name]
+		if !ok || page < 1 || page > category.pages {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if status, failed := category.failStatus[page]; failed {
+			w.WriteHeader(status)
+			return
 		}
 
-		assert.Equal(t, expectedProduct, products[0], "Expected product to match")
+		// Las primeras paginas tardan mas para forzar respuestas fuera de orden
+		time.Sleep(time.Duration(5-page) * 10 * time.Millisecond)
+
+		w.Header().Set("Content-Type", "text/html")
+		_, err := fmt.Fprintf(w, `<html><head>%s</head><body>
+			<div class="product-small box">
+				<div class="name product-title"><a href="#">Product page %d</a></div>
+				<div class="category">category</div>
+				<div class="price"><span class="woocommerce-Price-amount amount">1.000</span></div>
+			</div>
+			%s
+		</body></html>`, nextLinkTag(name, page, category), page, paginationWidget(name, page, category))
+
+		assert.NoError(nil, err, "Expected no error writing response")
 	})
+
+	return handler
+}
+
+func nextLinkTag(name string, page int, category pagedCategory) string {
+	if page >= category.lastLink {
+		return ""
+	}
+	return fmt.Sprintf(`<link rel="next" href="/categoria-producto/%s/page/%d/">`, name, page+1)
+}
+
+// paginationWidget imita el widget de WooCommerce: primera pagina, vecinas,
+// ultima pagina y link "siguiente"
+func paginationWidget(name string, page int, category pagedCategory) string {
+	if category.nextOnly {
+		return ""
+	}
+
+	link := func(class string, target int, text string) string {
+		// WooCommerce enlaza la primera pagina a la raiz de la categoria
+		href := fmt.Sprintf("/categoria-producto/%s/page/%d/", name, target)
+		if target == 1 {
+			href = fmt.Sprintf("/categoria-producto/%s/", name)
+		}
+		return fmt.Sprintf(`<li><a class="%s" href="%s">%s</a></li>`, class, href, text)
+	}
+
+	var items []string
+	for target := 1; target <= category.lastLink; target++ {
+		switch {
+		case target == page:
+			items = append(items, fmt.Sprintf(`<li><span aria-current="page" class="page-numbers current">%d</span></li>`, target))
+		case target == 1, target == category.lastLink, target == page-1, target == page+1:
+			items = append(items, link("page-numbers", target, fmt.Sprint(target)))
+		}
+	}
+	if page < category.lastLink {
+		items = append(items, link("next page-numbers", page+1, "&rarr;"))
+	}
+
+	return `<nav class="woocommerce-pagination"><ul class="page-numbers">` + strings.Join(items, "") + `</ul></nav>`
 }
 
 // TestServer to simualte a real page to scrape
@@ -76,12 +627,26 @@ func createTestServer() *httptest.Server {
 		w.Header().Set("Content-Type", "text/html")
 		_, err := w.Write([]byte(`
 			<div class="product-small box">
-				<div class="name product-title"><a href="#">Test Product</a></div>
+				<div class="box-image">
+					<a href="/producto/test-product/"><img src="data:image/svg+xml,%3Csvg%3E" data-src="/wp-content/uploads/test-product.jpg"></a>
+				</div>
+				<div class="name product-title"><a href="/producto/test-product/">Test Product</a></div>
 				<div class="category">category</div>
 				<div class="price">
 					<del><span class="woocommerce-Price-amount amount">123.456</span></del>
 					<ins><span class="woocommerce-Price-amount amount">7.890</span></ins>
 				</div>
+				<a href="?add-to-cart=1234" data-product_id="1234" class="add_to_cart_button">Agregar al carro</a>
+			</div>
+			<div class="product-small box">
+				<div class="box-image">
+					<a href="/producto/sold-out-product/"><img src="/wp-content/uploads/sold-out-product.jpg"></a>
+					<div class="out-of-stock-label">Agotado</div>
+				</div>
+				<div class="name product-title"><a href="/producto/sold-out-product/">Sold Out Product</a></div>
+				<div class="category">category</div>
+				<div class="price"><span class="woocommerce-Price-amount amount">990</span></div>
+				<a href="/producto/sold-out-product/" data-product_id="5678" class="product_type_simple">Leer más</a>
 			</div>
 		`))
 
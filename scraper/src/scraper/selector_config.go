@@ -0,0 +1,236 @@
+package scraper
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed stores/*.yaml
+var storeConfigs embed.FS
+
+// SelectorConfig son las reglas de extraccion de una tienda WooCommerce. Se
+// cargan desde YAML o JSON para poder corregir selectores sin recompilar.
+type SelectorConfig struct {
+	Store string `yaml:"store"`
+	// BaseURL es la raiz de la tienda, ej: https://cugat.cl
+	BaseURL string `yaml:"base_url"`
+	// CategoryPath es el prefijo de las URLs de categorias, ej: /categoria-producto/
+	CategoryPath string `yaml:"category_path"`
+	// MenuPath es la pagina con el menu de categorias, por defecto la portada
+	MenuPath string `yaml:"menu_path"`
+	// Pagination selecciona los links hacia otras paginas del listado
+	Pagination string `yaml:"pagination"`
+	// Item selecciona cada producto del listado
+	Item       string         `yaml:"item"`
+	Fields     FieldSelectors `yaml:"fields"`
+	Price      PriceRule      `yaml:"price"`
+	Categories []CategoryInfo `yaml:"categories"`
+	// RateLimit reemplaza el limite por defecto del collector para esta tienda
+	RateLimit RateLimit `yaml:"rate_limit,omitempty"`
+}
+
+// FieldSelectors indica de donde sale cada campo dentro de un producto
+type FieldSelectors struct {
+	Name     FieldSelector `yaml:"name"`
+	Category FieldSelector `yaml:"category"`
+	// Price es el precio cuando no hay descuento
+	Price           FieldSelector `yaml:"price"`
+	OriginalPrice   FieldSelector `yaml:"original_price"`
+	DiscountedPrice FieldSelector `yaml:"discounted_price"`
+	// Los siguientes campos son opcionales
+	URL   FieldSelector `yaml:"url"`
+	Image FieldSelector `yaml:"image"`
+	SKU   FieldSelector `yaml:"sku"`
+	// OutOfStock marca el producto como agotado si el selector encuentra algo
+	OutOfStock FieldSelector `yaml:"out_of_stock"`
+	// Promotion es el texto de una promocion, ej: "2x$3.000", "3x2" o "-20%"
+	Promotion FieldSelector `yaml:"promotion"`
+	// MemberPrice es el precio para socios o clientes del club
+	MemberPrice FieldSelector `yaml:"member_price"`
+}
+
+// FieldSelector lee el texto del elemento o, si Attr no esta vacio, su
+// atributo. Sin Selector se lee el atributo del propio producto. Attr acepta
+// varios atributos separados por coma y usa el primero con valor, ej:
+// "data-src,src" para imagenes con lazy loading.
+type FieldSelector struct {
+	Selector string `yaml:"selector"`
+	Attr     string `yaml:"attr,omitempty"`
+}
+
+// PriceRule indica como convertir el texto de un precio en enteros
+type PriceRule struct {
+	// Pattern captura cada precio del texto
+	Pattern string `yaml:"pattern"`
+	// ThousandsSeparator se quita antes de convertir a entero
+	ThousandsSeparator string `yaml:"thousands_separator"`
+}
+
+// Validate revisa que la configuracion este completa y que los selectores y
+// el patron de precios compilen. Devuelve todos los problemas juntos.
+func (c SelectorConfig) Validate() error {
+	var errs []error
+
+	if c.Store == "" {
+		errs = append(errs, errors.New("store is required"))
+	}
+
+	base, err := url.Parse(c.BaseURL)
+	if err != nil || base.Scheme == "" || base.Host == "" {
+		errs = append(errs, fmt.Errorf("base_url %q must be an absolute URL", c.BaseURL))
+	}
+
+	if !strings.HasPrefix(c.CategoryPath, "/") || !strings.HasSuffix(c.CategoryPath, "/") {
+		errs = append(errs, fmt.Errorf("category_path %q must start and end with /", c.CategoryPath))
+	}
+
+	selectors := []struct {
+		name     string
+		selector string
+		attr     string
+	}{
+		{"pagination", c.Pagination, ""},
+		{"item", c.Item, ""},
+		{"fields.name", c.Fields.Name.Selector, c.Fields.Name.Attr},
+		{"fields.category", c.Fields.Category.Selector, c.Fields.Category.Attr},
+		{"fields.price", c.Fields.Price.Selector, c.Fields.Price.Attr},
+		{"fields.original_price", c.Fields.OriginalPrice.Selector, c.Fields.OriginalPrice.Attr},
+		{"fields.discounted_price", c.Fields.DiscountedPrice.Selector, c.Fields.DiscountedPrice.Attr},
+	}
+	for _, s := range selectors {
+		// Un campo puede ser solo un atributo del producto
+		if s.selector == "" && s.attr != "" {
+			continue
+		}
+		if s.selector == "" {
+			errs = append(errs, fmt.Errorf("%s selector is required", s.name))
+			continue
+		}
+		_, err := cascadia.ParseGroup(s.selector)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s selector %q is invalid: %w", s.name, s.selector, err))
+		}
+	}
+
+	optional := []struct {
+		name     string
+		selector string
+	}{
+		{"fields.url", c.Fields.URL.Selector},
+		{"fields.image", c.Fields.Image.Selector},
+		{"fields.sku", c.Fields.SKU.Selector},
+		{"fields.out_of_stock", c.Fields.OutOfStock.Selector},
+		{"fields.promotion", c.Fields.Promotion.Selector},
+		{"fields.member_price", c.Fields.MemberPrice.Selector},
+	}
+	for _, s := range optional {
+		if s.selector == "" {
+			continue
+		}
+		_, err := cascadia.ParseGroup(s.selector)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s selector %q is invalid: %w", s.name, s.selector, err))
+		}
+	}
+
+	if c.Price.Pattern == "" {
+		errs = append(errs, errors.New("price.pattern is required"))
+	} else {
+		_, err := regexp.Compile(c.Price.Pattern)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("price.pattern %q is invalid: %w", c.Price.Pattern, err))
+		}
+	}
+
+	if c.RateLimit.Parallelism < 0 || c.RateLimit.Delay < 0 || c.RateLimit.RandomDelay < 0 {
+		errs = append(errs, errors.New("rate_limit values cannot be negative"))
+	}
+
+	if len(c.Categories) == 0 {
+		errs = append(errs, errors.New("at least one category is required"))
+	}
+	for i, category := range c.Categories {
+		if category.Category == "" {
+			errs = append(errs, fmt.Errorf("categories[%d] has no slug", i))
+		}
+	}
+
+	err = errors.Join(errs...)
+	if err != nil {
+		return fmt.Errorf("invalid config for store %q: %w", c.Store, err)
+	}
+
+	return nil
+}
+
+// LoadSelectorConfigs lee una lista de tiendas en YAML o JSON (JSON es YAML
+// valido) y valida cada una.
+func LoadSelectorConfigs(data []byte) ([]SelectorConfig, error) {
+	var configs []SelectorConfig
+	err := yaml.Unmarshal(data, &configs)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing stores config: %w", err)
+	}
+
+	if len(configs) == 0 {
+		return nil, errors.New("stores config has no stores")
+	}
+
+	for i := range configs {
+		configs[i] = configs[i].withDefaults()
+		err := configs[i].Validate()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return configs, nil
+}
+
+// DefaultSelectorConfigs devuelve las tiendas de stores/*.yaml incluidas en el binario
+func DefaultSelectorConfigs() ([]SelectorConfig, error) {
+	files, err := storeConfigs.ReadDir("stores")
+	if err != nil {
+		return nil, err
+	}
+
+	var configs []SelectorConfig
+	for _, file := range files {
+		data, err := storeConfigs.ReadFile(path.Join("stores", file.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		var config SelectorConfig
+		err = yaml.Unmarshal(data, &config)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %w", file.Name(), err)
+		}
+
+		config = config.withDefaults()
+		err = config.Validate()
+		if err != nil {
+			return nil, err
+		}
+
+		configs = append(configs, config)
+	}
+
+	return configs, nil
+}
+
+func (c SelectorConfig) withDefaults() SelectorConfig {
+	c.BaseURL = strings.TrimSuffix(c.BaseURL, "/")
+	if c.MenuPath == "" {
+		c.MenuPath = "/"
+	}
+	return c
+}
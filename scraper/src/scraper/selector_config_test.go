@@ -0,0 +1,143 @@
+package scraper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultSelectorConfigs(t *testing.T) {
+	configs, err := DefaultSelectorConfigs()
+
+	assert.NoError(t, err, "Expected embedded stores config to be valid")
+	assert.NotEmpty(t, configs, "Expected at least one store")
+
+	for _, config := range configs {
+		assert.NoError(t, config.Validate(), "Expected store %s to be valid", config.Store)
+	}
+}
+
+func TestLoadSelectorConfigs(t *testing.T) {
+	t.Run("Load_YAML", func(t *testing.T) {
+		configs, err := LoadSelectorConfigs([]byte(`
+- store: cugat.cl
+  base_url: https://cugat.cl/
+  category_path: /categoria-producto/
+  pagination: .woocommerce-pagination a.page-numbers
+  item: .product-small.box
+  fields:
+    name: {selector: .name.product-title a}
+    category: {selector: .category}
+    price: {selector: .price .amount}
+    original_price: {selector: .price del .amount}
+    discounted_price: {selector: .price ins .amount}
+  price:
+    pattern: '\d{1,3}(?:\.\d{3})*'
+    thousands_separator: .
+  categories:
+    - category: despensa
+      max_page: 10
+  rate_limit:
+    parallelism: 2
+    delay: 1s
+`))
+
+		assert.NoError(t, err, "Expected no error loading YAML config")
+		assert.Len(t, configs, 1, "Expected one store")
+		assert.Equal(t, "https://cugat.cl", configs[0].BaseURL, "Expected base URL without trailing slash")
+		assert.Equal(t, "/", configs[0].MenuPath, "Expected home page as default menu path")
+		assert.Equal(t, []CategoryInfo{{Category: "despensa", MaxPage: 10}}, configs[0].Categories, "Expected categories to match")
+		assert.Equal(t, RateLimit{Parallelism: 2, Delay: time.Second}, configs[0].RateLimit, "Expected store rate limit")
+	})
+
+	t.Run("Load_JSON", func(t *testing.T) {
+		configs, err := LoadSelectorConfigs([]byte(`[{
+			"store": "cugat.cl",
+			"base_url": "https://cugat.cl",
+			"category_path": "/categoria-producto/",
+			"pagination": "link[rel='next']",
+			"item": ".product",
+			"fields": {
+				"name": {"attr": "data-name"},
+				"category": {"selector": ".category"},
+				"price": {"selector": ".price"},
+				"original_price": {"selector": ".price del"},
+				"discounted_price": {"selector": ".price ins"}
+			},
+			"price": {"pattern": "\\d+"},
+			"categories": [{"category": "despensa"}]
+		}]`))
+
+		assert.NoError(t, err, "Expected no error loading JSON config")
+		assert.Len(t, configs, 1, "Expected one store")
+		assert.Equal(t, "data-name", configs[0].Fields.Name.Attr, "Expected attribute selector")
+	})
+
+	t.Run("Load_InvalidSyntax", func(t *testing.T) {
+		configs, err := LoadSelectorConfigs([]byte(`store: [`))
+
+		assert.Error(t, err, "Expected error parsing config")
+		assert.Nil(t, configs, "Expected nil configs")
+	})
+
+	t.Run("Load_Empty", func(t *testing.T) {
+		configs, err := LoadSelectorConfigs([]byte(`[]`))
+
+		assert.Error(t, err, "Expected error for a config without stores")
+		assert.Nil(t, configs, "Expected nil configs")
+	})
+}
+
+func TestSelectorConfig_Validate(t *testing.T) {
+	valid := func() SelectorConfig {
+		return SelectorConfig{
+			Store:        "cugat.cl",
+			BaseURL:      "https://cugat.cl",
+			CategoryPath: "/categoria-producto/",
+			Pagination:   "link[rel='next']",
+			Item:         ".product-small.box",
+			Fields: FieldSelectors{
+				Name:            FieldSelector{Selector: ".name a"},
+				Category:        FieldSelector{Selector: ".category"},
+				Price:           FieldSelector{Selector: ".price .amount"},
+				OriginalPrice:   FieldSelector{Selector: ".price del .amount"},
+				DiscountedPrice: FieldSelector{Selector: ".price ins .amount"},
+			},
+			Price:      PriceRule{Pattern: `\d+`},
+			Categories: []CategoryInfo{{Category: "despensa"}},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(c *SelectorConfig)
+		errMsg string
+	}{
+		{"Valid", func(c *SelectorConfig) {}, ""},
+		{"MissingStore", func(c *SelectorConfig) { c.Store = "" }, "store is required"},
+		{"RelativeBaseURL", func(c *SelectorConfig) { c.BaseURL = "cugat.cl" }, "base_url"},
+		{"CategoryPathWithoutSlash", func(c *SelectorConfig) { c.CategoryPath = "categoria-producto" }, "category_path"},
+		{"MissingItem", func(c *SelectorConfig) { c.Item = "" }, "item selector is required"},
+		{"InvalidSelector", func(c *SelectorConfig) { c.Fields.Name.Selector = ".name[" }, "fields.name selector"},
+		{"MissingPriceSelector", func(c *SelectorConfig) { c.Fields.DiscountedPrice.Selector = "" }, "fields.discounted_price selector is required"},
+		{"InvalidPattern", func(c *SelectorConfig) { c.Price.Pattern = `\d+(` }, "price.pattern"},
+		{"MissingCategories", func(c *SelectorConfig) { c.Categories = nil }, "at least one category"},
+		{"EmptyCategory", func(c *SelectorConfig) { c.Categories = []CategoryInfo{{}} }, "categories[0]"},
+		{"NegativeRateLimit", func(c *SelectorConfig) { c.RateLimit.Delay = -time.Second }, "rate_limit"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := valid()
+			test.modify(&config)
+
+			err := config.Validate()
+			if test.errMsg == "" {
+				assert.NoError(t, err, "Expected config to be valid")
+			} else {
+				assert.ErrorContains(t, err, test.errMsg, "Expected validation error")
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package scraper
+
+import (
+	"net/url"
+
+	"github.com/dieg0code/shared/models"
+	"github.com/gocolly/colly"
+)
+
+// StoreAdapter reune lo especifico de cada tienda: de donde salen las
+// categorias, como se arman las URLs de las paginas y como se extraen los
+// productos. ScraperImpl se encarga del crawling, la concurrencia y la paginacion.
+type StoreAdapter interface {
+	// Store identifica a la tienda, ej: cugat.cl
+	Store() string
+	// Categories es la lista estatica de categorias, respaldo del descubrimiento
+	Categories() []CategoryInfo
+	// CategoryMenuURL es la pagina que tiene el menu de categorias
+	CategoryMenuURL() string
+	// CategoryFromURL devuelve la categoria a la que apunta un link del menu
+	CategoryFromURL(link *url.URL) (models.Category, bool)
+	// PageURL arma la URL de una pagina de una categoria
+	PageURL(category string, page int) string
+	// PaginationSelector selecciona los links hacia otras paginas del listado
+	PaginationSelector() string
+	// PageNumber devuelve la pagina a la que apunta un link de paginacion
+	PageNumber(link *url.URL) (int, bool)
+	// ProductSelector selecciona cada producto del listado
+	ProductSelector() string
+	// ExtractProduct extrae el producto de un elemento del listado
+	ExtractProduct(e *colly.HTMLElement) models.Product
+}
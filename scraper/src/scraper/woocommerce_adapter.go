@@ -0,0 +1,233 @@
+package scraper
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/dieg0code/shared/models"
+	"github.com/gocolly/colly"
+	"github.com/sirupsen/logrus"
+)
+
+// pageNumberRegex extrae el numero de pagina de los links de paginacion de WooCommerce
+var pageNumberRegex = regexp.MustCompile(`/page/(\d+)/?`)
+
+// WooCommerceAdapter implementa StoreAdapter para tiendas WooCommerce. Los
+// selectores y la regla de precios salen de SelectorConfig.
+type WooCommerceAdapter struct {
+	Config SelectorConfig
+	prices priceParser
+}
+
+// Store implements StoreAdapter.
+func (w *WooCommerceAdapter) Store() string {
+	return w.Config.Store
+}
+
+// Categories implements StoreAdapter.
+func (w *WooCommerceAdapter) Categories() []CategoryInfo {
+	return w.Config.Categories
+}
+
+// CategoryMenuURL implements StoreAdapter.
+func (w *WooCommerceAdapter) CategoryMenuURL() string {
+	return w.Config.BaseURL + w.Config.MenuPath
+}
+
+// CategoryFromURL implements StoreAdapter. Los links de subcategorias tienen
+// la forma <category_path>padre/hija/.
+func (w *WooCommerceAdapter) CategoryFromURL(link *url.URL) (models.Category, bool) {
+	base, err := url.Parse(w.Config.BaseURL)
+	if err != nil || link.Host != base.Host || !strings.HasPrefix(link.Path, w.Config.CategoryPath) {
+		return models.Category{}, false
+	}
+
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(link.Path, w.Config.CategoryPath), "/"), "/")
+	for _, segment := range segments {
+		// Los links de paginacion no son categorias
+		if segment == "" || segment == "page" {
+			return models.Category{}, false
+		}
+	}
+
+	category := models.Category{
+		Store: w.Config.Store,
+		Slug:  segments[len(segments)-1],
+	}
+	if len(segments) > 1 {
+		category.Parent = segments[len(segments)-2]
+	}
+
+	return category, true
+}
+
+// PageURL implements StoreAdapter.
+func (w *WooCommerceAdapter) PageURL(category string, page int) string {
+	return w.Config.BaseURL + w.Config.CategoryPath + category + "/page/" + strconv.Itoa(page) + "/"
+}
+
+// PaginationSelector implements StoreAdapter.
+func (w *WooCommerceAdapter) PaginationSelector() string {
+	return w.Config.Pagination
+}
+
+// PageNumber implements StoreAdapter.
+func (w *WooCommerceAdapter) PageNumber(link *url.URL) (int, bool) {
+	match := pageNumberRegex.FindStringSubmatch(link.Path)
+	if match == nil {
+		return 0, false
+	}
+
+	page, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0, false
+	}
+
+	return page, true
+}
+
+// ProductSelector implements StoreAdapter.
+func (w *WooCommerceAdapter) ProductSelector() string {
+	return w.Config.Item
+}
+
+// ExtractProduct implements StoreAdapter.
+func (w *WooCommerceAdapter) ExtractProduct(e *colly.HTMLElement) models.Product {
+	fields := w.Config.Fields
+
+	originalPriceStr := fieldValue(e, fields.OriginalPrice)
+	discountPriceStr := fieldValue(e, fields.DiscountedPrice)
+
+	if discountPriceStr == "" {
+		originalPriceStr = fieldValue(e, fields.Price)
+	}
+
+	// Los productos variables muestran un rango, ej: "$1.000 – $2.000"
+	originalPrice := w.prices.parseRange(originalPriceStr)
+	discountPrice := w.prices.parseRange(discountPriceStr)
+	if originalPrice.Max == 0 && originalPrice.Promotion == nil {
+		logrus.Errorf("no prices found for product in %s", e.Request.URL)
+	}
+
+	product := models.Product{
+		Name:            fieldValue(e, fields.Name),
+		Category:        fieldValue(e, fields.Category),
+		OriginalPrice:   originalPrice.Min,
+		DiscountedPrice: discountPrice.Min,
+		URL:             absoluteURL(e, fieldValue(e, fields.URL)),
+		ImageURL:        absoluteURL(e, fieldValue(e, fields.Image)),
+		SKU:             fieldValue(e, fields.SKU),
+	}
+
+	// El rango corresponde al precio que paga el cliente
+	current := originalPrice
+	if discountPrice.Max > 0 {
+		current = discountPrice
+	}
+	if current.Max > current.Min {
+		product.MinPrice = current.Min
+		product.MaxPrice = current.Max
+	}
+
+	// La promocion puede venir junto al precio, en su propio elemento o como
+	// precio socio
+	for _, promotion := range []*models.Promotion{
+		discountPrice.Promotion,
+		originalPrice.Promotion,
+		w.prices.parseRange(fieldValue(e, fields.Promotion)).Promotion,
+		w.memberPrice(e),
+	} {
+		if promotion != nil {
+			promotion.UnitPrice = promotionUnitPrice(*promotion, product)
+			product.Promotion = promotion
+			break
+		}
+	}
+
+	// Sin selector de agotado no se sabe el stock y queda vacio
+	if fields.OutOfStock.Selector != "" {
+		product.StockStatus = models.StockInStock
+		if e.DOM.Find(fields.OutOfStock.Selector).Length() > 0 {
+			product.StockStatus = models.StockOutOfStock
+		}
+	}
+
+	return product
+}
+
+// memberPrice lee el precio para socios, si la tienda lo muestra
+func (w *WooCommerceAdapter) memberPrice(e *colly.HTMLElement) *models.Promotion {
+	price := w.prices.parseRange(fieldValue(e, w.Config.Fields.MemberPrice))
+	if price.Min == 0 {
+		return nil
+	}
+
+	return &models.Promotion{
+		Kind:     models.PromotionMemberPrice,
+		Quantity: 1,
+		Price:    price.Min,
+	}
+}
+
+// fieldValue lee un campo del producto segun su FieldSelector. Sin selector
+// se lee el atributo del propio producto.
+func fieldValue(e *colly.HTMLElement, field FieldSelector) string {
+	if field.Selector == "" && field.Attr == "" {
+		return ""
+	}
+	if field.Attr == "" {
+		// Los textos se separan con espacios: juntos "$1.790" y "2x$3.000"
+		// quedarian como "$1.7902x$3.000"
+		var texts []string
+		e.DOM.Find(field.Selector).Each(func(_ int, s *goquery.Selection) {
+			texts = append(texts, strings.TrimSpace(s.Text()))
+		})
+		return strings.TrimSpace(strings.Join(texts, " "))
+	}
+
+	for _, attr := range strings.Split(field.Attr, ",") {
+		attr = strings.TrimSpace(attr)
+
+		var value string
+		if field.Selector == "" {
+			value = strings.TrimSpace(e.Attr(attr))
+		} else {
+			value = strings.TrimSpace(e.ChildAttr(field.Selector, attr))
+		}
+
+		// Los placeholders de lazy loading son data URIs, no la imagen real
+		if value != "" && !strings.HasPrefix(value, "data:") {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// absoluteURL resuelve links relativos contra la pagina del listado
+func absoluteURL(e *colly.HTMLElement, link string) string {
+	if link == "" {
+		return ""
+	}
+	return e.Request.AbsoluteURL(link)
+}
+
+// NewWooCommerceAdapter crea el adapter de una tienda. La configuracion debe
+// venir validada (ver SelectorConfig.Validate).
+func NewWooCommerceAdapter(config SelectorConfig) StoreAdapter {
+	config = config.withDefaults()
+
+	prices, err := newPriceParser(config.Price)
+	if err != nil {
+		logrus.WithError(err).Errorf("invalid price rule for store %s, using default rule", config.Store)
+		prices = defaultPriceParser
+	}
+
+	return &WooCommerceAdapter{
+		Config: config,
+		prices: prices,
+	}
+}
@@ -0,0 +1,249 @@
+package scraper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/dieg0code/shared/models"
+	"github.com/gocolly/colly"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWooCommerceAdapter_CategoryFromURL(t *testing.T) {
+	adapter := cugatAdapter(t, "https://cugat.cl")
+
+	tests := []struct {
+		link     string
+		expected models.Category
+		ok       bool
+	}{
+		{"https://cugat.cl/categoria-producto/despensa/", models.Category{Store: "cugat.cl", Slug: "despensa"}, true},
+		{"https://cugat.cl/categoria-producto/despensa/arroz/", models.Category{Store: "cugat.cl", Slug: "arroz", Parent: "despensa"}, true},
+		{"https://cugat.cl/categoria-producto/despensa/page/2/", models.Category{}, false},
+		{"https://cugat.cl/producto/arroz-grado-1/", models.Category{}, false},
+		{"https://otra-tienda.cl/categoria-producto/vinos/", models.Category{}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.link, func(t *testing.T) {
+			link, err := url.Parse(test.link)
+			assert.NoError(t, err, "Expected a valid URL")
+
+			category, ok := adapter.CategoryFromURL(link)
+			assert.Equal(t, test.ok, ok, "Expected category match")
+			assert.Equal(t, test.expected, category, "Expected category to match")
+		})
+	}
+}
+
+func TestWooCommerceAdapter_Pages(t *testing.T) {
+	adapter := cugatAdapter(t, "https://cugat.cl/")
+
+	assert.Equal(t, "https://cugat.cl/", adapter.CategoryMenuURL(), "Expected the home page as category menu")
+	assert.Equal(t, "https://cugat.cl/categoria-producto/despensa/page/3/", adapter.PageURL("despensa", 3), "Expected WooCommerce page URL")
+
+	link, err := url.Parse(adapter.PageURL("despensa", 3))
+	assert.NoError(t, err, "Expected a valid URL")
+
+	page, ok := adapter.PageNumber(link)
+	assert.True(t, ok, "Expected a page number")
+	assert.Equal(t, 3, page, "Expected page number to match")
+
+	_, ok = adapter.PageNumber(&url.URL{Path: "/categoria-producto/despensa/"})
+	assert.False(t, ok, "Expected no page number for the category root")
+}
+
+// extractProducts sirve html desde un servidor de prueba y extrae sus productos
+func extractProducts(t *testing.T, html string, config func(baseURL string) StoreAdapter) []models.Product {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, err := w.Write([]byte(html))
+		assert.NoError(nil, err, "Expected no error writing response")
+	}))
+	defer ts.Close()
+
+	adapter := config(ts.URL)
+
+	var products []models.Product
+	collector := colly.NewCollector()
+	collector.OnHTML(adapter.ProductSelector(), func(e *colly.HTMLElement) {
+		products = append(products, adapter.ExtractProduct(e))
+	})
+
+	err := collector.Visit(ts.URL)
+	assert.NoError(t, err, "Expected no error visiting test server")
+
+	return products
+}
+
+func TestWooCommerceAdapter_ExtractProduct(t *testing.T) {
+	t.Run("Extract_CustomSelectors", func(t *testing.T) {
+		// Otro tema: el nombre viene en un atributo y los precios usan coma
+		products := extractProducts(t, `
+			<li class="product" data-name="Queso Gouda">
+				<span class="cat">Quesos</span>
+				<span class="amount">$ 4,990</span>
+			</li>
+		`, func(baseURL string) StoreAdapter {
+			return NewWooCommerceAdapter(SelectorConfig{
+				Store:        "otra-tienda.cl",
+				BaseURL:      baseURL,
+				CategoryPath: "/product-category/",
+				Item:         "li.product",
+				Fields: FieldSelectors{
+					Name:            FieldSelector{Attr: "data-name"},
+					Category:        FieldSelector{Selector: ".cat"},
+					Price:           FieldSelector{Selector: ".amount"},
+					OriginalPrice:   FieldSelector{Selector: "del .amount"},
+					DiscountedPrice: FieldSelector{Selector: "ins .amount"},
+				},
+				Price: PriceRule{Pattern: `\d{1,3}(?:,\d{3})*`, ThousandsSeparator: ","},
+			})
+		})
+
+		assert.Equal(t, []models.Product{
+			{Name: "Queso Gouda", Category: "Quesos", OriginalPrice: 4990},
+		}, products, "Expected products extracted with the configured rules")
+	})
+
+	cugat := func(baseURL string) StoreAdapter {
+		return cugatAdapter(t, baseURL)
+	}
+
+	t.Run("Extract_VariableProductRange", func(t *testing.T) {
+		products := extractProducts(t, `
+			<div class="product-small box">
+				<div class="name product-title"><a>Vino Reserva</a></div>
+				<div class="price">
+					<span class="woocommerce-Price-amount amount">$1.000</span> –
+					<span class="woocommerce-Price-amount amount">$2.000</span>
+				</div>
+			</div>
+		`, cugat)
+
+		assert.Len(t, products, 1, "Expected one product per card instead of one per price")
+		assert.Equal(t, 1000, products[0].OriginalPrice, "Expected the lowest price as original price")
+		assert.Equal(t, 0, products[0].DiscountedPrice, "Expected no discount")
+		assert.Equal(t, 1000, products[0].MinPrice, "Expected range minimum")
+		assert.Equal(t, 2000, products[0].MaxPrice, "Expected range maximum")
+		assert.Nil(t, products[0].Promotion, "Expected no promotion")
+	})
+
+	t.Run("Extract_DiscountedRange", func(t *testing.T) {
+		products := extractProducts(t, `
+			<div class="product-small box">
+				<div class="name product-title"><a>Vino Reserva</a></div>
+				<div class="price">
+					<del>
+						<span class="woocommerce-Price-amount amount">$1.000</span> –
+						<span class="woocommerce-Price-amount amount">$2.000</span>
+					</del>
+					<ins>
+						<span class="woocommerce-Price-amount amount">$800</span> –
+						<span class="woocommerce-Price-amount amount">$1.600</span>
+					</ins>
+				</div>
+			</div>
+		`, cugat)
+
+		assert.Len(t, products, 1, "Expected one product per card instead of one per price")
+		assert.Equal(t, 1000, products[0].OriginalPrice, "Expected the lowest original price")
+		assert.Equal(t, 800, products[0].DiscountedPrice, "Expected the lowest discounted price")
+		assert.Equal(t, 800, products[0].MinPrice, "Expected the range of the discounted price")
+		assert.Equal(t, 1600, products[0].MaxPrice, "Expected the range of the discounted price")
+	})
+
+	t.Run("Extract_PromotionInPrice", func(t *testing.T) {
+		products := extractProducts(t, `
+			<div class="product-small box">
+				<div class="name product-title"><a>Cerveza Lager 350 cc</a></div>
+				<div class="price">
+					<span class="woocommerce-Price-amount amount">$1.790</span><span class="woocommerce-Price-amount amount">2x$3.000</span>
+				</div>
+			</div>
+		`, cugat)
+
+		assert.Len(t, products, 1, "Expected one product per card")
+		assert.Equal(t, 1790, products[0].OriginalPrice, "Expected the regular price without the promotion")
+		assert.Equal(t, 0, products[0].MinPrice, "Expected no price range")
+		assert.Equal(t, &models.Promotion{Kind: models.PromotionMultiBuy, Quantity: 2, Price: 3000, UnitPrice: 1500}, products[0].Promotion, "Expected structured promotion")
+	})
+
+	t.Run("Extract_PromotionSelector", func(t *testing.T) {
+		products := extractProducts(t, `
+			<div class="product-small box">
+				<div class="badge">Lleva 3 x $5.000</div>
+				<div class="name product-title"><a>Jugo Naranja 1 L</a></div>
+				<div class="price"><span class="woocommerce-Price-amount amount">$1.990</span></div>
+			</div>
+		`, func(baseURL string) StoreAdapter {
+			config := cugatConfig(t, baseURL)
+			config.Fields.Promotion = FieldSelector{Selector: ".badge"}
+			return NewWooCommerceAdapter(config)
+		})
+
+		assert.Len(t, products, 1, "Expected one product per card")
+		assert.Equal(t, 1990, products[0].OriginalPrice, "Expected the regular price")
+		assert.Equal(t, &models.Promotion{Kind: models.PromotionMultiBuy, Quantity: 3, Price: 5000, UnitPrice: 1667}, products[0].Promotion, "Expected promotion from its own element")
+	})
+
+	t.Run("Extract_BadgePromotions", func(t *testing.T) {
+		products := extractProducts(t, `
+			<div class="product-small box">
+				<div class="badge-container"><span class="onsale">3x2</span> hasta el 31/12/2026</div>
+				<div class="name product-title"><a>Yogurt Frutilla</a></div>
+				<div class="price"><span class="woocommerce-Price-amount amount">$600</span></div>
+			</div>
+			<div class="product-small box">
+				<div class="badge-container"><span class="onsale">-20%</span></div>
+				<div class="name product-title"><a>Aceite de Oliva</a></div>
+				<div class="price">
+					<del><span class="woocommerce-Price-amount amount">$10.000</span></del>
+					<ins><span class="woocommerce-Price-amount amount">$8.000</span></ins>
+				</div>
+			</div>
+		`, cugat)
+
+		assert.Len(t, products, 2, "Expected one product per card")
+		assert.Equal(t, &models.Promotion{
+			Kind:        models.PromotionBuyXPayY,
+			Quantity:    3,
+			PayQuantity: 2,
+			UnitPrice:   400,
+			ValidUntil:  "2026-12-31",
+		}, products[0].Promotion, "Expected 3x2 promotion with its validity")
+		assert.Equal(t, 600, products[0].OriginalPrice, "Expected the regular price")
+
+		assert.Equal(t, &models.Promotion{
+			Kind:      models.PromotionPercentOff,
+			Quantity:  1,
+			Percent:   20,
+			UnitPrice: 8000,
+		}, products[1].Promotion, "Expected percentage promotion already applied to the sale price")
+	})
+
+	t.Run("Extract_MemberPrice", func(t *testing.T) {
+		products := extractProducts(t, `
+			<div class="product-small box">
+				<div class="name product-title"><a>Cafe Molido 250 g</a></div>
+				<div class="price"><span class="woocommerce-Price-amount amount">$5.990</span></div>
+				<div class="club-price">Precio socio $4.990</div>
+			</div>
+		`, func(baseURL string) StoreAdapter {
+			config := cugatConfig(t, baseURL)
+			config.Fields.MemberPrice = FieldSelector{Selector: ".club-price"}
+			return NewWooCommerceAdapter(config)
+		})
+
+		assert.Len(t, products, 1, "Expected one product per card")
+		assert.Equal(t, 5990, products[0].OriginalPrice, "Expected the regular price")
+		assert.Equal(t, &models.Promotion{
+			Kind:      models.PromotionMemberPrice,
+			Quantity:  1,
+			Price:     4990,
+			UnitPrice: 4990,
+		}, products[0].Promotion, "Expected member price promotion")
+	})
+}
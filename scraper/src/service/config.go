@@ -0,0 +1,30 @@
+package service
+
+import "time"
+
+type Config struct {
+	// Concurrency es la cantidad maxima de categorias que se scrapean en paralelo
+	Concurrency int
+	// MaxPages es el limite de seguridad de paginas por categoria; 0 es sin limite
+	MaxPages int
+	// GracePeriod es el tiempo que un producto descontinuado se mantiene antes de eliminarlo
+	GracePeriod time.Duration
+	// MaxFailureRatio es la proporcion de paginas fallidas (0 a 1) que se tolera
+	// antes de marcar el run como fallido
+	MaxFailureRatio float64
+	// DiscoverCategories activa el descubrimiento de categorias desde el menu de la tienda
+	DiscoverCategories bool
+	// CategoryAllowList reemplaza las categorias a scrapear cuando no esta vacia
+	CategoryAllowList []string
+	// CategoryDenyList excluye categorias del scrapeo
+	CategoryDenyList []string
+	// DeadlineMargin es el tiempo antes del deadline de la Lambda en que se deja
+	// de scrapear para guardar lo scrapeado y el checkpoint
+	DeadlineMargin time.Duration
+	// FunctionName es la Lambda que se invoca para seguir un run interrumpido;
+	// vacio deja el run interrumpido sin seguirlo
+	FunctionName string
+	// MaxInvocations es el maximo de invocaciones de un run antes de marcarlo
+	// como fallido; 0 es sin limite
+	MaxInvocations int
+}
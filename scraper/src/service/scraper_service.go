@@ -1,5 +1,12 @@
 package service
 
+import (
+	"context"
+
+	"github.com/dieg0code/shared/json/request"
+	"github.com/dieg0code/shared/models"
+)
+
 type ScraperService interface {
-	GetProducts() (bool, error)
+	GetProducts(ctx context.Context, scrapeReq request.ScrapeRequest) (models.ScrapeRun, error)
 }
@@ -1,62 +1,692 @@
 package service
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
 	"github.com/dieg0code/scraper/src/repository"
 	"github.com/dieg0code/scraper/src/scraper"
+	"github.com/dieg0code/shared/json/request"
 	"github.com/dieg0code/shared/models"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
 
 type ScraperServiceImpl struct {
-	Scraper           scraper.Scraper
-	ScraperRepository repository.ScraperRepository
+	Scrapers               []scraper.Scraper
+	ScraperRepository      repository.ScraperRepository
+	PriceHistoryRepository repository.PriceHistoryRepository
+	ScrapeRunRepository    repository.ScrapeRunRepository
+	CategoryRepository     repository.CategoryRepository
+	CheckpointRepository   repository.CheckpointRepository
+	SearchIndexRepository  repository.SearchIndexRepository
+	LambdaClient           lambdaiface.LambdaAPI
+	Config                 Config
 }
 
-// GetProducts implements ScraperService.
-func (s *ScraperServiceImpl) GetProducts() (bool, error) {
-	const baseURL string = "cugat.cl/categoria-producto"
-	protocol := "https"
+// categoryJob es una categoria a scrapear junto al scraper de su tienda. Las
+// categorias retomadas desde un checkpoint parten en startPage.
+type categoryJob struct {
+	source    scraper.Scraper
+	category  scraper.CategoryInfo
+	startPage int
+}
 
-	err := s.ScraperRepository.DeleteAll()
-	if err != nil {
-		logrus.WithError(err).Error("[ProductServiceImpl.GetProducts] Error deleting all products")
-		return false, err
-	}
+type categoryResult struct {
+	result  models.ScrapeResult
+	err     error
+	maxPage int
+}
+
+// interrupted indica si el scrapeo de una categoria se corto por el deadline
+// o la cancelacion de ctx y no por un error de la tienda
+func (r categoryResult) interrupted() bool {
+	return errors.Is(r.err, context.Canceled) || errors.Is(r.err, context.DeadlineExceeded)
+}
+
+// GetProducts implements ScraperService. Si ctx tiene deadline el scrapeo se
+// detiene Config.DeadlineMargin antes, se guarda lo scrapeado y un checkpoint
+// con las categorias pendientes y el scraper se vuelve a invocar para seguir
+// el mismo run. El run se completa cuando no quedan categorias pendientes.
+func (s *ScraperServiceImpl) GetProducts(ctx context.Context, scrapeReq request.ScrapeRequest) (models.ScrapeRun, error) {
+	now := time.Now()
 
-	logrus.Info("[ProductServiceImpl.UpdateData] Scraping data started")
-	for _, categoryInfo := range scraper.Categories {
-		products, err := s.Scraper.ScrapeData(protocol, baseURL, categoryInfo.MaxPage, categoryInfo.Category)
+	var run models.ScrapeRun
+	var checkpoint models.ScrapeCheckpoint
+	var jobs []categoryJob
+	var err error
+	if scrapeReq.Resume {
+		run, checkpoint, jobs, err = s.resumeRun(ctx, scrapeReq)
 		if err != nil {
-			logrus.WithError(err).Error("[ProductServiceImpl.UpdateData] Error scraping data")
-			return false, err
+			logrus.WithError(err).Error("[ScraperServiceImpl.GetProducts] Error resuming scrape run")
+			return run, err
+		}
+		logrus.WithFields(logrus.Fields{
+			"run_id":     run.RunID,
+			"invocation": checkpoint.Invocations + 1,
+		}).Info("[ScraperServiceImpl.GetProducts] Scraping data resumed")
+	} else {
+		run, err = s.startRun(ctx, scrapeReq, now)
+		if err != nil {
+			logrus.WithError(err).Error("[ScraperServiceImpl.GetProducts] Error saving scrape run")
+			return run, err
+		}
+
+		logrus.WithField("run_id", run.RunID).Info("[ScraperServiceImpl.GetProducts] Scraping data started")
+		for _, source := range s.Scrapers {
+			for _, category := range s.resolveCategories(ctx, source, now) {
+				jobs = append(jobs, categoryJob{source: source, category: category, startPage: 1})
+			}
+		}
+	}
+
+	scrapeCtx, cancel := s.scrapeContext(ctx)
+	results := s.scrapeCategories(scrapeCtx, jobs)
+	cancel()
+
+	var pending []models.CategoryCursor
+	for i, result := range results {
+		summary := summarizeCategory(jobs[i].source.Store(), jobs[i].category.Category, result)
+		run.Categories = mergeCategory(run.Categories, summary)
+		run.Products += summary.Products
+		run.Pages += summary.Pages
+		run.FailedPages += summary.FailedPages
+
+		if result.interrupted() {
+			pending = append(pending, models.CategoryCursor{
+				Store:    jobs[i].source.Store(),
+				Category: jobs[i].category.Category,
+				NextPage: max(result.result.NextPage, 1),
+				MaxPage:  result.maxPage,
+			})
 		}
-		for _, product := range products {
+	}
+
+	// Las categorias o paginas con error no detienen el scrapeo; el run solo
+	// falla cuando la proporcion de paginas fallidas supera el umbral
+	failureRatio := run.FailureRatio()
+	if failureRatio > s.Config.MaxFailureRatio {
+		logrus.WithFields(logrus.Fields{
+			"run_id":        run.RunID,
+			"failed_pages":  run.FailedPages,
+			"pages":         run.Pages,
+			"failure_ratio": failureRatio,
+		}).Error("[ScraperServiceImpl.GetProducts] Too many failed pages")
+		return s.finishRun(ctx, run, fmt.Errorf("failure ratio %.2f exceeds threshold %.2f", failureRatio, s.Config.MaxFailureRatio))
+	}
+
+	// Upsert de lo scrapeado: la tabla nunca queda vacia durante la actualizacion
+	seen := make(map[string]bool)
+	var products []models.Product
+	var observations []models.PriceObservation
+	for i, result := range results {
+		store := jobs[i].source.Store()
+		for _, product := range result.result.Products {
+			// Un producto que aparece en varias categorias, por ejemplo en una de
+			// temporada, tiene un solo ID y se guarda con la primera
+			productID := scraper.ProductID(store, jobs[i].category.Category, product.SKU, product.URL, product.Name)
+			if seen[productID] {
+				continue
+			}
+
 			productModel := models.Product{
-				ProductID:       uuid.New().String(),
+				ProductID:       productID,
+				Store:           store,
 				Name:            product.Name,
 				Category:        product.Category,
+				CategorySlug:    jobs[i].category.Category,
 				OriginalPrice:   product.OriginalPrice,
 				DiscountedPrice: product.DiscountedPrice,
-				LastUpdated:     time.Now().Format("02-01-2006"),
+				MinPrice:        product.MinPrice,
+				MaxPrice:        product.MaxPrice,
+				Promotion:       product.Promotion,
+				URL:             product.URL,
+				ImageURL:        product.ImageURL,
+				SKU:             product.SKU,
+				StockStatus:     product.StockStatus,
+				LastUpdated:     now.Format("02-01-2006"),
+				LastSeenRunID:   run.RunID,
+			}
+
+			// Precio por kg, litro o unidad para comparar envases distintos
+			size, ok := scraper.ParsePackageSize(product.Name)
+			if ok {
+				productModel.PackageQuantity = size.Quantity
+				productModel.PackageUnit = size.Unit
+				productModel.PackageCount = size.Count
+				productModel.UnitPrice = size.UnitPrice(productModel.CurrentPrice())
+			}
+			productModel.SetListing()
+
+			products = append(products, productModel)
+			seen[productModel.ProductID] = true
+
+			observations = append(observations, models.PriceObservation{
+				ProductID:       productModel.ProductID,
+				Date:            now.Format("2006-01-02"),
+				OriginalPrice:   productModel.OriginalPrice,
+				DiscountedPrice: productModel.DiscountedPrice,
+			})
+		}
+	}
+
+	if len(products) > 0 {
+		err = s.ScraperRepository.CreateMany(ctx, products)
+		if err != nil {
+			logBatchError(err, "[ScraperServiceImpl.GetProducts] Error creating products")
+			return s.finishRun(ctx, run, err)
+		}
+
+		err = s.PriceHistoryRepository.CreateMany(ctx, observations)
+		if err != nil {
+			logBatchError(err, "[ScraperServiceImpl.GetProducts] Error saving price observations")
+			return s.finishRun(ctx, run, err)
+		}
+
+		// Un producto que falta en el indice solo no aparece en la busqueda,
+		// asi que el run sigue; el proximo run lo vuelve a indexar
+		err = s.SearchIndexRepository.Index(ctx, products)
+		if err != nil {
+			logBatchError(err, "[ScraperServiceImpl.GetProducts] Error indexing products for search")
+		}
+	}
+
+	// Sin todas las categorias no se sabe que productos desaparecieron
+	if len(pending) > 0 {
+		return s.interruptRun(ctx, run, checkpoint.Invocations+1, pending)
+	}
+
+	s.saveCategoryStats(ctx, run, now)
+
+	err = s.syncDiscontinued(ctx, run.RunID, seen, failedCategories(run), now)
+	if err != nil {
+		logrus.WithError(err).Error("[ScraperServiceImpl.GetProducts] Error syncing discontinued products")
+		return s.finishRun(ctx, run, err)
+	}
+
+	logrus.WithField("run_id", run.RunID).Info("[ScraperServiceImpl.GetProducts] Data scraped successfully")
+	return s.finishRun(ctx, run, nil)
+}
+
+// startRun registra el inicio del scrapeo. Si la API no envio un run id
+// (por ejemplo una invocacion manual) se genera uno nuevo.
+func (s *ScraperServiceImpl) startRun(ctx context.Context, scrapeReq request.ScrapeRequest, now time.Time) (models.ScrapeRun, error) {
+	run := models.ScrapeRun{
+		RunID:       scrapeReq.RunID,
+		TriggeredBy: scrapeReq.TriggeredBy,
+		StartedAt:   now.Format(time.RFC3339),
+		Status:      models.ScrapeRunRunning,
+		Categories:  []models.CategoryRun{},
+	}
+	if run.RunID == "" {
+		run.RunID = uuid.New().String()
+	}
+
+	_, err := s.ScrapeRunRepository.Save(ctx, run)
+	return run, err
+}
+
+// finishRun guarda el estado final del scrapeo y devuelve runErr si lo hubo.
+// Se guarda aunque ctx se haya cancelado para que el run no quede corriendo.
+// Un run terminado, con exito o no, ya no se retoma, asi que tambien se borra
+// su checkpoint si lo tenia.
+func (s *ScraperServiceImpl) finishRun(ctx context.Context, run models.ScrapeRun, runErr error) (models.ScrapeRun, error) {
+	ctx = context.WithoutCancel(ctx)
+	s.deleteCheckpoint(ctx, run.RunID)
+
+	run.FinishedAt = time.Now().Format(time.RFC3339)
+	run.Status = models.ScrapeRunSucceeded
+	if runErr != nil {
+		run.Status = models.ScrapeRunFailed
+		run.Error = runErr.Error()
+	}
+
+	_, err := s.ScrapeRunRepository.Save(ctx, run)
+	if err != nil {
+		logrus.WithError(err).Error("[ScraperServiceImpl.finishRun] Error saving scrape run")
+		if runErr == nil {
+			return run, err
+		}
+	}
+
+	return run, runErr
+}
+
+// interruptRun guarda un checkpoint con las categorias que faltan, deja el
+// run como interrumpido y vuelve a invocar el scraper para seguirlo. Igual
+// que finishRun, se guarda aunque ctx se haya cancelado. Despues de
+// Config.MaxInvocations invocaciones el run falla para no seguir para siempre.
+func (s *ScraperServiceImpl) interruptRun(ctx context.Context, run models.ScrapeRun, invocations int, pending []models.CategoryCursor) (models.ScrapeRun, error) {
+	ctx = context.WithoutCancel(ctx)
+	if s.Config.MaxInvocations > 0 && invocations >= s.Config.MaxInvocations {
+		logrus.WithField("run_id", run.RunID).Errorf("[ScraperServiceImpl.interruptRun] %d categories pending after %d invocations", len(pending), invocations)
+		return s.finishRun(ctx, run, fmt.Errorf("run not finished after %d invocations", invocations))
+	}
+
+	checkpoint := models.ScrapeCheckpoint{
+		RunID:       run.RunID,
+		SavedAt:     time.Now().Format(time.RFC3339),
+		Invocations: invocations,
+		Pending:     pending,
+	}
+
+	_, err := s.CheckpointRepository.Save(ctx, checkpoint)
+	if err != nil {
+		logrus.WithError(err).Error("[ScraperServiceImpl.interruptRun] Error saving checkpoint")
+		return s.finishRun(ctx, run, err)
+	}
+
+	run.Status = models.ScrapeRunInterrupted
+	_, err = s.ScrapeRunRepository.Save(ctx, run)
+	if err != nil {
+		logrus.WithError(err).Error("[ScraperServiceImpl.interruptRun] Error saving scrape run")
+		return run, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"run_id":  run.RunID,
+		"pending": len(pending),
+	}).Warn("[ScraperServiceImpl.interruptRun] Scraping interrupted, checkpoint saved")
+
+	err = s.invokeResume(ctx, run)
+	if err != nil {
+		logrus.WithError(err).Error("[ScraperServiceImpl.interruptRun] Error invoking scraper to resume run")
+		return s.finishRun(ctx, run, err)
+	}
+
+	return run, nil
+}
+
+// resumeRun carga el run y el checkpoint que dejo la invocacion anterior y
+// arma los trabajos de las categorias pendientes desde la pagina donde quedaron
+func (s *ScraperServiceImpl) resumeRun(ctx context.Context, scrapeReq request.ScrapeRequest) (models.ScrapeRun, models.ScrapeCheckpoint, []categoryJob, error) {
+	run, err := s.ScrapeRunRepository.GetByID(ctx, scrapeReq.RunID)
+	if err != nil {
+		return run, models.ScrapeCheckpoint{}, nil, err
+	}
+
+	checkpoint, err := s.CheckpointRepository.GetByRunID(ctx, run.RunID)
+	if err != nil {
+		run, err = s.finishRun(ctx, run, err)
+		return run, checkpoint, nil, err
+	}
+
+	sources := make(map[string]scraper.Scraper)
+	for _, source := range s.Scrapers {
+		sources[source.Store()] = source
+	}
+
+	var jobs []categoryJob
+	for _, cursor := range checkpoint.Pending {
+		source, ok := sources[cursor.Store]
+		if !ok {
+			logrus.Warnf("[ScraperServiceImpl.resumeRun] Store %s is no longer configured, skipping category %s", cursor.Store, cursor.Category)
+			continue
+		}
+
+		jobs = append(jobs, categoryJob{
+			source:    source,
+			category:  scraper.CategoryInfo{Category: cursor.Category, MaxPage: cursor.MaxPage},
+			startPage: cursor.NextPage,
+		})
+	}
+
+	run.Status = models.ScrapeRunRunning
+	_, err = s.ScrapeRunRepository.Save(ctx, run)
+	return run, checkpoint, jobs, err
+}
+
+// invokeResume invoca el scraper de forma asincrona para que siga el run desde
+// su checkpoint. Sin Config.FunctionName el run queda interrumpido y se puede
+// retomar invocando el scraper con resume.
+func (s *ScraperServiceImpl) invokeResume(ctx context.Context, run models.ScrapeRun) error {
+	if s.Config.FunctionName == "" || s.LambdaClient == nil {
+		logrus.WithField("run_id", run.RunID).Warn("[ScraperServiceImpl.invokeResume] No function to invoke, run must be resumed manually")
+		return nil
+	}
+
+	payload, err := json.Marshal(request.ScrapeRequest{
+		RunID:       run.RunID,
+		TriggeredBy: run.TriggeredBy,
+		Resume:      true,
+	})
+	if err != nil {
+		return err
+	}
+
+	input := &lambda.InvokeInput{
+		FunctionName:   aws.String(s.Config.FunctionName),
+		InvocationType: aws.String("Event"),
+		Payload:        payload,
+	}
+
+	_, err = s.LambdaClient.InvokeWithContext(ctx, input)
+	return err
+}
+
+// deleteCheckpoint borra el checkpoint de un run terminado. Si falla solo se
+// registra, el run ya no lo va a usar.
+func (s *ScraperServiceImpl) deleteCheckpoint(ctx context.Context, runID string) {
+	err := s.CheckpointRepository.Delete(ctx, runID)
+	if err != nil {
+		logrus.WithError(err).Warnf("[ScraperServiceImpl.deleteCheckpoint] Error deleting checkpoint of run %s", runID)
+	}
+}
+
+// scrapeContext adelanta el deadline de ctx en Config.DeadlineMargin para que
+// quede tiempo de guardar lo scrapeado antes de que se corte la Lambda
+func (s *ScraperServiceImpl) scrapeContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if !ok || s.Config.DeadlineMargin <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithDeadline(ctx, deadline.Add(-s.Config.DeadlineMargin))
+}
+
+// resolveCategories devuelve las categorias a scrapear de una tienda. Con
+// Config.DiscoverCategories se descubren desde el menu de la tienda y si eso
+// falla se usan las ultimas guardadas; la lista estatica del adapter queda
+// como respaldo. Config.CategoryAllowList reemplaza la lista y
+// Config.CategoryDenyList excluye.
+func (s *ScraperServiceImpl) resolveCategories(ctx context.Context, source scraper.Scraper, now time.Time) []scraper.CategoryInfo {
+	static := source.Categories()
+	categories := static
+
+	if s.Config.DiscoverCategories {
+		discovered, err := s.discoverCategories(ctx, source, now)
+		if err != nil || len(discovered) == 0 {
+			logrus.WithError(err).Warnf("[ScraperServiceImpl.resolveCategories] No categories discovered for %s, using static list", source.Store())
+		} else {
+			categories = discovered
+		}
+	}
+
+	// MaxPage de la lista estatica se mantiene como limite por categoria
+	maxPages := make(map[string]int)
+	for _, category := range static {
+		maxPages[category.Category] = category.MaxPage
+	}
+
+	if len(s.Config.CategoryAllowList) > 0 {
+		categories = nil
+		for _, slug := range s.Config.CategoryAllowList {
+			categories = append(categories, scraper.CategoryInfo{Category: slug})
+		}
+	}
+
+	denied := make(map[string]bool)
+	for _, slug := range s.Config.CategoryDenyList {
+		denied[slug] = true
+	}
+
+	var resolved []scraper.CategoryInfo
+	for _, category := range categories {
+		if denied[category.Category] {
+			continue
+		}
+		if category.MaxPage == 0 {
+			category.MaxPage = maxPages[category.Category]
+		}
+		resolved = append(resolved, category)
+	}
+
+	return resolved
+}
+
+// discoverCategories descubre las categorias de la tienda y las guarda. Si no
+// se pueden descubrir devuelve las guardadas en el ultimo scrapeo. Solo se
+// scrapean las categorias de primer nivel porque ya incluyen sus subcategorias.
+func (s *ScraperServiceImpl) discoverCategories(ctx context.Context, source scraper.Scraper, now time.Time) ([]scraper.CategoryInfo, error) {
+	found, err := source.DiscoverCategories(ctx)
+	if err != nil || len(found) == 0 {
+		logrus.WithError(err).Warnf("[ScraperServiceImpl.discoverCategories] Error discovering categories for %s, using stored categories", source.Store())
+		found, err = s.CategoryRepository.GetByStore(ctx, source.Store())
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		for _, category := range found {
+			category.LastSeen = now.Format(time.RFC3339)
+			_, err := s.CategoryRepository.Save(ctx, category)
+			if err != nil {
+				logrus.WithError(err).Errorf("[ScraperServiceImpl.discoverCategories] Error saving category %s", category.Slug)
 			}
-			_, err := s.ScraperRepository.Create(productModel)
+		}
+	}
+
+	var categories []scraper.CategoryInfo
+	for _, category := range found {
+		if category.Parent == "" {
+			categories = append(categories, scraper.CategoryInfo{Category: category.Slug})
+		}
+	}
+
+	return categories, nil
+}
+
+// saveCategoryStats guarda en cada categoria cuantos productos tuvo en el run.
+// Las categorias con paginas fallidas conservan los datos del ultimo run en
+// que se scrapearon completas.
+func (s *ScraperServiceImpl) saveCategoryStats(ctx context.Context, run models.ScrapeRun, now time.Time) {
+	for _, category := range run.Categories {
+		if category.FailedPages > 0 || len(category.Errors) > 0 {
+			continue
+		}
+
+		err := s.CategoryRepository.UpdateStats(ctx, category.Store, category.Category, category.Products, now.Format(time.RFC3339))
+		if err != nil {
+			logrus.WithError(err).Errorf("[ScraperServiceImpl.saveCategoryStats] Error updating stats of category %s", category.Category)
+		}
+	}
+}
+
+// mergeCategory agrega el resumen de una categoria al run. Si la categoria ya
+// se habia scrapeado en parte en una invocacion anterior se suman los totales.
+func mergeCategory(categories []models.CategoryRun, summary models.CategoryRun) []models.CategoryRun {
+	for i, category := range categories {
+		if category.Store != summary.Store || category.Category != summary.Category {
+			continue
+		}
+
+		categories[i].Products += summary.Products
+		categories[i].Pages += summary.Pages
+		categories[i].FailedPages += summary.FailedPages
+		categories[i].Errors = append(categories[i].Errors, summary.Errors...)
+		return categories
+	}
+
+	return append(categories, summary)
+}
+
+// summarizeCategory arma el resumen de una categoria. Si la categoria no se
+// pudo scrapear en absoluto se cuenta como una pagina fallida.
+func summarizeCategory(store string, category string, result categoryResult) models.CategoryRun {
+	summary := models.CategoryRun{
+		Store:       store,
+		Category:    category,
+		Products:    len(result.result.Products),
+		Pages:       result.result.Pages,
+		FailedPages: len(result.result.Errors),
+		Errors:      result.result.Errors,
+	}
+
+	for _, scrapeErr := range result.result.Errors {
+		logrus.WithFields(logrus.Fields{
+			"category":    scrapeErr.Category,
+			"page_url":    scrapeErr.PageURL,
+			"status_code": scrapeErr.StatusCode,
+		}).Errorf("[ScraperServiceImpl.GetProducts] Error scraping page: %s", scrapeErr.Error)
+	}
+
+	if result.interrupted() {
+		logrus.Warnf("[ScraperServiceImpl.GetProducts] Category %s interrupted at page %d", category, result.result.NextPage)
+		return summary
+	}
+
+	if result.err != nil {
+		logrus.WithError(result.err).Errorf("[ScraperServiceImpl.GetProducts] Error scraping category %s", category)
+		summary.Pages++
+		summary.FailedPages++
+		summary.Errors = append(summary.Errors, models.ScrapeError{
+			Category: category,
+			Error:    result.err.Error(),
+		})
+	}
+
+	return summary
+}
+
+// failedCategories devuelve las categorias del run con paginas fallidas,
+// indexadas por tienda y slug
+func failedCategories(run models.ScrapeRun) map[string]bool {
+	failed := make(map[string]bool)
+	for _, category := range run.Categories {
+		if category.FailedPages > 0 || len(category.Errors) > 0 {
+			failed[category.Store+"/"+category.Category] = true
+		}
+	}
+	return failed
+}
+
+// syncDiscontinued marca como descontinuados los productos que no aparecieron
+// en este scrapeo y elimina los que llevan mas de Config.GracePeriod descontinuados.
+// Los productos vistos en invocaciones anteriores del mismo run tienen su id
+// en LastSeenRunID. En las categorias de failed no se sabe si un producto
+// desaparecio o solo no se pudo leer, asi que sus productos no se tocan; los
+// productos sin categoria tampoco cuando alguna categoria fallo.
+func (s *ScraperServiceImpl) syncDiscontinued(ctx context.Context, runID string, seen map[string]bool, failed map[string]bool, now time.Time) error {
+	products, err := s.ScraperRepository.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	var expired []string
+	var expiredProducts []models.Product
+	for _, product := range products {
+		if seen[product.ProductID] || product.LastSeenRunID == runID {
+			continue
+		}
+
+		if failed[product.Store+"/"+product.CategorySlug] || (product.CategorySlug == "" && len(failed) > 0) {
+			continue
+		}
+
+		if product.DiscontinuedAt == "" {
+			err := s.ScraperRepository.MarkDiscontinued(ctx, product.ProductID, now.Format(time.RFC3339))
 			if err != nil {
-				logrus.WithError(err).Error("[ProductServiceImpl.UpdateData] Error creating product")
-				return false, err
+				return err
 			}
+			continue
+		}
+
+		discontinuedAt, err := time.Parse(time.RFC3339, product.DiscontinuedAt)
+		if err != nil {
+			logrus.WithError(err).Warnf("[ScraperServiceImpl.syncDiscontinued] Invalid discontinued date for product %s", product.ProductID)
+			continue
+		}
+
+		if now.Sub(discontinuedAt) >= s.Config.GracePeriod {
+			expired = append(expired, product.ProductID)
+			expiredProducts = append(expiredProducts, product)
 		}
 	}
 
-	logrus.Info("[ProductServiceImpl.UpdateData] Data scraped successfully")
-	return true, nil
+	if len(expired) == 0 {
+		return nil
+	}
+
+	err = s.ScraperRepository.DeleteMany(ctx, expired)
+	if err != nil {
+		logBatchError(err, "[ScraperServiceImpl.syncDiscontinued] Error deleting expired products")
+		return err
+	}
+
+	// La API ignora las entradas de productos que ya no existen, asi que un
+	// error aca solo deja entradas de mas en el indice
+	err = s.SearchIndexRepository.Remove(ctx, expiredProducts)
+	if err != nil {
+		logBatchError(err, "[ScraperServiceImpl.syncDiscontinued] Error removing expired products from the search index")
+	}
+
+	return nil
+}
+
+// logBatchError registra cada item que no se pudo escribir cuando err es un
+// *repository.BatchError, o err tal cual en otro caso
+func logBatchError(err error, msg string) {
+	var batchErr *repository.BatchError
+	if !errors.As(err, &batchErr) {
+		logrus.WithError(err).Error(msg)
+		return
+	}
+
+	for _, failure := range batchErr.Failures {
+		logrus.WithError(failure.Err).WithField("item_id", failure.ID).Error(msg)
+	}
+}
+
+// scrapeCategories scrapea las categorias de todas las tiendas con un pool de
+// workers acotado por Config.Concurrency. Los resultados quedan en el mismo
+// orden que jobs. Las categorias que no alcanzan a empezar antes de que se
+// cancele ctx quedan interrumpidas desde la primera pagina.
+func (s *ScraperServiceImpl) scrapeCategories(ctx context.Context, jobs []categoryJob) []categoryResult {
+	results := make([]categoryResult, len(jobs))
+
+	workers := s.Config.Concurrency
+	if workers < 1 {
+		workers = 1
+	}
+
+	queue := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				maxPage := jobs[i].category.MaxPage
+				if maxPage == 0 {
+					maxPage = s.Config.MaxPages
+				}
+
+				if err := ctx.Err(); err != nil {
+					results[i] = categoryResult{result: models.ScrapeResult{NextPage: jobs[i].startPage}, err: err, maxPage: maxPage}
+					continue
+				}
+
+				result, err := jobs[i].source.ScrapeData(ctx, jobs[i].startPage, maxPage, jobs[i].category.Category)
+				results[i] = categoryResult{result: result, err: err, maxPage: maxPage}
+			}
+		}()
+	}
+
+	for i := range jobs {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+
+	return results
 }
 
-func NewScraperServiceImpl(scraper scraper.Scraper, scraperRepository repository.ScraperRepository) ScraperService {
+func NewScraperServiceImpl(scrapers []scraper.Scraper, scraperRepository repository.ScraperRepository, priceHistoryRepository repository.PriceHistoryRepository, scrapeRunRepository repository.ScrapeRunRepository, categoryRepository repository.CategoryRepository, checkpointRepository repository.CheckpointRepository, searchIndexRepository repository.SearchIndexRepository, lambdaClient lambdaiface.LambdaAPI, config Config) ScraperService {
 	return &ScraperServiceImpl{
-		Scraper:           scraper,
-		ScraperRepository: scraperRepository,
+		Scrapers:               scrapers,
+		ScraperRepository:      scraperRepository,
+		PriceHistoryRepository: priceHistoryRepository,
+		ScrapeRunRepository:    scrapeRunRepository,
+		CategoryRepository:     categoryRepository,
+		CheckpointRepository:   checkpointRepository,
+		SearchIndexRepository:  searchIndexRepository,
+		LambdaClient:           lambdaClient,
+		Config:                 config,
 	}
 }
@@ -1,124 +1,1217 @@
 package service
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/dieg0code/scraper/src/scraper"
+	"github.com/dieg0code/shared/json/request"
 	"github.com/dieg0code/shared/mocks"
 	"github.com/dieg0code/shared/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
+// testCategories es la lista estatica de categorias del scraper de prueba
+var testCategories = []scraper.CategoryInfo{
+	{Category: "bebidas-alcoholicas"},
+	{Category: "despensa"},
+	{Category: "lacteos"},
+	{Category: "mascotas"},
+	{Category: "navidad"},
+}
+
+// newMockScraper crea un scraper de cugat.cl con la lista estatica de categorias
+func newMockScraper() *mocks.MockScraper {
+	scraperMock := new(mocks.MockScraper)
+	scraperMock.On("Store").Return("cugat.cl")
+	scraperMock.On("Categories").Return(testCategories)
+	return scraperMock
+}
+
+// newMockSearchIndex crea un indice de busqueda que acepta cualquier escritura
+func newMockSearchIndex() *mocks.MockSearchIndexRepository {
+	searchIndexRepo := new(mocks.MockSearchIndexRepository)
+	searchIndexRepo.On("Index", mock.Anything).Return(nil)
+	searchIndexRepo.On("Remove", mock.Anything).Return(nil)
+	return searchIndexRepo
+}
+
+// newMockCheckpoints crea un repositorio de checkpoints que acepta borrar
+// cualquier checkpoint, como al terminar cada run
+func newMockCheckpoints() *mocks.MockCheckpointRepository {
+	checkpointRepo := new(mocks.MockCheckpointRepository)
+	checkpointRepo.On("Delete", mock.Anything).Return(nil)
+	return checkpointRepo
+}
+
+// createdProducts junta los productos de todas las llamadas a CreateMany
+func createdProducts(repo *mocks.MockScraperRepository) []models.Product {
+	var products []models.Product
+	for _, call := range repo.Calls {
+		if call.Method == "CreateMany" {
+			batch, ok := call.Arguments.Get(0).([]models.Product)
+			if ok {
+				products = append(products, batch...)
+			}
+		}
+	}
+	return products
+}
+
+// withProduct matchea un batch de CreateMany con algun producto que cumpla match
+func withProduct(match func(models.Product) bool) interface{} {
+	return mock.MatchedBy(func(products []models.Product) bool {
+		for _, product := range products {
+			if match(product) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// withObservation matchea un batch de CreateMany con alguna observacion que cumpla match
+func withObservation(match func(models.PriceObservation) bool) interface{} {
+	return mock.MatchedBy(func(observations []models.PriceObservation) bool {
+		for _, observation := range observations {
+			if match(observation) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
 func TestScraperService_GetProducts(t *testing.T) {
+	scrapeReq := request.ScrapeRequest{RunID: "run-id", TriggeredBy: "user-id"}
+
 	t.Run("GetProducts_Success", func(t *testing.T) {
 		repo := new(mocks.MockScraperRepository)
-		scraper := new(mocks.MockScraper)
+		priceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		scrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		categoryRepo := new(mocks.MockCategoryRepository)
+		categoryRepo.On("UpdateStats", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		scrapeRunRepo.On("Save", mock.Anything).Return(models.ScrapeRun{}, nil)
+		scraperMock := newMockScraper()
 
-		scraperService := NewScraperServiceImpl(scraper, repo)
+		scraperService := NewScraperServiceImpl([]scraper.Scraper{scraperMock}, repo, priceHistoryRepo, scrapeRunRepo, categoryRepo, newMockCheckpoints(), newMockSearchIndex(), new(mocks.MockLambdaClient), Config{Concurrency: 4})
 
 		// Configurar los mocks
-		repo.On("DeleteAll").Return(nil)
-		scraper.On("ScrapeData", "https", "cugat.cl/categoria-producto", mock.Anything, mock.Anything).Return([]models.Product{
+		repo.On("GetAll").Return([]models.Product{}, nil)
+		scraperMock.On("ScrapeData", mock.Anything, mock.Anything, mock.Anything).Return(models.ScrapeResult{Products: []models.Product{
 			{
-				Name:            "Product1",
+				Name:            "Product1 500 g",
 				Category:        "Category1",
 				OriginalPrice:   100,
 				DiscountedPrice: 80,
+				MinPrice:        80,
+				MaxPrice:        120,
+				Promotion:       &models.Promotion{Quantity: 2, Price: 150},
+				URL:             "https://cugat.cl/producto/product1/",
+				ImageURL:        "https://cugat.cl/wp-content/uploads/product1.jpg",
+				SKU:             "1234",
+				StockStatus:     models.StockOutOfStock,
 			},
-		}, nil)
-		repo.On("Create", mock.Anything).Return(models.Product{}, nil)
+		}, Pages: 1}, nil)
+		repo.On("CreateMany", mock.Anything).Return(nil)
+		priceHistoryRepo.On("CreateMany", mock.Anything).Return(nil)
 
 		// Llamar a la función
-		success, err := scraperService.GetProducts()
+		run, err := scraperService.GetProducts(context.Background(), scrapeReq)
 
 		// Verificar los resultados
 		assert.NoError(t, err, "Expected no error, but got %v", err)
-		assert.True(t, success, "Expected success to be true, but got %v", success)
+		assert.Equal(t, models.ScrapeRunSucceeded, run.Status, "Expected run to succeed, but got %v", run.Status)
 
-		repo.AssertCalled(t, "DeleteAll")
-		scraper.AssertCalled(t, "ScrapeData", "https", "cugat.cl/categoria-producto", mock.Anything, mock.Anything)
-		repo.AssertCalled(t, "Create", mock.Anything)
+		scraperMock.AssertCalled(t, "ScrapeData", mock.Anything, mock.Anything, mock.Anything)
+		repo.AssertCalled(t, "CreateMany", withProduct(func(product models.Product) bool {
+			return product.URL == "https://cugat.cl/producto/product1/" &&
+				product.ImageURL == "https://cugat.cl/wp-content/uploads/product1.jpg" &&
+				product.SKU == "1234" &&
+				product.StockStatus == models.StockOutOfStock &&
+				product.PackageQuantity == 0.5 &&
+				product.PackageUnit == scraper.UnitKilogram &&
+				product.PackageCount == 1 &&
+				product.UnitPrice == 160 &&
+				product.MinPrice == 80 &&
+				product.MaxPrice == 120 &&
+				product.DiscountPercent == 20 &&
+				product.Listing == models.ListingActive &&
+				product.CategorySlug != "" &&
+				product.ListingCategory == product.CategorySlug &&
+				product.SortPrice == 80 &&
+				product.Promotion != nil && *product.Promotion == models.Promotion{Quantity: 2, Price: 150}
+		}))
+		repo.AssertCalled(t, "GetAll")
+		priceHistoryRepo.AssertCalled(t, "CreateMany", withObservation(func(observation models.PriceObservation) bool {
+			return observation.ProductID != "" &&
+				observation.Date == time.Now().Format("2006-01-02") &&
+				observation.OriginalPrice == 100 &&
+				observation.DiscountedPrice == 80
+		}))
 	})
 
-	t.Run("GetProducts_ErrorDeletingAllProducts", func(t *testing.T) {
+	t.Run("GetProducts_SameNameDifferentSKU", func(t *testing.T) {
 		repo := new(mocks.MockScraperRepository)
-		scraper := new(mocks.MockScraper)
+		priceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		scrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		categoryRepo := new(mocks.MockCategoryRepository)
+		categoryRepo.On("UpdateStats", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		scrapeRunRepo.On("Save", mock.Anything).Return(models.ScrapeRun{}, nil)
+		scraperMock := newMockScraper()
+
+		scraperService := NewScraperServiceImpl([]scraper.Scraper{scraperMock}, repo, priceHistoryRepo, scrapeRunRepo, categoryRepo, newMockCheckpoints(), newMockSearchIndex(), new(mocks.MockLambdaClient), Config{Concurrency: 1})
 
-		scraperService := NewScraperServiceImpl(scraper, repo)
+		repo.On("GetAll").Return([]models.Product{}, nil)
+		scraperMock.On("ScrapeData", mock.Anything, mock.Anything, mock.Anything).Return(models.ScrapeResult{Products: []models.Product{
+			{Name: "Arroz 1 kg", OriginalPrice: 1000, DiscountedPrice: 1000, SKU: "101"},
+			{Name: "Arroz 1 kg", OriginalPrice: 1200, DiscountedPrice: 1200, SKU: "102"},
+		}, Pages: 1}, nil)
+		repo.On("CreateMany", mock.Anything).Return(nil)
+		priceHistoryRepo.On("CreateMany", mock.Anything).Return(nil)
+
+		_, err := scraperService.GetProducts(context.Background(), scrapeReq)
+
+		assert.NoError(t, err, "Expected no error, but got %v", err)
+		ids := make(map[string]string)
+		for _, product := range createdProducts(repo) {
+			if product.CategorySlug == testCategories[0].Category {
+				ids[product.SKU] = product.ProductID
+			}
+		}
+		assert.Len(t, ids, 2, "Expected both SKUs to be saved, but got %v", ids)
+		assert.NotEqual(t, ids["101"], ids["102"], "Expected different IDs for different SKUs")
+	})
+
+	t.Run("GetProducts_SameProductInSeveralCategories", func(t *testing.T) {
+		repo := new(mocks.MockScraperRepository)
+		priceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		scrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		categoryRepo := new(mocks.MockCategoryRepository)
+		categoryRepo.On("UpdateStats", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		scrapeRunRepo.On("Save", mock.Anything).Return(models.ScrapeRun{}, nil)
+		scraperMock := newMockScraper()
+
+		scraperService := NewScraperServiceImpl([]scraper.Scraper{scraperMock}, repo, priceHistoryRepo, scrapeRunRepo, categoryRepo, newMockCheckpoints(), newMockSearchIndex(), new(mocks.MockLambdaClient), Config{Concurrency: 1})
+
+		// Todas las categorias devuelven el mismo producto, como una categoria
+		// de temporada que repite productos de las demas
+		repo.On("GetAll").Return([]models.Product{}, nil)
+		scraperMock.On("ScrapeData", mock.Anything, mock.Anything, mock.Anything).Return(models.ScrapeResult{Products: []models.Product{
+			{Name: "Arroz 1 kg", OriginalPrice: 1000, SKU: "101", URL: "https://cugat.cl/producto/arroz/"},
+		}, Pages: 1}, nil)
+		repo.On("CreateMany", mock.Anything).Return(nil)
+		priceHistoryRepo.On("CreateMany", mock.Anything).Return(nil)
+
+		_, err := scraperService.GetProducts(context.Background(), scrapeReq)
+
+		assert.NoError(t, err, "Expected no error, but got %v", err)
+		products := createdProducts(repo)
+		assert.Len(t, products, 1, "Expected the product to be saved once")
+		assert.Equal(t, testCategories[0].Category, products[0].CategorySlug, "Expected the product to keep the first category it appears in")
+		priceHistoryRepo.AssertCalled(t, "CreateMany", mock.MatchedBy(func(observations []models.PriceObservation) bool {
+			return len(observations) == 1
+		}))
+	})
+
+	t.Run("GetProducts_ErrorSavingPriceObservation", func(t *testing.T) {
+		repo := new(mocks.MockScraperRepository)
+		priceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		scrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		categoryRepo := new(mocks.MockCategoryRepository)
+		scrapeRunRepo.On("Save", mock.Anything).Return(models.ScrapeRun{}, nil)
+		scraperMock := newMockScraper()
+
+		scraperService := NewScraperServiceImpl([]scraper.Scraper{scraperMock}, repo, priceHistoryRepo, scrapeRunRepo, categoryRepo, newMockCheckpoints(), newMockSearchIndex(), new(mocks.MockLambdaClient), Config{Concurrency: 4})
 
 		// Configurar los mocks
-		repo.On("DeleteAll").Return(assert.AnError)
-		scraper.On("ScrapeData", "https", "cugat.cl/categoria-producto", mock.Anything, mock.Anything).Return([]models.Product{
+		scraperMock.On("ScrapeData", mock.Anything, mock.Anything, mock.Anything).Return(models.ScrapeResult{Products: []models.Product{
 			{
 				Name:            "Product1",
 				Category:        "Category1",
 				OriginalPrice:   100,
 				DiscountedPrice: 80,
 			},
-		}, nil)
-		repo.On("Create", mock.Anything).Return(models.Product{}, nil)
+		}, Pages: 1}, nil)
+		repo.On("CreateMany", mock.Anything).Return(nil)
+		priceHistoryRepo.On("CreateMany", mock.Anything).Return(assert.AnError)
 
 		// Llamar a la función
-		success, err := scraperService.GetProducts()
+		run, err := scraperService.GetProducts(context.Background(), scrapeReq)
 
 		// Verificar los resultados
 		assert.Error(t, err, "Expected an error, but got nil")
-		assert.False(t, success, "Expected success to be false, but got %v", success)
+		assert.Equal(t, models.ScrapeRunFailed, run.Status, "Expected run to fail, but got %v", run.Status)
 
-		repo.AssertCalled(t, "DeleteAll")
-		repo.AssertNotCalled(t, "Create", mock.Anything)
+		repo.AssertNotCalled(t, "GetAll")
 	})
 
 	t.Run("GetProducts_ErrorScrapingData", func(t *testing.T) {
 		repo := new(mocks.MockScraperRepository)
-		scraper := new(mocks.MockScraper)
+		priceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		scrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		categoryRepo := new(mocks.MockCategoryRepository)
+		scrapeRunRepo.On("Save", mock.Anything).Return(models.ScrapeRun{}, nil)
+		scraperMock := newMockScraper()
 
-		scraperService := NewScraperServiceImpl(scraper, repo)
+		scraperService := NewScraperServiceImpl([]scraper.Scraper{scraperMock}, repo, priceHistoryRepo, scrapeRunRepo, categoryRepo, newMockCheckpoints(), newMockSearchIndex(), new(mocks.MockLambdaClient), Config{Concurrency: 4})
 
 		// Configurar los mocks
-		repo.On("DeleteAll").Return(nil)
-		scraper.On("ScrapeData", "https", "cugat.cl/categoria-producto", mock.Anything, mock.Anything).Return([]models.Product{}, assert.AnError)
-		repo.On("Create", mock.Anything).Return(models.Product{}, nil)
+		repo.On("GetAll").Return([]models.Product{}, nil)
+		scraperMock.On("ScrapeData", mock.Anything, mock.Anything, mock.Anything).Return(models.ScrapeResult{}, assert.AnError)
+		repo.On("CreateMany", mock.Anything).Return(nil)
+		priceHistoryRepo.On("CreateMany", mock.Anything).Return(nil)
 
 		// Llamar a la función
-		success, err := scraperService.GetProducts()
+		run, err := scraperService.GetProducts(context.Background(), scrapeReq)
 
 		// Verificar los resultados
 		assert.Error(t, err, "Expected an error, but got nil")
-		assert.False(t, success, "Expected success to be false, but got %v", success)
+		assert.Equal(t, models.ScrapeRunFailed, run.Status, "Expected run to fail, but got %v", run.Status)
 
-		repo.AssertCalled(t, "DeleteAll")
-		scraper.AssertCalled(t, "ScrapeData", "https", "cugat.cl/categoria-producto", mock.Anything, mock.Anything)
-		repo.AssertNotCalled(t, "Create", mock.Anything)
+		scraperMock.AssertCalled(t, "ScrapeData", mock.Anything, mock.Anything, mock.Anything)
+		repo.AssertNotCalled(t, "CreateMany", mock.Anything)
+		repo.AssertNotCalled(t, "GetAll")
 	})
 
 	t.Run("GetProducts_ErrorCreatingProduct", func(t *testing.T) {
 		repo := new(mocks.MockScraperRepository)
-		scraper := new(mocks.MockScraper)
+		priceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		scrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		categoryRepo := new(mocks.MockCategoryRepository)
+		scrapeRunRepo.On("Save", mock.Anything).Return(models.ScrapeRun{}, nil)
+		scraperMock := newMockScraper()
 
-		scraperService := NewScraperServiceImpl(scraper, repo)
+		scraperService := NewScraperServiceImpl([]scraper.Scraper{scraperMock}, repo, priceHistoryRepo, scrapeRunRepo, categoryRepo, newMockCheckpoints(), newMockSearchIndex(), new(mocks.MockLambdaClient), Config{Concurrency: 4})
 
 		// Configurar los mocks
-		repo.On("DeleteAll").Return(nil)
-		scraper.On("ScrapeData", "https", "cugat.cl/categoria-producto", mock.Anything, mock.Anything).Return([]models.Product{
+		repo.On("GetAll").Return([]models.Product{}, nil)
+		scraperMock.On("ScrapeData", mock.Anything, mock.Anything, mock.Anything).Return(models.ScrapeResult{Products: []models.Product{
 			{
 				Name:            "Product1",
 				Category:        "Category1",
 				OriginalPrice:   100,
 				DiscountedPrice: 80,
 			},
-		}, nil)
-		repo.On("Create", mock.Anything).Return(models.Product{}, assert.AnError)
+		}, Pages: 1}, nil)
+		repo.On("CreateMany", mock.Anything).Return(assert.AnError)
+
+		// Llamar a la función
+		run, err := scraperService.GetProducts(context.Background(), scrapeReq)
+
+		// Verificar los resultados
+		assert.Error(t, err, "Expected an error, but got nil")
+		assert.Equal(t, models.ScrapeRunFailed, run.Status, "Expected run to fail, but got %v", run.Status)
+
+		scraperMock.AssertCalled(t, "ScrapeData", mock.Anything, mock.Anything, mock.Anything)
+		repo.AssertCalled(t, "CreateMany", mock.Anything)
+		repo.AssertNotCalled(t, "GetAll")
+	})
+
+	t.Run("GetProducts_ErrorGettingExistingProducts", func(t *testing.T) {
+		repo := new(mocks.MockScraperRepository)
+		priceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		scrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		categoryRepo := new(mocks.MockCategoryRepository)
+		categoryRepo.On("UpdateStats", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		scrapeRunRepo.On("Save", mock.Anything).Return(models.ScrapeRun{}, nil)
+		scraperMock := newMockScraper()
+
+		scraperService := NewScraperServiceImpl([]scraper.Scraper{scraperMock}, repo, priceHistoryRepo, scrapeRunRepo, categoryRepo, newMockCheckpoints(), newMockSearchIndex(), new(mocks.MockLambdaClient), Config{Concurrency: 4})
+
+		// Configurar los mocks
+		scraperMock.On("ScrapeData", mock.Anything, mock.Anything, mock.Anything).Return(models.ScrapeResult{Products: []models.Product{
+			{
+				Name:            "Product1",
+				Category:        "Category1",
+				OriginalPrice:   100,
+				DiscountedPrice: 80,
+			},
+		}, Pages: 1}, nil)
+		repo.On("CreateMany", mock.Anything).Return(nil)
+		priceHistoryRepo.On("CreateMany", mock.Anything).Return(nil)
+		repo.On("GetAll").Return([]models.Product{}, assert.AnError)
 
 		// Llamar a la función
-		success, err := scraperService.GetProducts()
+		run, err := scraperService.GetProducts(context.Background(), scrapeReq)
 
 		// Verificar los resultados
 		assert.Error(t, err, "Expected an error, but got nil")
-		assert.False(t, success, "Expected success to be false, but got %v", success)
+		assert.Equal(t, models.ScrapeRunFailed, run.Status, "Expected run to fail, but got %v", run.Status)
+
+		repo.AssertCalled(t, "CreateMany", mock.Anything)
+		repo.AssertNotCalled(t, "MarkDiscontinued", mock.Anything, mock.Anything)
+	})
+}
+
+func TestScraperService_GetProducts_Sync(t *testing.T) {
+	scrapeReq := request.ScrapeRequest{RunID: "run-id", TriggeredBy: "user-id"}
+	scrapedProduct := models.Product{
+		Name:            "Product1",
+		Category:        "Category1",
+		OriginalPrice:   100,
+		DiscountedPrice: 80,
+	}
+
+	t.Run("GetProducts_MarksUnseenAsDiscontinued", func(t *testing.T) {
+		repo := new(mocks.MockScraperRepository)
+		priceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		scrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		categoryRepo := new(mocks.MockCategoryRepository)
+		categoryRepo.On("UpdateStats", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		scrapeRunRepo.On("Save", mock.Anything).Return(models.ScrapeRun{}, nil)
+		scraperMock := newMockScraper()
+
+		scraperService := NewScraperServiceImpl([]scraper.Scraper{scraperMock}, repo, priceHistoryRepo, scrapeRunRepo, categoryRepo, newMockCheckpoints(), newMockSearchIndex(), new(mocks.MockLambdaClient), Config{Concurrency: 4, GracePeriod: 24 * time.Hour})
+
+		seenID := scraper.ProductID("cugat.cl", testCategories[0].Category, scrapedProduct.SKU, scrapedProduct.URL, scrapedProduct.Name)
+
+		scraperMock.On("ScrapeData", mock.Anything, mock.Anything, mock.Anything).Return(models.ScrapeResult{Products: []models.Product{scrapedProduct}, Pages: 1}, nil)
+		repo.On("CreateMany", mock.Anything).Return(nil)
+		priceHistoryRepo.On("CreateMany", mock.Anything).Return(nil)
+		repo.On("GetAll").Return([]models.Product{
+			{ProductID: seenID, Name: scrapedProduct.Name},
+			{ProductID: "gone", Name: "Gone Product"},
+		}, nil)
+		repo.On("MarkDiscontinued", "gone", mock.Anything).Return(nil)
+
+		run, err := scraperService.GetProducts(context.Background(), scrapeReq)
+
+		assert.NoError(t, err, "Expected no error, but got %v", err)
+		assert.Equal(t, models.ScrapeRunSucceeded, run.Status, "Expected run to succeed, but got %v", run.Status)
+
+		repo.AssertCalled(t, "MarkDiscontinued", "gone", mock.Anything)
+		repo.AssertNotCalled(t, "MarkDiscontinued", seenID, mock.Anything)
+		repo.AssertNotCalled(t, "DeleteMany", mock.Anything)
+	})
+
+	t.Run("GetProducts_DeletesAfterGracePeriod", func(t *testing.T) {
+		repo := new(mocks.MockScraperRepository)
+		priceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		scrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		categoryRepo := new(mocks.MockCategoryRepository)
+		categoryRepo.On("UpdateStats", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		scrapeRunRepo.On("Save", mock.Anything).Return(models.ScrapeRun{}, nil)
+		scraperMock := newMockScraper()
+		searchIndexRepo := newMockSearchIndex()
+
+		scraperService := NewScraperServiceImpl([]scraper.Scraper{scraperMock}, repo, priceHistoryRepo, scrapeRunRepo, categoryRepo, newMockCheckpoints(), searchIndexRepo, new(mocks.MockLambdaClient), Config{Concurrency: 4, GracePeriod: 24 * time.Hour})
+
+		scraperMock.On("ScrapeData", mock.Anything, mock.Anything, mock.Anything).Return(models.ScrapeResult{Products: []models.Product{scrapedProduct}, Pages: 1}, nil)
+		repo.On("CreateMany", mock.Anything).Return(nil)
+		priceHistoryRepo.On("CreateMany", mock.Anything).Return(nil)
+		repo.On("GetAll").Return([]models.Product{
+			{ProductID: "expired", DiscontinuedAt: time.Now().Add(-48 * time.Hour).Format(time.RFC3339)},
+			{ProductID: "recent", DiscontinuedAt: time.Now().Add(-1 * time.Hour).Format(time.RFC3339)},
+		}, nil)
+		repo.On("DeleteMany", []string{"expired"}).Return(nil)
+
+		run, err := scraperService.GetProducts(context.Background(), scrapeReq)
+
+		assert.NoError(t, err, "Expected no error, but got %v", err)
+		assert.Equal(t, models.ScrapeRunSucceeded, run.Status, "Expected run to succeed, but got %v", run.Status)
+
+		repo.AssertCalled(t, "DeleteMany", []string{"expired"})
+		repo.AssertNotCalled(t, "MarkDiscontinued", mock.Anything, mock.Anything)
+		searchIndexRepo.AssertCalled(t, "Remove", mock.MatchedBy(func(products []models.Product) bool {
+			return len(products) == 1 && products[0].ProductID == "expired"
+		}))
+	})
+
+	t.Run("GetProducts_IndexesProductsForSearch", func(t *testing.T) {
+		repo := new(mocks.MockScraperRepository)
+		priceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		scrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		categoryRepo := new(mocks.MockCategoryRepository)
+		categoryRepo.On("UpdateStats", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		scrapeRunRepo.On("Save", mock.Anything).Return(models.ScrapeRun{}, nil)
+		scraperMock := newMockScraper()
+		searchIndexRepo := new(mocks.MockSearchIndexRepository)
+
+		scraperService := NewScraperServiceImpl([]scraper.Scraper{scraperMock}, repo, priceHistoryRepo, scrapeRunRepo, categoryRepo, newMockCheckpoints(), searchIndexRepo, new(mocks.MockLambdaClient), Config{Concurrency: 4, GracePeriod: 24 * time.Hour})
+
+		scraperMock.On("ScrapeData", mock.Anything, mock.Anything, mock.Anything).Return(models.ScrapeResult{Products: []models.Product{scrapedProduct}, Pages: 1}, nil)
+		repo.On("CreateMany", mock.Anything).Return(nil)
+		priceHistoryRepo.On("CreateMany", mock.Anything).Return(nil)
+		repo.On("GetAll").Return([]models.Product{}, nil)
+		// Un error del indice no hace fallar el run
+		searchIndexRepo.On("Index", mock.Anything).Return(assert.AnError)
+
+		run, err := scraperService.GetProducts(context.Background(), scrapeReq)
+
+		assert.NoError(t, err, "Expected no error, but got %v", err)
+		assert.Equal(t, models.ScrapeRunSucceeded, run.Status, "Expected run to succeed, but got %v", run.Status)
+		searchIndexRepo.AssertCalled(t, "Index", mock.MatchedBy(func(products []models.Product) bool {
+			return len(products) == len(testCategories) && products[0].CategorySlug != ""
+		}))
+	})
+
+	t.Run("GetProducts_ErrorMarkingDiscontinued", func(t *testing.T) {
+		repo := new(mocks.MockScraperRepository)
+		priceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		scrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		categoryRepo := new(mocks.MockCategoryRepository)
+		categoryRepo.On("UpdateStats", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		scrapeRunRepo.On("Save", mock.Anything).Return(models.ScrapeRun{}, nil)
+		scraperMock := newMockScraper()
+
+		scraperService := NewScraperServiceImpl([]scraper.Scraper{scraperMock}, repo, priceHistoryRepo, scrapeRunRepo, categoryRepo, newMockCheckpoints(), newMockSearchIndex(), new(mocks.MockLambdaClient), Config{Concurrency: 4, GracePeriod: 24 * time.Hour})
+
+		scraperMock.On("ScrapeData", mock.Anything, mock.Anything, mock.Anything).Return(models.ScrapeResult{Products: []models.Product{scrapedProduct}, Pages: 1}, nil)
+		repo.On("CreateMany", mock.Anything).Return(nil)
+		priceHistoryRepo.On("CreateMany", mock.Anything).Return(nil)
+		repo.On("GetAll").Return([]models.Product{{ProductID: "gone"}}, nil)
+		repo.On("MarkDiscontinued", "gone", mock.Anything).Return(assert.AnError)
+
+		run, err := scraperService.GetProducts(context.Background(), scrapeReq)
+
+		assert.Error(t, err, "Expected an error, but got nil")
+		assert.Equal(t, models.ScrapeRunFailed, run.Status, "Expected run to fail, but got %v", run.Status)
+	})
+}
+
+func TestScraperService_GetProducts_Concurrent(t *testing.T) {
+	scrapeReq := request.ScrapeRequest{RunID: "run-id", TriggeredBy: "user-id"}
+
+	t.Run("GetProducts_UsesMaxPagesAsDefaultCap", func(t *testing.T) {
+		repo := new(mocks.MockScraperRepository)
+		priceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		scrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		categoryRepo := new(mocks.MockCategoryRepository)
+		categoryRepo.On("UpdateStats", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		scrapeRunRepo.On("Save", mock.Anything).Return(models.ScrapeRun{}, nil)
+		scraperMock := newMockScraper()
+
+		scraperService := NewScraperServiceImpl([]scraper.Scraper{scraperMock}, repo, priceHistoryRepo, scrapeRunRepo, categoryRepo, newMockCheckpoints(), newMockSearchIndex(), new(mocks.MockLambdaClient), Config{Concurrency: 4, MaxPages: 30})
+
+		repo.On("GetAll").Return([]models.Product{}, nil)
+		scraperMock.On("ScrapeData", mock.Anything, 30, mock.Anything).Return(models.ScrapeResult{Pages: 1}, nil)
+
+		_, err := scraperService.GetProducts(context.Background(), scrapeReq)
+
+		assert.NoError(t, err, "Expected no error, but got %v", err)
+		scraperMock.AssertNumberOfCalls(t, "ScrapeData", len(testCategories))
+	})
+
+	t.Run("GetProducts_KeepsCategoryOrder", func(t *testing.T) {
+		repo := new(mocks.MockScraperRepository)
+		priceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		scrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		categoryRepo := new(mocks.MockCategoryRepository)
+		categoryRepo.On("UpdateStats", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		scrapeRunRepo.On("Save", mock.Anything).Return(models.ScrapeRun{}, nil)
+		scraperMock := newMockScraper()
+
+		scraperService := NewScraperServiceImpl([]scraper.Scraper{scraperMock}, repo, priceHistoryRepo, scrapeRunRepo, categoryRepo, newMockCheckpoints(), newMockSearchIndex(), new(mocks.MockLambdaClient), Config{Concurrency: 4})
+
+		// Cada categoria devuelve un producto con su propio nombre
+		repo.On("GetAll").Return([]models.Product{}, nil)
+		for _, categoryInfo := range testCategories {
+			scraperMock.On("ScrapeData", mock.Anything, categoryInfo.MaxPage, categoryInfo.Category).Return(models.ScrapeResult{Products: []models.Product{
+				{
+					Name:     categoryInfo.Category,
+					Category: categoryInfo.Category,
+				},
+			}, Pages: 1}, nil)
+		}
+		repo.On("CreateMany", mock.Anything).Return(nil)
+		priceHistoryRepo.On("CreateMany", mock.Anything).Return(nil)
+
+		run, err := scraperService.GetProducts(context.Background(), scrapeReq)
+
+		assert.NoError(t, err, "Expected no error, but got %v", err)
+		assert.Equal(t, models.ScrapeRunSucceeded, run.Status, "Expected run to succeed, but got %v", run.Status)
+
+		// Los productos se guardan en el mismo orden que un scrapeo secuencial
+		var created []string
+		for _, product := range createdProducts(repo) {
+			created = append(created, product.Name)
+		}
+
+		var expected []string
+		for _, categoryInfo := range testCategories {
+			expected = append(expected, categoryInfo.Category)
+		}
+
+		assert.Equal(t, expected, created, "Expected products to be created in category order")
+		scraperMock.AssertNumberOfCalls(t, "ScrapeData", len(testCategories))
+	})
+}
+
+func TestScraperService_GetProducts_Stores(t *testing.T) {
+	scrapeReq := request.ScrapeRequest{RunID: "run-id", TriggeredBy: "user-id"}
+
+	t.Run("GetProducts_ScrapesEveryStore", func(t *testing.T) {
+		repo := new(mocks.MockScraperRepository)
+		priceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		scrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		categoryRepo := new(mocks.MockCategoryRepository)
+		categoryRepo.On("UpdateStats", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		scrapeRunRepo.On("Save", mock.Anything).Return(models.ScrapeRun{}, nil)
+
+		cugat := new(mocks.MockScraper)
+		cugat.On("Store").Return("cugat.cl")
+		cugat.On("Categories").Return([]scraper.CategoryInfo{{Category: "despensa"}})
+		cugat.On("ScrapeData", mock.Anything, mock.Anything, "despensa").Return(models.ScrapeResult{Products: []models.Product{{Name: "Arroz"}}, Pages: 1}, nil)
+
+		other := new(mocks.MockScraper)
+		other.On("Store").Return("otra-tienda.cl")
+		other.On("Categories").Return([]scraper.CategoryInfo{{Category: "despensa"}})
+		other.On("ScrapeData", mock.Anything, mock.Anything, "despensa").Return(models.ScrapeResult{Products: []models.Product{{Name: "Arroz"}}, Pages: 1}, nil)
+
+		scraperService := NewScraperServiceImpl([]scraper.Scraper{cugat, other}, repo, priceHistoryRepo, scrapeRunRepo, categoryRepo, newMockCheckpoints(), newMockSearchIndex(), new(mocks.MockLambdaClient), Config{Concurrency: 2})
+
+		repo.On("GetAll").Return([]models.Product{}, nil)
+		repo.On("CreateMany", mock.Anything).Return(nil)
+		priceHistoryRepo.On("CreateMany", mock.Anything).Return(nil)
+
+		run, err := scraperService.GetProducts(context.Background(), scrapeReq)
+
+		assert.NoError(t, err, "Expected no error, but got %v", err)
+		assert.Len(t, run.Categories, 2, "Expected one entry per store category")
+		assert.Equal(t, "cugat.cl", run.Categories[0].Store, "Expected stores in configuration order")
+		assert.Equal(t, "otra-tienda.cl", run.Categories[1].Store, "Expected stores in configuration order")
+
+		// El mismo producto en dos tiendas son dos productos distintos
+		repo.AssertCalled(t, "CreateMany", withProduct(func(product models.Product) bool {
+			return product.Store == "cugat.cl" && product.ProductID == scraper.ProductID("cugat.cl", "despensa", "", "", "Arroz")
+		}))
+		repo.AssertCalled(t, "CreateMany", withProduct(func(product models.Product) bool {
+			return product.Store == "otra-tienda.cl" && product.ProductID == scraper.ProductID("otra-tienda.cl", "despensa", "", "", "Arroz")
+		}))
+	})
+}
+
+func TestScraperService_GetProducts_ScrapeRun(t *testing.T) {
+	scrapedProducts := []models.Product{
+		{Name: "Product1", Category: "Category1", OriginalPrice: 100},
+		{Name: "Product2", Category: "Category1", OriginalPrice: 200},
+	}
+
+	t.Run("GetProducts_RecordsRun", func(t *testing.T) {
+		repo := new(mocks.MockScraperRepository)
+		priceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		scrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		categoryRepo := new(mocks.MockCategoryRepository)
+		categoryRepo.On("UpdateStats", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		scraperMock := newMockScraper()
+
+		scraperService := NewScraperServiceImpl([]scraper.Scraper{scraperMock}, repo, priceHistoryRepo, scrapeRunRepo, categoryRepo, newMockCheckpoints(), newMockSearchIndex(), new(mocks.MockLambdaClient), Config{Concurrency: 4})
+
+		scraperMock.On("ScrapeData", mock.Anything, mock.Anything, mock.Anything).Return(models.ScrapeResult{Products: scrapedProducts, Pages: 1}, nil)
+		repo.On("CreateMany", mock.Anything).Return(nil)
+		repo.On("GetAll").Return([]models.Product{}, nil)
+		priceHistoryRepo.On("CreateMany", mock.Anything).Return(nil)
+		scrapeRunRepo.On("Save", mock.Anything).Return(models.ScrapeRun{}, nil)
+
+		run, err := scraperService.GetProducts(context.Background(), request.ScrapeRequest{RunID: "run-id", TriggeredBy: "user-id"})
+
+		assert.NoError(t, err, "Expected no error, but got %v", err)
+		assert.Equal(t, "run-id", run.RunID, "Expected run ID from the request")
+		assert.Equal(t, "user-id", run.TriggeredBy, "Expected trigger user from the request")
+		assert.Equal(t, models.ScrapeRunSucceeded, run.Status, "Expected run to succeed")
+		assert.NotEmpty(t, run.StartedAt, "Expected start time")
+		assert.NotEmpty(t, run.FinishedAt, "Expected end time")
+		assert.Len(t, run.Categories, len(testCategories), "Expected one entry per category")
+		assert.Equal(t, models.CategoryRun{Store: "cugat.cl", Category: testCategories[0].Category, Products: 2, Pages: 1}, run.Categories[0], "Expected category counts")
+		assert.Equal(t, 2*len(testCategories), run.Products, "Expected total product count")
+		assert.Equal(t, len(testCategories), run.Pages, "Expected total page count")
+		assert.Zero(t, run.FailedPages, "Expected no failed pages")
+
+		// Se guarda al iniciar (running) y al terminar (succeeded)
+		scrapeRunRepo.AssertNumberOfCalls(t, "Save", 2)
+		scrapeRunRepo.AssertCalled(t, "Save", mock.MatchedBy(func(saved models.ScrapeRun) bool {
+			return saved.RunID == "run-id" && saved.Status == models.ScrapeRunRunning
+		}))
+		scrapeRunRepo.AssertCalled(t, "Save", mock.MatchedBy(func(saved models.ScrapeRun) bool {
+			return saved.RunID == "run-id" && saved.Status == models.ScrapeRunSucceeded
+		}))
+	})
+
+	t.Run("GetProducts_GeneratesRunID", func(t *testing.T) {
+		repo := new(mocks.MockScraperRepository)
+		priceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		scrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		categoryRepo := new(mocks.MockCategoryRepository)
+		categoryRepo.On("UpdateStats", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		scraperMock := newMockScraper()
+
+		scraperService := NewScraperServiceImpl([]scraper.Scraper{scraperMock}, repo, priceHistoryRepo, scrapeRunRepo, categoryRepo, newMockCheckpoints(), newMockSearchIndex(), new(mocks.MockLambdaClient), Config{Concurrency: 4})
+
+		scraperMock.On("ScrapeData", mock.Anything, mock.Anything, mock.Anything).Return(models.ScrapeResult{Products: []models.Product{}, Pages: 1}, nil)
+		repo.On("GetAll").Return([]models.Product{}, nil)
+		scrapeRunRepo.On("Save", mock.Anything).Return(models.ScrapeRun{}, nil)
+
+		run, err := scraperService.GetProducts(context.Background(), request.ScrapeRequest{})
+
+		assert.NoError(t, err, "Expected no error, but got %v", err)
+		assert.NotEmpty(t, run.RunID, "Expected a generated run ID")
+	})
+
+	t.Run("GetProducts_RecordsCategoryError", func(t *testing.T) {
+		repo := new(mocks.MockScraperRepository)
+		priceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		scrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		categoryRepo := new(mocks.MockCategoryRepository)
+		scraperMock := newMockScraper()
+
+		scraperService := NewScraperServiceImpl([]scraper.Scraper{scraperMock}, repo, priceHistoryRepo, scrapeRunRepo, categoryRepo, newMockCheckpoints(), newMockSearchIndex(), new(mocks.MockLambdaClient), Config{Concurrency: 4})
+
+		failing := testCategories[1]
+		scraperMock.On("ScrapeData", mock.Anything, failing.MaxPage, failing.Category).Return(models.ScrapeResult{}, assert.AnError)
+		scraperMock.On("ScrapeData", mock.Anything, mock.Anything, mock.Anything).Return(models.ScrapeResult{Products: scrapedProducts, Pages: 1}, nil)
+		scrapeRunRepo.On("Save", mock.Anything).Return(models.ScrapeRun{}, nil)
+
+		run, err := scraperService.GetProducts(context.Background(), request.ScrapeRequest{RunID: "run-id"})
+
+		assert.Error(t, err, "Expected an error, but got nil")
+		assert.Equal(t, models.ScrapeRunFailed, run.Status, "Expected run to fail")
+		assert.Equal(t, []models.ScrapeError{{Category: failing.Category, Error: assert.AnError.Error()}}, run.Categories[1].Errors, "Expected category error to be recorded")
+		assert.Equal(t, 1, run.Categories[1].FailedPages, "Expected the failing category to count as a failed page")
+		assert.Empty(t, run.Categories[0].Errors, "Expected no error on other categories")
+		scrapeRunRepo.AssertCalled(t, "Save", mock.MatchedBy(func(saved models.ScrapeRun) bool {
+			return saved.Status == models.ScrapeRunFailed && saved.Error != ""
+		}))
+	})
+
+	t.Run("GetProducts_ErrorSavingRun", func(t *testing.T) {
+		repo := new(mocks.MockScraperRepository)
+		priceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		scrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		categoryRepo := new(mocks.MockCategoryRepository)
+		scraperMock := newMockScraper()
+
+		scraperService := NewScraperServiceImpl([]scraper.Scraper{scraperMock}, repo, priceHistoryRepo, scrapeRunRepo, categoryRepo, newMockCheckpoints(), newMockSearchIndex(), new(mocks.MockLambdaClient), Config{Concurrency: 4})
+
+		scrapeRunRepo.On("Save", mock.Anything).Return(models.ScrapeRun{}, assert.AnError)
+
+		_, err := scraperService.GetProducts(context.Background(), request.ScrapeRequest{RunID: "run-id"})
+
+		assert.Error(t, err, "Expected an error, but got nil")
+		scraperMock.AssertNotCalled(t, "ScrapeData", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestScraperService_GetProducts_PartialFailure(t *testing.T) {
+	scrapeReq := request.ScrapeRequest{RunID: "run-id", TriggeredBy: "user-id"}
+	scrapedProduct := models.Product{Name: "Product1", Category: "Category1", OriginalPrice: 100}
+	pageError := models.ScrapeError{
+		Category:   testCategories[0].Category,
+		PageURL:    "https://cugat.cl/categoria-producto/" + testCategories[0].Category + "/page/2/",
+		StatusCode: 500,
+		Error:      "Internal Server Error",
+	}
+
+	t.Run("GetProducts_ToleratesFailuresUnderThreshold", func(t *testing.T) {
+		repo := new(mocks.MockScraperRepository)
+		priceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		scrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		categoryRepo := new(mocks.MockCategoryRepository)
+		categoryRepo.On("UpdateStats", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		scrapeRunRepo.On("Save", mock.Anything).Return(models.ScrapeRun{}, nil)
+		scraperMock := newMockScraper()
+
+		scraperService := NewScraperServiceImpl([]scraper.Scraper{scraperMock}, repo, priceHistoryRepo, scrapeRunRepo, categoryRepo, newMockCheckpoints(), newMockSearchIndex(), new(mocks.MockLambdaClient), Config{Concurrency: 4, MaxFailureRatio: 0.5})
+
+		// La primera categoria tiene 2 paginas y una falla; el resto responde bien
+		failing := testCategories[0]
+		scraperMock.On("ScrapeData", mock.Anything, failing.MaxPage, failing.Category).Return(models.ScrapeResult{
+			Products: []models.Product{scrapedProduct},
+			Pages:    2,
+			Errors:   []models.ScrapeError{pageError},
+		}, nil)
+		scraperMock.On("ScrapeData", mock.Anything, mock.Anything, mock.Anything).Return(models.ScrapeResult{Products: []models.Product{scrapedProduct}, Pages: 1}, nil)
+		repo.On("CreateMany", mock.Anything).Return(nil)
+		priceHistoryRepo.On("CreateMany", mock.Anything).Return(nil)
+
+		// Un producto de la categoria con la pagina fallida y otro de una
+		// categoria completa que no aparecieron en el run
+		repo.On("GetAll").Return([]models.Product{
+			{ProductID: "unread", Store: "cugat.cl", CategorySlug: failing.Category},
+			{ProductID: "gone", Store: "cugat.cl", CategorySlug: testCategories[1].Category},
+			{ProductID: "legacy", Store: "cugat.cl"},
+		}, nil)
+		repo.On("MarkDiscontinued", "gone", mock.Anything).Return(nil)
+
+		run, err := scraperService.GetProducts(context.Background(), scrapeReq)
+
+		assert.NoError(t, err, "Expected no error, but got %v", err)
+		assert.Equal(t, models.ScrapeRunSucceeded, run.Status, "Expected run to succeed")
+		assert.Equal(t, 1, run.FailedPages, "Expected one failed page")
+		assert.Equal(t, len(testCategories)+1, run.Pages, "Expected every visited page to be counted")
+		assert.Equal(t, []models.ScrapeError{pageError}, run.Categories[0].Errors, "Expected structured page error in the summary")
+
+		assert.Len(t, createdProducts(repo), len(testCategories), "Expected one product per category")
+		// Solo se descontinuan los productos de las categorias completas
+		repo.AssertCalled(t, "MarkDiscontinued", "gone", mock.Anything)
+		repo.AssertNumberOfCalls(t, "MarkDiscontinued", 1)
+
+		// La categoria con una pagina fallida conserva el conteo anterior
+		categoryRepo.AssertNumberOfCalls(t, "UpdateStats", len(testCategories)-1)
+		categoryRepo.AssertNotCalled(t, "UpdateStats", "cugat.cl", failing.Category, mock.Anything, mock.Anything)
+		categoryRepo.AssertCalled(t, "UpdateStats", "cugat.cl", "despensa", 1, mock.Anything)
+	})
+
+	t.Run("GetProducts_FailsOverThreshold", func(t *testing.T) {
+		repo := new(mocks.MockScraperRepository)
+		priceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		scrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		categoryRepo := new(mocks.MockCategoryRepository)
+		scrapeRunRepo.On("Save", mock.Anything).Return(models.ScrapeRun{}, nil)
+		scraperMock := newMockScraper()
+
+		scraperService := NewScraperServiceImpl([]scraper.Scraper{scraperMock}, repo, priceHistoryRepo, scrapeRunRepo, categoryRepo, newMockCheckpoints(), newMockSearchIndex(), new(mocks.MockLambdaClient), Config{Concurrency: 4, MaxFailureRatio: 0.5})
+
+		// Todas las categorias pierden 2 de 3 paginas
+		scraperMock.On("ScrapeData", mock.Anything, mock.Anything, mock.Anything).Return(models.ScrapeResult{
+			Products: []models.Product{scrapedProduct},
+			Pages:    3,
+			Errors:   []models.ScrapeError{pageError, pageError},
+		}, nil)
+
+		run, err := scraperService.GetProducts(context.Background(), scrapeReq)
+
+		assert.Error(t, err, "Expected an error, but got nil")
+		assert.Equal(t, models.ScrapeRunFailed, run.Status, "Expected run to fail")
+		assert.Contains(t, run.Error, "exceeds threshold", "Expected the threshold to be reported")
+		assert.InDelta(t, 2.0/3.0, run.FailureRatio(), 0.001, "Expected failure ratio of 2/3")
+
+		repo.AssertNotCalled(t, "CreateMany", mock.Anything)
+		scrapeRunRepo.AssertCalled(t, "Save", mock.MatchedBy(func(saved models.ScrapeRun) bool {
+			return saved.Status == models.ScrapeRunFailed && saved.FailedPages == 2*len(testCategories)
+		}))
+	})
+}
+
+func TestScraperService_GetProducts_Deadline(t *testing.T) {
+	scrapeReq := request.ScrapeRequest{RunID: "run-id", TriggeredBy: "user-id"}
+	scrapedProduct := models.Product{Name: "Product1", Category: "Category1", OriginalPrice: 100}
+
+	t.Run("GetProducts_SavesCheckpointWhenInterrupted", func(t *testing.T) {
+		repo := new(mocks.MockScraperRepository)
+		priceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		scrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		categoryRepo := new(mocks.MockCategoryRepository)
+		checkpointRepo := newMockCheckpoints()
+		scrapeRunRepo.On("Save", mock.Anything).Return(models.ScrapeRun{}, nil)
+		scraperMock := newMockScraper()
+
+		scraperService := NewScraperServiceImpl([]scraper.Scraper{scraperMock}, repo, priceHistoryRepo, scrapeRunRepo, categoryRepo, checkpointRepo, newMockSearchIndex(), new(mocks.MockLambdaClient), Config{Concurrency: 1, MaxPages: 30})
+
+		// La segunda categoria se corta en la tercera pagina
+		interrupted := testCategories[1]
+		scraperMock.On("ScrapeData", mock.Anything, 30, interrupted.Category).Return(models.ScrapeResult{
+			Products: []models.Product{scrapedProduct, {Name: "Product2", Category: "Category1", OriginalPrice: 200}},
+			Pages:    2,
+			NextPage: 3,
+		}, context.DeadlineExceeded)
+		scraperMock.On("ScrapeData", mock.Anything, mock.Anything, mock.Anything).Return(models.ScrapeResult{Products: []models.Product{scrapedProduct}, Pages: 1}, nil)
+		repo.On("CreateMany", mock.Anything).Return(nil)
+		priceHistoryRepo.On("CreateMany", mock.Anything).Return(nil)
+		checkpointRepo.On("Save", mock.Anything).Return(models.ScrapeCheckpoint{}, nil)
+
+		run, err := scraperService.GetProducts(context.Background(), scrapeReq)
+
+		assert.NoError(t, err, "Expected no error, but got %v", err)
+		assert.Equal(t, models.ScrapeRunInterrupted, run.Status, "Expected run to be interrupted")
+		assert.Zero(t, run.FailedPages, "Expected the interruption not to count as a failure")
+		assert.Equal(t, 2, run.Categories[1].Pages, "Expected the pages scraped before the interruption")
+
+		// Lo scrapeado antes del corte se guarda igual
+		assert.Len(t, createdProducts(repo), len(testCategories)+1, "Expected the products scraped before the interruption")
+		checkpointRepo.AssertCalled(t, "Save", mock.MatchedBy(func(checkpoint models.ScrapeCheckpoint) bool {
+			return checkpoint.RunID == "run-id" &&
+				assert.ObjectsAreEqual([]models.CategoryCursor{
+					{Store: "cugat.cl", Category: interrupted.Category, NextPage: 3, MaxPage: 30},
+				}, checkpoint.Pending)
+		}))
+		scrapeRunRepo.AssertCalled(t, "Save", mock.MatchedBy(func(saved models.ScrapeRun) bool {
+			return saved.Status == models.ScrapeRunInterrupted
+		}))
+		// Sin todas las categorias no se marca nada como descontinuado
+		repo.AssertNotCalled(t, "GetAll")
+	})
+
+	t.Run("GetProducts_StopsBeforeDeadline", func(t *testing.T) {
+		repo := new(mocks.MockScraperRepository)
+		priceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		scrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		categoryRepo := new(mocks.MockCategoryRepository)
+		checkpointRepo := newMockCheckpoints()
+		scrapeRunRepo.On("Save", mock.Anything).Return(models.ScrapeRun{}, nil)
+		scraperMock := newMockScraper()
+
+		// Queda menos tiempo que el margen, asi que no se alcanza a scrapear nada
+		scraperService := NewScraperServiceImpl([]scraper.Scraper{scraperMock}, repo, priceHistoryRepo, scrapeRunRepo, categoryRepo, checkpointRepo, newMockSearchIndex(), new(mocks.MockLambdaClient), Config{Concurrency: 2, DeadlineMargin: time.Minute})
+		checkpointRepo.On("Save", mock.Anything).Return(models.ScrapeCheckpoint{}, nil)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		run, err := scraperService.GetProducts(ctx, scrapeReq)
+
+		assert.NoError(t, err, "Expected no error, but got %v", err)
+		assert.Equal(t, models.ScrapeRunInterrupted, run.Status, "Expected run to be interrupted")
+		scraperMock.AssertNotCalled(t, "ScrapeData", mock.Anything, mock.Anything, mock.Anything)
+		checkpointRepo.AssertCalled(t, "Save", mock.MatchedBy(func(checkpoint models.ScrapeCheckpoint) bool {
+			if len(checkpoint.Pending) != len(testCategories) {
+				return false
+			}
+			for _, cursor := range checkpoint.Pending {
+				if cursor.NextPage != 1 {
+					return false
+				}
+			}
+			return true
+		}))
+	})
+
+	t.Run("GetProducts_ErrorSavingCheckpoint", func(t *testing.T) {
+		repo := new(mocks.MockScraperRepository)
+		priceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		scrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		categoryRepo := new(mocks.MockCategoryRepository)
+		checkpointRepo := newMockCheckpoints()
+		scrapeRunRepo.On("Save", mock.Anything).Return(models.ScrapeRun{}, nil)
+		scraperMock := newMockScraper()
+
+		scraperService := NewScraperServiceImpl([]scraper.Scraper{scraperMock}, repo, priceHistoryRepo, scrapeRunRepo, categoryRepo, checkpointRepo, newMockSearchIndex(), new(mocks.MockLambdaClient), Config{Concurrency: 4})
+
+		scraperMock.On("ScrapeData", mock.Anything, mock.Anything, mock.Anything).Return(models.ScrapeResult{NextPage: 1}, context.Canceled)
+		checkpointRepo.On("Save", mock.Anything).Return(models.ScrapeCheckpoint{}, assert.AnError)
+
+		run, err := scraperService.GetProducts(context.Background(), scrapeReq)
+
+		assert.Error(t, err, "Expected an error, but got nil")
+		assert.Equal(t, models.ScrapeRunFailed, run.Status, "Expected run to fail without a checkpoint")
+	})
+}
+
+func TestScraperService_GetProducts_Resume(t *testing.T) {
+	scrapedProduct := models.Product{Name: "Product1", Category: "Category1", OriginalPrice: 100}
+	cursor := models.CategoryCursor{Store: "cugat.cl", Category: "despensa", NextPage: 3, MaxPage: 30}
+
+	t.Run("GetProducts_InvokesResume", func(t *testing.T) {
+		repo := new(mocks.MockScraperRepository)
+		priceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		scrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		categoryRepo := new(mocks.MockCategoryRepository)
+		checkpointRepo := newMockCheckpoints()
+		lambdaClient := new(mocks.MockLambdaClient)
+		scrapeRunRepo.On("Save", mock.Anything).Return(models.ScrapeRun{}, nil)
+		scraperMock := newMockScraper()
+
+		scraperService := NewScraperServiceImpl([]scraper.Scraper{scraperMock}, repo, priceHistoryRepo, scrapeRunRepo, categoryRepo, checkpointRepo, newMockSearchIndex(), lambdaClient, Config{Concurrency: 4, FunctionName: "scraper"})
+
+		scraperMock.On("ScrapeData", mock.Anything, mock.Anything, mock.Anything).Return(models.ScrapeResult{NextPage: 1}, context.DeadlineExceeded)
+		checkpointRepo.On("Save", mock.Anything).Return(models.ScrapeCheckpoint{}, nil)
+		lambdaClient.On("Invoke", mock.MatchedBy(func(input *lambda.InvokeInput) bool {
+			var payload request.ScrapeRequest
+			err := json.Unmarshal(input.Payload, &payload)
+			return err == nil &&
+				*input.FunctionName == "scraper" &&
+				*input.InvocationType == "Event" &&
+				payload == request.ScrapeRequest{RunID: "run-id", TriggeredBy: "user-id", Resume: true}
+		})).Return(&lambda.InvokeOutput{}, nil)
+
+		run, err := scraperService.GetProducts(context.Background(), request.ScrapeRequest{RunID: "run-id", TriggeredBy: "user-id"})
+
+		assert.NoError(t, err, "Expected no error, but got %v", err)
+		assert.Equal(t, models.ScrapeRunInterrupted, run.Status, "Expected run to be interrupted")
+		checkpointRepo.AssertCalled(t, "Save", mock.MatchedBy(func(checkpoint models.ScrapeCheckpoint) bool {
+			return checkpoint.Invocations == 1
+		}))
+		lambdaClient.AssertExpectations(t)
+	})
+
+	t.Run("GetProducts_ErrorInvokingResume", func(t *testing.T) {
+		repo := new(mocks.MockScraperRepository)
+		priceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		scrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		categoryRepo := new(mocks.MockCategoryRepository)
+		checkpointRepo := newMockCheckpoints()
+		lambdaClient := new(mocks.MockLambdaClient)
+		scrapeRunRepo.On("Save", mock.Anything).Return(models.ScrapeRun{}, nil)
+		scraperMock := newMockScraper()
+
+		scraperService := NewScraperServiceImpl([]scraper.Scraper{scraperMock}, repo, priceHistoryRepo, scrapeRunRepo, categoryRepo, checkpointRepo, newMockSearchIndex(), lambdaClient, Config{Concurrency: 4, FunctionName: "scraper"})
+
+		scraperMock.On("ScrapeData", mock.Anything, mock.Anything, mock.Anything).Return(models.ScrapeResult{NextPage: 1}, context.DeadlineExceeded)
+		checkpointRepo.On("Save", mock.Anything).Return(models.ScrapeCheckpoint{}, nil)
+		lambdaClient.On("Invoke", mock.Anything).Return(&lambda.InvokeOutput{}, assert.AnError)
+
+		run, err := scraperService.GetProducts(context.Background(), request.ScrapeRequest{RunID: "run-id"})
+
+		assert.Error(t, err, "Expected an error, but got nil")
+		assert.Equal(t, models.ScrapeRunFailed, run.Status, "Expected run to fail when it cannot be resumed")
+		checkpointRepo.AssertCalled(t, "Delete", "run-id")
+	})
+
+	t.Run("GetProducts_ResumesFromCheckpoint", func(t *testing.T) {
+		repo := new(mocks.MockScraperRepository)
+		priceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		scrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		categoryRepo := new(mocks.MockCategoryRepository)
+		categoryRepo.On("UpdateStats", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		checkpointRepo := newMockCheckpoints()
+		lambdaClient := new(mocks.MockLambdaClient)
+		scrapeRunRepo.On("Save", mock.Anything).Return(models.ScrapeRun{}, nil)
+		scraperMock := newMockScraper()
+
+		scraperService := NewScraperServiceImpl([]scraper.Scraper{scraperMock}, repo, priceHistoryRepo, scrapeRunRepo, categoryRepo, checkpointRepo, newMockSearchIndex(), lambdaClient, Config{Concurrency: 4, DiscoverCategories: true, FunctionName: "scraper"})
+
+		// La invocacion anterior scrapeo dos paginas de despensa y lacteos completa
+		scrapeRunRepo.On("GetByID", "run-id").Return(models.ScrapeRun{
+			RunID:       "run-id",
+			TriggeredBy: "user-id",
+			Status:      models.ScrapeRunInterrupted,
+			Products:    3,
+			Pages:       3,
+			Categories: []models.CategoryRun{
+				{Store: "cugat.cl", Category: "despensa", Products: 2, Pages: 2},
+				{Store: "cugat.cl", Category: "lacteos", Products: 1, Pages: 1},
+			},
+		}, nil)
+		checkpointRepo.On("GetByRunID", "run-id").Return(models.ScrapeCheckpoint{
+			RunID:       "run-id",
+			Invocations: 1,
+			Pending:     []models.CategoryCursor{cursor},
+		}, nil)
+		scraperMock.On("ScrapeData", 3, 30, "despensa").Return(models.ScrapeResult{Products: []models.Product{scrapedProduct}, Pages: 1}, nil)
+		repo.On("CreateMany", mock.Anything).Return(nil)
+		priceHistoryRepo.On("CreateMany", mock.Anything).Return(nil)
+
+		// Los productos vistos en la invocacion anterior no se descontinuan
+		repo.On("GetAll").Return([]models.Product{
+			{ProductID: "seen-before", LastSeenRunID: "run-id"},
+			{ProductID: "gone", LastSeenRunID: "old-run"},
+		}, nil)
+		repo.On("MarkDiscontinued", "gone", mock.Anything).Return(nil)
+
+		run, err := scraperService.GetProducts(context.Background(), request.ScrapeRequest{RunID: "run-id", TriggeredBy: "user-id", Resume: true})
+
+		assert.NoError(t, err, "Expected no error, but got %v", err)
+		assert.Equal(t, models.ScrapeRunSucceeded, run.Status, "Expected run to succeed once every category is done")
+		assert.Equal(t, 4, run.Products, "Expected products of every invocation")
+		assert.Equal(t, 4, run.Pages, "Expected pages of every invocation")
+		assert.Equal(t, []models.CategoryRun{
+			{Store: "cugat.cl", Category: "despensa", Products: 3, Pages: 3},
+			{Store: "cugat.cl", Category: "lacteos", Products: 1, Pages: 1},
+		}, run.Categories, "Expected the resumed category to add to its summary")
+
+		repo.AssertCalled(t, "CreateMany", withProduct(func(product models.Product) bool {
+			return product.LastSeenRunID == "run-id"
+		}))
+		repo.AssertNumberOfCalls(t, "MarkDiscontinued", 1)
+		checkpointRepo.AssertCalled(t, "Delete", "run-id")
+		scraperMock.AssertNotCalled(t, "DiscoverCategories")
+		lambdaClient.AssertNotCalled(t, "Invoke", mock.Anything)
+	})
+
+	t.Run("GetProducts_FailsAfterMaxInvocations", func(t *testing.T) {
+		repo := new(mocks.MockScraperRepository)
+		priceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		scrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		categoryRepo := new(mocks.MockCategoryRepository)
+		checkpointRepo := newMockCheckpoints()
+		lambdaClient := new(mocks.MockLambdaClient)
+		scrapeRunRepo.On("Save", mock.Anything).Return(models.ScrapeRun{}, nil)
+		scraperMock := newMockScraper()
+
+		scraperService := NewScraperServiceImpl([]scraper.Scraper{scraperMock}, repo, priceHistoryRepo, scrapeRunRepo, categoryRepo, checkpointRepo, newMockSearchIndex(), lambdaClient, Config{Concurrency: 4, FunctionName: "scraper", MaxInvocations: 3})
+
+		scrapeRunRepo.On("GetByID", "run-id").Return(models.ScrapeRun{RunID: "run-id", Status: models.ScrapeRunInterrupted}, nil)
+		checkpointRepo.On("GetByRunID", "run-id").Return(models.ScrapeCheckpoint{
+			RunID:       "run-id",
+			Invocations: 2,
+			Pending:     []models.CategoryCursor{cursor},
+		}, nil)
+		scraperMock.On("ScrapeData", 3, 30, "despensa").Return(models.ScrapeResult{NextPage: 3}, context.DeadlineExceeded)
+
+		run, err := scraperService.GetProducts(context.Background(), request.ScrapeRequest{RunID: "run-id", Resume: true})
+
+		assert.Error(t, err, "Expected an error, but got nil")
+		assert.Equal(t, models.ScrapeRunFailed, run.Status, "Expected run to fail after the last invocation")
+		checkpointRepo.AssertNotCalled(t, "Save", mock.Anything)
+		checkpointRepo.AssertCalled(t, "Delete", "run-id")
+		lambdaClient.AssertNotCalled(t, "Invoke", mock.Anything)
+	})
+
+	t.Run("GetProducts_DeletesCheckpointWhenResumedRunFails", func(t *testing.T) {
+		repo := new(mocks.MockScraperRepository)
+		priceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		scrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		categoryRepo := new(mocks.MockCategoryRepository)
+		checkpointRepo := newMockCheckpoints()
+		scrapeRunRepo.On("Save", mock.Anything).Return(models.ScrapeRun{}, nil)
+		scraperMock := newMockScraper()
+
+		scraperService := NewScraperServiceImpl([]scraper.Scraper{scraperMock}, repo, priceHistoryRepo, scrapeRunRepo, categoryRepo, checkpointRepo, newMockSearchIndex(), new(mocks.MockLambdaClient), Config{Concurrency: 4, MaxFailureRatio: 0.5})
+
+		scrapeRunRepo.On("GetByID", "run-id").Return(models.ScrapeRun{RunID: "run-id", Status: models.ScrapeRunInterrupted}, nil)
+		checkpointRepo.On("GetByRunID", "run-id").Return(models.ScrapeCheckpoint{
+			RunID:       "run-id",
+			Invocations: 1,
+			Pending:     []models.CategoryCursor{cursor},
+		}, nil)
+
+		// Todas las paginas que quedaban fallan
+		scraperMock.On("ScrapeData", 3, 30, "despensa").Return(models.ScrapeResult{
+			Pages: 2,
+			Errors: []models.ScrapeError{
+				{Category: "despensa", PageURL: "https://cugat.cl/categoria-producto/despensa/page/3/", StatusCode: 500},
+				{Category: "despensa", PageURL: "https://cugat.cl/categoria-producto/despensa/page/4/", StatusCode: 500},
+			},
+		}, nil)
+
+		run, err := scraperService.GetProducts(context.Background(), request.ScrapeRequest{RunID: "run-id", Resume: true})
+
+		assert.Error(t, err, "Expected an error, but got nil")
+		assert.Equal(t, models.ScrapeRunFailed, run.Status, "Expected run to fail over the threshold")
+		checkpointRepo.AssertCalled(t, "Delete", "run-id")
+		repo.AssertNotCalled(t, "CreateMany", mock.Anything)
+	})
+
+	t.Run("GetProducts_ErrorLoadingCheckpoint", func(t *testing.T) {
+		repo := new(mocks.MockScraperRepository)
+		priceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		scrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		categoryRepo := new(mocks.MockCategoryRepository)
+		checkpointRepo := newMockCheckpoints()
+		scrapeRunRepo.On("Save", mock.Anything).Return(models.ScrapeRun{}, nil)
+		scraperMock := newMockScraper()
+
+		scraperService := NewScraperServiceImpl([]scraper.Scraper{scraperMock}, repo, priceHistoryRepo, scrapeRunRepo, categoryRepo, checkpointRepo, newMockSearchIndex(), new(mocks.MockLambdaClient), Config{Concurrency: 4})
 
-		repo.AssertCalled(t, "DeleteAll")
-		scraper.AssertCalled(t, "ScrapeData", "https", "cugat.cl/categoria-producto", mock.Anything, mock.Anything)
-		repo.AssertCalled(t, "Create", mock.Anything)
+		scrapeRunRepo.On("GetByID", "run-id").Return(models.ScrapeRun{RunID: "run-id", Status: models.ScrapeRunInterrupted}, nil)
+		checkpointRepo.On("GetByRunID", "run-id").Return(models.ScrapeCheckpoint{}, errors.New("checkpoint not found"))
+
+		run, err := scraperService.GetProducts(context.Background(), request.ScrapeRequest{RunID: "run-id", Resume: true})
+
+		assert.Error(t, err, "Expected an error, but got nil")
+		assert.Equal(t, models.ScrapeRunFailed, run.Status, "Expected run to fail without a checkpoint")
+		scraperMock.AssertNotCalled(t, "ScrapeData", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestScraperService_GetProducts_Categories(t *testing.T) {
+	scrapeReq := request.ScrapeRequest{RunID: "run-id", TriggeredBy: "user-id"}
+	discovered := []models.Category{
+		{Slug: "despensa", Name: "Despensa"},
+		{Slug: "arroz", Name: "Arroz", Parent: "despensa"},
+		{Slug: "navidad", Name: "Navidad"},
+	}
+
+	scrapedCategories := func(scraperMock *mocks.MockScraper) []string {
+		var categories []string
+		for _, call := range scraperMock.Calls {
+			if call.Method == "ScrapeData" {
+				categories = append(categories, call.Arguments.String(2))
+			}
+		}
+		return categories
+	}
+
+	t.Run("GetProducts_DiscoversAndSavesCategories", func(t *testing.T) {
+		repo := new(mocks.MockScraperRepository)
+		priceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		scrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		categoryRepo := new(mocks.MockCategoryRepository)
+		categoryRepo.On("UpdateStats", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		scrapeRunRepo.On("Save", mock.Anything).Return(models.ScrapeRun{}, nil)
+		scraperMock := newMockScraper()
+
+		scraperService := NewScraperServiceImpl([]scraper.Scraper{scraperMock}, repo, priceHistoryRepo, scrapeRunRepo, categoryRepo, newMockCheckpoints(), newMockSearchIndex(), new(mocks.MockLambdaClient), Config{Concurrency: 1, DiscoverCategories: true})
+
+		scraperMock.On("DiscoverCategories").Return(discovered, nil)
+		categoryRepo.On("Save", mock.Anything).Return(models.Category{}, nil)
+		scraperMock.On("ScrapeData", mock.Anything, mock.Anything, mock.Anything).Return(models.ScrapeResult{Pages: 1}, nil)
+		repo.On("GetAll").Return([]models.Product{}, nil)
+
+		run, err := scraperService.GetProducts(context.Background(), scrapeReq)
+
+		assert.NoError(t, err, "Expected no error, but got %v", err)
+		assert.Len(t, run.Categories, 2, "Expected only top level categories to be scraped")
+		assert.Equal(t, []string{"despensa", "navidad"}, scrapedCategories(scraperMock), "Expected discovered categories to be scraped")
+
+		categoryRepo.AssertNumberOfCalls(t, "Save", len(discovered))
+		categoryRepo.AssertCalled(t, "Save", mock.MatchedBy(func(category models.Category) bool {
+			return category.Slug == "arroz" && category.Parent == "despensa" && category.LastSeen != ""
+		}))
+	})
+
+	t.Run("GetProducts_UsesStoredCategoriesWhenDiscoveryFails", func(t *testing.T) {
+		repo := new(mocks.MockScraperRepository)
+		priceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		scrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		categoryRepo := new(mocks.MockCategoryRepository)
+		categoryRepo.On("UpdateStats", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		scrapeRunRepo.On("Save", mock.Anything).Return(models.ScrapeRun{}, nil)
+		scraperMock := newMockScraper()
+
+		scraperService := NewScraperServiceImpl([]scraper.Scraper{scraperMock}, repo, priceHistoryRepo, scrapeRunRepo, categoryRepo, newMockCheckpoints(), newMockSearchIndex(), new(mocks.MockLambdaClient), Config{Concurrency: 1, DiscoverCategories: true})
+
+		scraperMock.On("DiscoverCategories").Return([]models.Category{}, assert.AnError)
+		categoryRepo.On("GetByStore", "cugat.cl").Return([]models.Category{{Slug: "lacteos", Name: "Lacteos"}}, nil)
+		scraperMock.On("ScrapeData", mock.Anything, mock.Anything, mock.Anything).Return(models.ScrapeResult{Pages: 1}, nil)
+		repo.On("GetAll").Return([]models.Product{}, nil)
+
+		_, err := scraperService.GetProducts(context.Background(), scrapeReq)
+
+		assert.NoError(t, err, "Expected no error, but got %v", err)
+		assert.Equal(t, []string{"lacteos"}, scrapedCategories(scraperMock), "Expected stored categories to be scraped")
+		categoryRepo.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("GetProducts_FallsBackToStaticCategories", func(t *testing.T) {
+		repo := new(mocks.MockScraperRepository)
+		priceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		scrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		categoryRepo := new(mocks.MockCategoryRepository)
+		categoryRepo.On("UpdateStats", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		scrapeRunRepo.On("Save", mock.Anything).Return(models.ScrapeRun{}, nil)
+		scraperMock := newMockScraper()
+
+		scraperService := NewScraperServiceImpl([]scraper.Scraper{scraperMock}, repo, priceHistoryRepo, scrapeRunRepo, categoryRepo, newMockCheckpoints(), newMockSearchIndex(), new(mocks.MockLambdaClient), Config{Concurrency: 1, DiscoverCategories: true})
+
+		scraperMock.On("DiscoverCategories").Return([]models.Category{}, assert.AnError)
+		categoryRepo.On("GetByStore", "cugat.cl").Return([]models.Category{}, assert.AnError)
+		scraperMock.On("ScrapeData", mock.Anything, mock.Anything, mock.Anything).Return(models.ScrapeResult{Pages: 1}, nil)
+		repo.On("GetAll").Return([]models.Product{}, nil)
+
+		run, err := scraperService.GetProducts(context.Background(), scrapeReq)
+
+		assert.NoError(t, err, "Expected no error, but got %v", err)
+		assert.Len(t, run.Categories, len(testCategories), "Expected the static category list")
+	})
+
+	t.Run("GetProducts_AppliesAllowAndDenyLists", func(t *testing.T) {
+		repo := new(mocks.MockScraperRepository)
+		priceHistoryRepo := new(mocks.MockPriceHistoryRepository)
+		scrapeRunRepo := new(mocks.MockScrapeRunRepository)
+		categoryRepo := new(mocks.MockCategoryRepository)
+		categoryRepo.On("UpdateStats", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		scrapeRunRepo.On("Save", mock.Anything).Return(models.ScrapeRun{}, nil)
+		scraperMock := newMockScraper()
+
+		scraperService := NewScraperServiceImpl([]scraper.Scraper{scraperMock}, repo, priceHistoryRepo, scrapeRunRepo, categoryRepo, newMockCheckpoints(), newMockSearchIndex(), new(mocks.MockLambdaClient), Config{
+			Concurrency:       1,
+			CategoryAllowList: []string{"despensa", "lacteos", "navidad"},
+			CategoryDenyList:  []string{"navidad"},
+		})
+
+		scraperMock.On("ScrapeData", mock.Anything, mock.Anything, mock.Anything).Return(models.ScrapeResult{Pages: 1}, nil)
+		repo.On("GetAll").Return([]models.Product{}, nil)
+
+		_, err := scraperService.GetProducts(context.Background(), scrapeReq)
+
+		assert.NoError(t, err, "Expected no error, but got %v", err)
+		assert.Equal(t, []string{"despensa", "lacteos"}, scrapedCategories(scraperMock), "Expected the allow list minus denied categories")
+		scraperMock.AssertNotCalled(t, "DiscoverCategories")
 	})
 }
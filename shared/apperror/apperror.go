@@ -0,0 +1,96 @@
+package apperror
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Code es el codigo del tipo de error que se envia en las respuestas para que
+// los clientes no dependan del texto del mensaje
+type Code string
+
+const (
+	CodeNotFound         Code = "NOT_FOUND"
+	CodeMethodNotAllowed Code = "METHOD_NOT_ALLOWED"
+	CodeConflict         Code = "CONFLICT"
+	CodeValidation       Code = "VALIDATION_ERROR"
+	CodeUnauthorized     Code = "UNAUTHORIZED"
+	CodeInternal         Code = "INTERNAL_ERROR"
+)
+
+// statuses es el status HTTP de cada codigo
+var statuses = map[Code]int{
+	CodeNotFound:         http.StatusNotFound,
+	CodeMethodNotAllowed: http.StatusMethodNotAllowed,
+	CodeConflict:         http.StatusConflict,
+	CodeValidation:       http.StatusBadRequest,
+	CodeUnauthorized:     http.StatusUnauthorized,
+	CodeInternal:         http.StatusInternalServerError,
+}
+
+// Error es un error con tipo. Message se puede mostrar al cliente y Err es la
+// causa, que solo se usa para los logs y con errors.As.
+type Error struct {
+	Code    Code
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Wrap crea un error de tipo code con la causa err
+func Wrap(code Code, message string, err error) error {
+	return &Error{Code: code, Message: message, Err: err}
+}
+
+func NotFound(message string) error {
+	return &Error{Code: CodeNotFound, Message: message}
+}
+
+func MethodNotAllowed(message string) error {
+	return &Error{Code: CodeMethodNotAllowed, Message: message}
+}
+
+func Conflict(message string) error {
+	return &Error{Code: CodeConflict, Message: message}
+}
+
+func Validation(message string) error {
+	return &Error{Code: CodeValidation, Message: message}
+}
+
+func Unauthorized(message string) error {
+	return &Error{Code: CodeUnauthorized, Message: message}
+}
+
+func Internal(message string) error {
+	return &Error{Code: CodeInternal, Message: message}
+}
+
+// CodeOf devuelve el codigo de err. Los errores sin tipo son internos.
+func CodeOf(err error) Code {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr.Code
+	}
+	return CodeInternal
+}
+
+// Is informa si err es de tipo code
+func Is(err error, code Code) bool {
+	return err != nil && CodeOf(err) == code
+}
+
+// HTTPStatus es el status HTTP que corresponde a err
+func HTTPStatus(err error) int {
+	if status, ok := statuses[CodeOf(err)]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,48 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodeOf(t *testing.T) {
+	t.Run("CodeOf_Typed", func(t *testing.T) {
+		assert.Equal(t, CodeNotFound, CodeOf(NotFound("product not found")), "Expected the code of the error")
+		assert.Equal(t, CodeConflict, CodeOf(Conflict("email already registered")), "Expected the code of the error")
+		assert.Equal(t, CodeValidation, CodeOf(Validation("invalid cursor")), "Expected the code of the error")
+		assert.Equal(t, CodeUnauthorized, CodeOf(Unauthorized("invalid credentials")), "Expected the code of the error")
+		assert.Equal(t, CodeInternal, CodeOf(Internal("error getting product")), "Expected the code of the error")
+	})
+
+	t.Run("CodeOf_Wrapped", func(t *testing.T) {
+		err := fmt.Errorf("getting product: %w", NotFound("product not found"))
+
+		assert.Equal(t, CodeNotFound, CodeOf(err), "Expected the code of the wrapped error")
+		assert.True(t, Is(err, CodeNotFound), "Expected the wrapped error to be NotFound")
+	})
+
+	t.Run("CodeOf_Untyped", func(t *testing.T) {
+		assert.Equal(t, CodeInternal, CodeOf(errors.New("boom")), "Expected untyped errors to be internal")
+		assert.False(t, Is(nil, CodeInternal), "Expected nil not to have a code")
+	})
+}
+
+func TestWrap(t *testing.T) {
+	cause := errors.New("Key: 'Email' failed on the 'email' tag")
+	err := Wrap(CodeValidation, "invalid request", cause)
+
+	assert.Equal(t, "invalid request", err.Error(), "Expected the message of the error")
+	assert.ErrorIs(t, err, cause, "Expected the cause to be unwrapped")
+}
+
+func TestHTTPStatus(t *testing.T) {
+	assert.Equal(t, http.StatusNotFound, HTTPStatus(NotFound("user not found")), "Expected status code 404")
+	assert.Equal(t, http.StatusConflict, HTTPStatus(Conflict("email already registered")), "Expected status code 409")
+	assert.Equal(t, http.StatusBadRequest, HTTPStatus(Validation("invalid cursor")), "Expected status code 400")
+	assert.Equal(t, http.StatusUnauthorized, HTTPStatus(Unauthorized("invalid credentials")), "Expected status code 401")
+	assert.Equal(t, http.StatusInternalServerError, HTTPStatus(errors.New("boom")), "Expected status code 500")
+}
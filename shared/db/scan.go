@@ -0,0 +1,86 @@
+package db
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+)
+
+// Scanner recorre un Scan pagina por pagina siguiendo LastEvaluatedKey.
+// DynamoDB devuelve como maximo 1 MB por llamada a Scan, asi que todo scan
+// completo de una tabla tiene que pasar por aca:
+//
+//	scanner := db.NewScanner(ctx, client, input)
+//	for scanner.Next() {
+//		items := scanner.Items()
+//	}
+//	if err := scanner.Err(); err != nil {
+//		...
+//	}
+type Scanner struct {
+	ctx     context.Context
+	client  dynamodbiface.DynamoDBAPI
+	input   dynamodb.ScanInput
+	items   []map[string]*dynamodb.AttributeValue
+	lastKey map[string]*dynamodb.AttributeValue
+	started bool
+	err     error
+}
+
+// NewScanner crea un Scanner para input. Si input trae ExclusiveStartKey el
+// recorrido empieza desde esa clave.
+func NewScanner(ctx context.Context, client dynamodbiface.DynamoDBAPI, input *dynamodb.ScanInput) *Scanner {
+	return &Scanner{
+		ctx:     ctx,
+		client:  client,
+		input:   *input,
+		lastKey: input.ExclusiveStartKey,
+	}
+}
+
+// Next pide la siguiente pagina. Devuelve false cuando no quedan paginas o
+// cuando el Scan fallo, en ese caso Err devuelve el error.
+func (s *Scanner) Next() bool {
+	if s.err != nil || (s.started && len(s.lastKey) == 0) {
+		return false
+	}
+
+	// Cada pagina usa su propia copia del input para no modificar el del caller
+	input := s.input
+	input.ExclusiveStartKey = s.lastKey
+
+	output, err := s.client.ScanWithContext(s.ctx, &input)
+	if err != nil {
+		s.err = err
+		s.items = nil
+		return false
+	}
+
+	s.started = true
+	s.items = output.Items
+	s.lastKey = output.LastEvaluatedKey
+	return true
+}
+
+// Items devuelve los items de la pagina actual
+func (s *Scanner) Items() []map[string]*dynamodb.AttributeValue {
+	return s.items
+}
+
+// Err devuelve el error del Scan que detuvo el recorrido, si hubo
+func (s *Scanner) Err() error {
+	return s.err
+}
+
+// ScanAll devuelve los items de todas las paginas de un Scan
+func ScanAll(ctx context.Context, client dynamodbiface.DynamoDBAPI, input *dynamodb.ScanInput) ([]map[string]*dynamodb.AttributeValue, error) {
+	var items []map[string]*dynamodb.AttributeValue
+
+	scanner := NewScanner(ctx, client, input)
+	for scanner.Next() {
+		items = append(items, scanner.Items()...)
+	}
+
+	return items, scanner.Err()
+}
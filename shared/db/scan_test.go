@@ -0,0 +1,146 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/dieg0code/shared/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// mockPages configura el mock para devolver ids en paginas, cada una con el
+// LastEvaluatedKey de su ultimo item salvo la ultima
+func mockPages(mockDB *mocks.MockDynamoDB, pages [][]string) {
+	var startKey map[string]*dynamodb.AttributeValue
+	for i, ids := range pages {
+		output := &dynamodb.ScanOutput{}
+		for _, id := range ids {
+			output.Items = append(output.Items, map[string]*dynamodb.AttributeValue{"ID": {S: aws.String(id)}})
+		}
+		if i < len(pages)-1 {
+			output.LastEvaluatedKey = output.Items[len(output.Items)-1]
+		}
+
+		expected := startKey
+		mockDB.On("Scan", mock.MatchedBy(func(input *dynamodb.ScanInput) bool {
+			if expected == nil {
+				return input.ExclusiveStartKey == nil
+			}
+			return input.ExclusiveStartKey != nil &&
+				aws.StringValue(input.ExclusiveStartKey["ID"].S) == aws.StringValue(expected["ID"].S)
+		})).Return(output, nil).Once()
+
+		startKey = output.LastEvaluatedKey
+	}
+}
+
+func ids(items []map[string]*dynamodb.AttributeValue) []string {
+	var result []string
+	for _, item := range items {
+		result = append(result, aws.StringValue(item["ID"].S))
+	}
+	return result
+}
+
+func TestScanner(t *testing.T) {
+	t.Run("Scanner_FollowsLastEvaluatedKey", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		mockPages(mockDB, [][]string{{"1", "2"}, {"3", "4"}, {"5"}})
+
+		scanner := NewScanner(context.Background(), mockDB, &dynamodb.ScanInput{TableName: aws.String("test-table")})
+
+		var pages [][]string
+		for scanner.Next() {
+			pages = append(pages, ids(scanner.Items()))
+		}
+
+		assert.NoError(t, scanner.Err(), "Expected no error scanning")
+		assert.Equal(t, [][]string{{"1", "2"}, {"3", "4"}, {"5"}}, pages, "Expected every page in order")
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("Scanner_DoesNotModifyInput", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		mockPages(mockDB, [][]string{{"1"}, {"2"}})
+
+		input := &dynamodb.ScanInput{TableName: aws.String("test-table"), FilterExpression: aws.String("attribute_not_exists(DiscontinuedAt)")}
+		_, err := ScanAll(context.Background(), mockDB, input)
+		assert.NoError(t, err, "Expected no error scanning")
+
+		assert.Nil(t, input.ExclusiveStartKey, "Expected the caller's input to be left as is")
+		mockDB.AssertCalled(t, "Scan", mock.MatchedBy(func(page *dynamodb.ScanInput) bool {
+			return page.ExclusiveStartKey != nil && aws.StringValue(page.FilterExpression) == "attribute_not_exists(DiscontinuedAt)"
+		}))
+	})
+
+	t.Run("Scanner_StartsFromExclusiveStartKey", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		mockDB.On("Scan", mock.MatchedBy(func(input *dynamodb.ScanInput) bool {
+			return input.ExclusiveStartKey != nil && aws.StringValue(input.ExclusiveStartKey["ID"].S) == "2"
+		})).Return(&dynamodb.ScanOutput{Items: []map[string]*dynamodb.AttributeValue{{"ID": {S: aws.String("3")}}}}, nil).Once()
+
+		items, err := ScanAll(context.Background(), mockDB, &dynamodb.ScanInput{
+			TableName:         aws.String("test-table"),
+			ExclusiveStartKey: map[string]*dynamodb.AttributeValue{"ID": {S: aws.String("2")}},
+		})
+
+		assert.NoError(t, err, "Expected no error scanning")
+		assert.Equal(t, []string{"3"}, ids(items), "Expected the items after the start key")
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("Scanner_ErrorOnLaterPage", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		mockDB.On("Scan", mock.MatchedBy(func(input *dynamodb.ScanInput) bool {
+			return input.ExclusiveStartKey == nil
+		})).Return(&dynamodb.ScanOutput{
+			Items:            []map[string]*dynamodb.AttributeValue{{"ID": {S: aws.String("1")}}},
+			LastEvaluatedKey: map[string]*dynamodb.AttributeValue{"ID": {S: aws.String("1")}},
+		}, nil).Once()
+		mockDB.On("Scan", mock.Anything).Return(&dynamodb.ScanOutput{}, assert.AnError).Once()
+
+		scanner := NewScanner(context.Background(), mockDB, &dynamodb.ScanInput{TableName: aws.String("test-table")})
+
+		assert.True(t, scanner.Next(), "Expected the first page")
+		assert.False(t, scanner.Next(), "Expected the scan to stop on error")
+		assert.Equal(t, assert.AnError, scanner.Err(), "Expected the scan error")
+		assert.Empty(t, scanner.Items(), "Expected no items after an error")
+		assert.False(t, scanner.Next(), "Expected no more pages after an error")
+		mockDB.AssertNumberOfCalls(t, "Scan", 2)
+	})
+}
+
+func TestScanAll(t *testing.T) {
+	t.Run("ScanAll_Success", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		mockPages(mockDB, [][]string{{"1", "2"}, {"3"}})
+
+		items, err := ScanAll(context.Background(), mockDB, &dynamodb.ScanInput{TableName: aws.String("test-table")})
+
+		assert.NoError(t, err, "Expected no error scanning")
+		assert.Equal(t, []string{"1", "2", "3"}, ids(items), "Expected the items of every page")
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("ScanAll_EmptyTable", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		mockDB.On("Scan", mock.Anything).Return(&dynamodb.ScanOutput{}, nil).Once()
+
+		items, err := ScanAll(context.Background(), mockDB, &dynamodb.ScanInput{TableName: aws.String("test-table")})
+
+		assert.NoError(t, err, "Expected no error scanning")
+		assert.Empty(t, items, "Expected no items")
+	})
+
+	t.Run("ScanAll_Error", func(t *testing.T) {
+		mockDB := new(mocks.MockDynamoDB)
+		mockDB.On("Scan", mock.Anything).Return(&dynamodb.ScanOutput{}, assert.AnError).Once()
+
+		_, err := ScanAll(context.Background(), mockDB, &dynamodb.ScanInput{TableName: aws.String("test-table")})
+
+		assert.Error(t, err, "Expected error scanning")
+	})
+}
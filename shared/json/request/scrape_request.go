@@ -0,0 +1,10 @@
+package request
+
+// ScrapeRequest es el payload con el que la API invoca la lambda del scraper
+type ScrapeRequest struct {
+	RunID       string `json:"run_id"`
+	TriggeredBy string `json:"triggered_by"`
+	// Resume indica que el scraper se invoco a si mismo para seguir un run
+	// interrumpido desde su checkpoint
+	Resume bool `json:"resume,omitempty"`
+}
@@ -1,8 +1,18 @@
 package response
 
 type BaseResponse struct {
-	Code    int         `json:"code"`
-	Status  string      `json:"status"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data"`
+	Code       int                 `json:"code"`
+	Status     string              `json:"status"`
+	Message    string              `json:"message"`
+	Data       interface{}         `json:"data"`
+	Pagination *PaginationResponse `json:"pagination,omitempty"`
+}
+
+// PaginationResponse acompaña a las respuestas paginadas. NextCursor se envia
+// como cursor para pedir la pagina siguiente y queda vacio en la ultima.
+type PaginationResponse struct {
+	PageSize   int    `json:"page_size"`
+	Count      int    `json:"count"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
 }
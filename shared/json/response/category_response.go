@@ -0,0 +1,10 @@
+package response
+
+type CategoryResponse struct {
+	Store        string `json:"store"`
+	Slug         string `json:"slug"`
+	Name         string `json:"name"`
+	Parent       string `json:"parent,omitempty"`
+	ProductCount int    `json:"product_count"`
+	LastUpdated  string `json:"last_updated,omitempty"`
+}
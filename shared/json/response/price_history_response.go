@@ -0,0 +1,7 @@
+package response
+
+type PriceHistoryResponse struct {
+	Date            string `json:"date"`
+	OriginalPrice   int    `json:"original_price"`
+	DiscountedPrice int    `json:"discounted_price"`
+}
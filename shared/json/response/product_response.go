@@ -1,10 +1,36 @@
 package response
 
 type ProductResponse struct {
-	ProductID       string `json:"product_id"`
-	Name            string `json:"name"`
-	Category        string `json:"category"`
-	OriginalPrice   int    `json:"original_price"`
-	DiscountedPrice int    `json:"discounted_price"`
-	LastUpdated     string `json:"last_updated"`
+	ProductID       string             `json:"product_id"`
+	Store           string             `json:"store"`
+	Name            string             `json:"name"`
+	Category        string             `json:"category"`
+	CategorySlug    string             `json:"category_slug,omitempty"`
+	OriginalPrice   int                `json:"original_price"`
+	DiscountedPrice int                `json:"discounted_price"`
+	DiscountPercent int                `json:"discount_percent,omitempty"`
+	MinPrice        int                `json:"min_price,omitempty"`
+	MaxPrice        int                `json:"max_price,omitempty"`
+	Promotion       *PromotionResponse `json:"promotion,omitempty"`
+	URL             string             `json:"url"`
+	ImageURL        string             `json:"image_url"`
+	SKU             string             `json:"sku"`
+	StockStatus     string             `json:"stock_status"`
+	PackageQuantity float64            `json:"package_quantity,omitempty"`
+	PackageUnit     string             `json:"package_unit,omitempty"`
+	PackageCount    int                `json:"package_count,omitempty"`
+	UnitPrice       int                `json:"unit_price,omitempty"`
+	LastUpdated     string             `json:"last_updated"`
+	DiscontinuedAt  string             `json:"discontinued_at,omitempty"`
+}
+
+type PromotionResponse struct {
+	Kind        string `json:"kind"`
+	Quantity    int    `json:"quantity"`
+	Price       int    `json:"price,omitempty"`
+	PayQuantity int    `json:"pay_quantity,omitempty"`
+	Percent     int    `json:"percent,omitempty"`
+	UnitPrice   int    `json:"unit_price"`
+	ValidFrom   string `json:"valid_from,omitempty"`
+	ValidUntil  string `json:"valid_until,omitempty"`
 }
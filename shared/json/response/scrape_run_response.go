@@ -0,0 +1,30 @@
+package response
+
+type ScrapeRunResponse struct {
+	RunID       string                `json:"run_id"`
+	TriggeredBy string                `json:"triggered_by"`
+	StartedAt   string                `json:"started_at"`
+	FinishedAt  string                `json:"finished_at,omitempty"`
+	Status      string                `json:"status"`
+	Error       string                `json:"error,omitempty"`
+	Products    int                   `json:"products"`
+	Pages       int                   `json:"pages"`
+	FailedPages int                   `json:"failed_pages"`
+	Categories  []CategoryRunResponse `json:"categories"`
+}
+
+type CategoryRunResponse struct {
+	Store       string                `json:"store"`
+	Category    string                `json:"category"`
+	Products    int                   `json:"products"`
+	Pages       int                   `json:"pages"`
+	FailedPages int                   `json:"failed_pages"`
+	Errors      []ScrapeErrorResponse `json:"errors,omitempty"`
+}
+
+type ScrapeErrorResponse struct {
+	Category   string `json:"category"`
+	PageURL    string `json:"page_url,omitempty"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Error      string `json:"error"`
+}
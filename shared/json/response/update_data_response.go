@@ -0,0 +1,5 @@
+package response
+
+type UpdateDataResponse struct {
+	RunID string `json:"run_id"`
+}
@@ -0,0 +1,29 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/dieg0code/shared/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCategoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockCategoryRepository) Save(_ context.Context, category models.Category) (models.Category, error) {
+	args := m.Called(category)
+	return args.Get(0).(models.Category), args.Error(1)
+}
+func (m *MockCategoryRepository) GetByStore(_ context.Context, store string) ([]models.Category, error) {
+	args := m.Called(store)
+	return args.Get(0).([]models.Category), args.Error(1)
+}
+func (m *MockCategoryRepository) UpdateStats(_ context.Context, store string, slug string, productCount int, updatedAt string) error {
+	args := m.Called(store, slug, productCount, updatedAt)
+	return args.Error(0)
+}
+func (m *MockCategoryRepository) GetAll(_ context.Context) ([]models.Category, error) {
+	args := m.Called()
+	return args.Get(0).([]models.Category), args.Error(1)
+}
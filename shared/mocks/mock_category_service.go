@@ -0,0 +1,22 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/dieg0code/serverles-api-scraper/api/data/request"
+	"github.com/dieg0code/shared/json/response"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCategoryService struct {
+	mock.Mock
+}
+
+func (m *MockCategoryService) GetAll(_ context.Context) ([]response.CategoryResponse, error) {
+	args := m.Called()
+	return args.Get(0).([]response.CategoryResponse), args.Error(1)
+}
+func (m *MockCategoryService) GetProducts(_ context.Context, slug string, filter request.ProductFilterRequest) ([]response.ProductResponse, response.PaginationResponse, error) {
+	args := m.Called(slug, filter)
+	return args.Get(0).([]response.ProductResponse), args.Get(1).(response.PaginationResponse), args.Error(2)
+}
@@ -0,0 +1,25 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/dieg0code/shared/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCheckpointRepository struct {
+	mock.Mock
+}
+
+func (m *MockCheckpointRepository) Save(_ context.Context, checkpoint models.ScrapeCheckpoint) (models.ScrapeCheckpoint, error) {
+	args := m.Called(checkpoint)
+	return args.Get(0).(models.ScrapeCheckpoint), args.Error(1)
+}
+func (m *MockCheckpointRepository) GetByRunID(_ context.Context, runID string) (models.ScrapeCheckpoint, error) {
+	args := m.Called(runID)
+	return args.Get(0).(models.ScrapeCheckpoint), args.Error(1)
+}
+func (m *MockCheckpointRepository) Delete(_ context.Context, runID string) error {
+	args := m.Called(runID)
+	return args.Error(0)
+}
@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/lambda"
 	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
 	"github.com/stretchr/testify/mock"
@@ -15,3 +17,7 @@ func (m *MockLambdaClient) Invoke(input *lambda.InvokeInput) (*lambda.InvokeOutp
 	args := m.Called(input)
 	return args.Get(0).(*lambda.InvokeOutput), args.Error(1)
 }
+
+func (m *MockLambdaClient) InvokeWithContext(_ aws.Context, input *lambda.InvokeInput, _ ...request.Option) (*lambda.InvokeOutput, error) {
+	return m.Invoke(input)
+}
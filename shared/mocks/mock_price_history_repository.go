@@ -0,0 +1,26 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/dieg0code/shared/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPriceHistoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockPriceHistoryRepository) Create(_ context.Context, observation models.PriceObservation) (models.PriceObservation, error) {
+	args := m.Called(observation)
+	return args.Get(0).(models.PriceObservation), args.Error(1)
+}
+func (m *MockPriceHistoryRepository) CreateMany(_ context.Context, observations []models.PriceObservation) error {
+	args := m.Called(observations)
+	return args.Error(0)
+}
+
+func (m *MockPriceHistoryRepository) GetByProductID(_ context.Context, productID string, from string, to string) ([]models.PriceObservation, error) {
+	args := m.Called(productID, from, to)
+	return args.Get(0).([]models.PriceObservation), args.Error(1)
+}
@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"context"
+
 	"github.com/dieg0code/shared/models"
 	"github.com/stretchr/testify/mock"
 )
@@ -9,14 +11,22 @@ type MockProductRepository struct {
 	mock.Mock
 }
 
-func (m *MockProductRepository) GetAll() ([]models.Product, error) {
-	args := m.Called()
-	return args.Get(0).([]models.Product), args.Error(1)
+func (m *MockProductRepository) List(_ context.Context, query models.ProductQuery) (models.ProductPage, error) {
+	args := m.Called(query)
+	return args.Get(0).(models.ProductPage), args.Error(1)
 }
-func (m *MockProductRepository) GetByID(id string) (models.Product, error) {
+func (m *MockProductRepository) ListByCategory(_ context.Context, slug string, query models.ProductQuery) (models.ProductPage, error) {
+	args := m.Called(slug, query)
+	return args.Get(0).(models.ProductPage), args.Error(1)
+}
+func (m *MockProductRepository) GetByID(_ context.Context, id string) (models.Product, error) {
 	args := m.Called(id)
 	return args.Get(0).(models.Product), args.Error(1)
 }
+func (m *MockProductRepository) GetByIDs(_ context.Context, ids []string) ([]models.Product, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]models.Product), args.Error(1)
+}
 func (m *MockProductRepository) Create(product models.Product) (models.Product, error) {
 	args := m.Called(product)
 	return args.Get(0).(models.Product), args.Error(1)
@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"context"
+
 	"github.com/dieg0code/serverles-api-scraper/api/data/request"
 	"github.com/dieg0code/shared/json/response"
 	"github.com/stretchr/testify/mock"
@@ -10,15 +12,23 @@ type MockProductService struct {
 	mock.Mock
 }
 
-func (m *MockProductService) GetAll() ([]response.ProductResponse, error) {
-	args := m.Called()
-	return args.Get(0).([]response.ProductResponse), args.Error(1)
+func (m *MockProductService) GetAll(_ context.Context, filter request.ProductFilterRequest) ([]response.ProductResponse, response.PaginationResponse, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]response.ProductResponse), args.Get(1).(response.PaginationResponse), args.Error(2)
 }
-func (m *MockProductService) GetByID(productID string) (response.ProductResponse, error) {
+func (m *MockProductService) GetByID(_ context.Context, productID string) (response.ProductResponse, error) {
 	args := m.Called(productID)
 	return args.Get(0).(response.ProductResponse), args.Error(1)
 }
-func (m *MockProductService) UpdateData(updateData request.UpdateDataRequest) (bool, error) {
-	args := m.Called(updateData)
-	return args.Bool(0), args.Error(1)
+func (m *MockProductService) UpdateData(_ context.Context, updateData request.UpdateDataRequest, triggeredBy string) (string, error) {
+	args := m.Called(updateData, triggeredBy)
+	return args.String(0), args.Error(1)
+}
+func (m *MockProductService) GetPriceHistory(_ context.Context, productID string, historyReq request.PriceHistoryRequest) ([]response.PriceHistoryResponse, error) {
+	args := m.Called(productID, historyReq)
+	return args.Get(0).([]response.PriceHistoryResponse), args.Error(1)
+}
+func (m *MockProductService) Search(_ context.Context, searchReq request.SearchRequest) ([]response.ProductResponse, error) {
+	args := m.Called(searchReq)
+	return args.Get(0).([]response.ProductResponse), args.Error(1)
 }
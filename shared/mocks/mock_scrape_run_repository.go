@@ -0,0 +1,25 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/dieg0code/shared/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockScrapeRunRepository struct {
+	mock.Mock
+}
+
+func (m *MockScrapeRunRepository) Save(_ context.Context, run models.ScrapeRun) (models.ScrapeRun, error) {
+	args := m.Called(run)
+	return args.Get(0).(models.ScrapeRun), args.Error(1)
+}
+func (m *MockScrapeRunRepository) GetAll(_ context.Context) ([]models.ScrapeRun, error) {
+	args := m.Called()
+	return args.Get(0).([]models.ScrapeRun), args.Error(1)
+}
+func (m *MockScrapeRunRepository) GetByID(_ context.Context, runID string) (models.ScrapeRun, error) {
+	args := m.Called(runID)
+	return args.Get(0).(models.ScrapeRun), args.Error(1)
+}
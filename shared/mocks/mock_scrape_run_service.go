@@ -0,0 +1,21 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/dieg0code/shared/json/response"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockScrapeRunService struct {
+	mock.Mock
+}
+
+func (m *MockScrapeRunService) GetAll(_ context.Context) ([]response.ScrapeRunResponse, error) {
+	args := m.Called()
+	return args.Get(0).([]response.ScrapeRunResponse), args.Error(1)
+}
+func (m *MockScrapeRunService) GetByID(_ context.Context, runID string) (response.ScrapeRunResponse, error) {
+	args := m.Called(runID)
+	return args.Get(0).(response.ScrapeRunResponse), args.Error(1)
+}
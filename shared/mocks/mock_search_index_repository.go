@@ -0,0 +1,21 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/dieg0code/shared/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSearchIndexRepository struct {
+	mock.Mock
+}
+
+func (m *MockSearchIndexRepository) Index(_ context.Context, products []models.Product) error {
+	args := m.Called(products)
+	return args.Error(0)
+}
+func (m *MockSearchIndexRepository) Remove(_ context.Context, products []models.Product) error {
+	args := m.Called(products)
+	return args.Error(0)
+}
@@ -0,0 +1,17 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/dieg0code/shared/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSearchRepository struct {
+	mock.Mock
+}
+
+func (m *MockSearchRepository) GetByPrefix(_ context.Context, prefix string) ([]models.SearchEntry, error) {
+	args := m.Called(prefix)
+	return args.Get(0).([]models.SearchEntry), args.Error(1)
+}
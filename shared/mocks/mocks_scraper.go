@@ -1,6 +1,9 @@
 package mocks
 
 import (
+	"context"
+
+	"github.com/dieg0code/scraper/src/scraper"
 	"github.com/dieg0code/shared/models"
 	"github.com/stretchr/testify/mock"
 )
@@ -9,9 +12,24 @@ type MockScraper struct {
 	mock.Mock
 }
 
-func (m *MockScraper) ScrapeData(protocol string, baseURL string, maxPage int, category string) ([]models.Product, error) {
-	args := m.Called(protocol, baseURL, maxPage, category)
-	return args.Get(0).([]models.Product), args.Error(1)
+func (m *MockScraper) Store() string {
+	args := m.Called()
+	return args.String(0)
+}
+
+func (m *MockScraper) Categories() []scraper.CategoryInfo {
+	args := m.Called()
+	return args.Get(0).([]scraper.CategoryInfo)
+}
+
+func (m *MockScraper) ScrapeData(_ context.Context, startPage int, maxPage int, category string) (models.ScrapeResult, error) {
+	args := m.Called(startPage, maxPage, category)
+	return args.Get(0).(models.ScrapeResult), args.Error(1)
+}
+
+func (m *MockScraper) DiscoverCategories(_ context.Context) ([]models.Category, error) {
+	args := m.Called()
+	return args.Get(0).([]models.Category), args.Error(1)
 }
 
 func (m *MockScraper) CleanPrice(price string) ([]int, error) {
@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"context"
+
 	"github.com/dieg0code/shared/models"
 	"github.com/stretchr/testify/mock"
 )
@@ -9,11 +11,33 @@ type MockScraperRepository struct {
 	mock.Mock
 }
 
-func (m *MockScraperRepository) Create(product models.Product) (models.Product, error) {
+func (m *MockScraperRepository) Create(_ context.Context, product models.Product) (models.Product, error) {
 	args := m.Called(product)
 	return args.Get(0).(models.Product), args.Error(1)
 }
-func (m *MockScraperRepository) DeleteAll() error {
+func (m *MockScraperRepository) CreateMany(_ context.Context, products []models.Product) error {
+	args := m.Called(products)
+	return args.Error(0)
+}
+
+func (m *MockScraperRepository) GetAll(_ context.Context) ([]models.Product, error) {
+	args := m.Called()
+	return args.Get(0).([]models.Product), args.Error(1)
+}
+func (m *MockScraperRepository) MarkDiscontinued(_ context.Context, productID string, discontinuedAt string) error {
+	args := m.Called(productID, discontinuedAt)
+	return args.Error(0)
+}
+func (m *MockScraperRepository) Delete(_ context.Context, productID string) error {
+	args := m.Called(productID)
+	return args.Error(0)
+}
+func (m *MockScraperRepository) DeleteMany(_ context.Context, productIDs []string) error {
+	args := m.Called(productIDs)
+	return args.Error(0)
+}
+
+func (m *MockScraperRepository) DeleteAll(_ context.Context) error {
 	args := m.Called()
 	return args.Error(0)
 }
@@ -0,0 +1,14 @@
+package models
+
+// Category es una categoria de productos descubierta en el menu de la tienda
+type Category struct {
+	Store    string `json:"store" dynamodbav:"Store"`
+	Slug     string `json:"slug" dynamodbav:"Slug"`
+	Name     string `json:"name" dynamodbav:"Name"`
+	Parent   string `json:"parent,omitempty" dynamodbav:"Parent,omitempty"`
+	LastSeen string `json:"last_seen" dynamodbav:"LastSeen"`
+	// ProductCount y LastUpdated los guarda el scraper al terminar un run con
+	// los productos de la ultima vez que la categoria se scrapeo completa
+	ProductCount int    `json:"product_count" dynamodbav:"ProductCount,omitempty"`
+	LastUpdated  string `json:"last_updated,omitempty" dynamodbav:"LastUpdated,omitempty"`
+}
@@ -0,0 +1,8 @@
+package models
+
+type PriceObservation struct {
+	ProductID       string `json:"product_id" dynamodbav:"ProductID"`
+	Date            string `json:"date" dynamodbav:"Date"`
+	OriginalPrice   int    `json:"original_price" dynamodbav:"OriginalPrice"`
+	DiscountedPrice int    `json:"discounted_price" dynamodbav:"DiscountedPrice"`
+}
@@ -1,10 +1,72 @@
 package models
 
+import "strings"
+
+const (
+	StockInStock    = "in_stock"
+	StockOutOfStock = "out_of_stock"
+)
+
+// ListingActive es el valor de Listing de los productos que se listan en la API
+const ListingActive = "ACTIVE"
+
 type Product struct {
 	ProductID       string `json:"product_id" dynamodbav:"ProductID"`
+	Store           string `json:"store" dynamodbav:"Store"`
 	Name            string `json:"name" dynamodbav:"Name"`
 	Category        string `json:"category" dynamodbav:"Category"`
+	CategorySlug    string `json:"category_slug,omitempty" dynamodbav:"CategorySlug,omitempty"`
 	OriginalPrice   int    `json:"original_price" dynamodbav:"OriginalPrice"`
 	DiscountedPrice int    `json:"discounted_price" dynamodbav:"DiscountedPrice"`
-	LastUpdated     string `json:"last_updated" dynamodbav:"LastUpdated"`
+	// Rango del precio actual de los productos variables, ej: "$1.000 – $2.000".
+	// OriginalPrice y DiscountedPrice guardan el minimo del rango.
+	MinPrice    int        `json:"min_price,omitempty" dynamodbav:"MinPrice,omitempty"`
+	MaxPrice    int        `json:"max_price,omitempty" dynamodbav:"MaxPrice,omitempty"`
+	Promotion   *Promotion `json:"promotion,omitempty" dynamodbav:"Promotion,omitempty"`
+	URL         string     `json:"url" dynamodbav:"URL"`
+	ImageURL    string     `json:"image_url" dynamodbav:"ImageURL"`
+	SKU         string     `json:"sku" dynamodbav:"SKU"`
+	StockStatus string     `json:"stock_status" dynamodbav:"StockStatus"`
+	// Tamaño del envase sacado del nombre, normalizado a kg, litros o unidades
+	PackageQuantity float64 `json:"package_quantity,omitempty" dynamodbav:"PackageQuantity,omitempty"`
+	PackageUnit     string  `json:"package_unit,omitempty" dynamodbav:"PackageUnit,omitempty"`
+	PackageCount    int     `json:"package_count,omitempty" dynamodbav:"PackageCount,omitempty"`
+	// UnitPrice es el precio por kg, litro o unidad segun PackageUnit
+	UnitPrice   int    `json:"unit_price,omitempty" dynamodbav:"UnitPrice,omitempty"`
+	LastUpdated string `json:"last_updated" dynamodbav:"LastUpdated"`
+	// LastSeenRunID es el ultimo run que encontro el producto; un run que se
+	// reparte en varias invocaciones lo usa para saber que productos vio
+	LastSeenRunID  string `json:"last_seen_run_id,omitempty" dynamodbav:"LastSeenRunID,omitempty"`
+	DiscontinuedAt string `json:"discontinued_at,omitempty" dynamodbav:"DiscontinuedAt,omitempty"`
+	// DiscountPercent es el descuento del precio de oferta sobre el original
+	DiscountPercent int `json:"discount_percent,omitempty" dynamodbav:"DiscountPercent"`
+	// Claves de los indices de listado de la API. Listing y ListingCategory
+	// solo existen en los productos activos, asi los descontinuados quedan
+	// fuera de los indices.
+	Listing         string `json:"-" dynamodbav:"Listing,omitempty"`
+	ListingCategory string `json:"-" dynamodbav:"ListingCategory,omitempty"`
+	SortName        string `json:"-" dynamodbav:"SortName,omitempty"`
+	SortPrice       int    `json:"-" dynamodbav:"SortPrice"`
+}
+
+// CurrentPrice es el precio que paga el cliente: el de oferta si lo hay
+func (p Product) CurrentPrice() int {
+	if p.DiscountedPrice > 0 {
+		return p.DiscountedPrice
+	}
+	return p.OriginalPrice
+}
+
+// SetListing completa el descuento y las claves de los indices de listado.
+// Se llama antes de guardar un producto activo.
+func (p *Product) SetListing() {
+	p.DiscountPercent = 0
+	if p.DiscountedPrice > 0 && p.OriginalPrice > p.DiscountedPrice {
+		p.DiscountPercent = (p.OriginalPrice - p.DiscountedPrice) * 100 / p.OriginalPrice
+	}
+
+	p.Listing = ListingActive
+	p.ListingCategory = p.CategorySlug
+	p.SortName = strings.ToLower(p.Name)
+	p.SortPrice = p.CurrentPrice()
 }
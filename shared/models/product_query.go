@@ -0,0 +1,28 @@
+package models
+
+// Ordenes del listado de productos, cada uno se resuelve con su propio indice
+const (
+	SortByName     = "name"
+	SortByPrice    = "price"
+	SortByDiscount = "discount"
+)
+
+// ProductQuery son los filtros, el orden y la pagina de un listado de productos
+type ProductQuery struct {
+	Store      string
+	Category   string
+	OnPromo    bool
+	Discounted bool
+	MinPrice   int
+	MaxPrice   int
+	SortBy     string
+	Descending bool
+	Limit      int
+	Cursor     string
+}
+
+// ProductPage es una pagina del listado. NextCursor queda vacio en la ultima.
+type ProductPage struct {
+	Products   []Product
+	NextCursor string
+}
@@ -0,0 +1,29 @@
+package models
+
+const (
+	// PromotionMultiBuy es "lleva N por $X", ej: "2x$3.000"
+	PromotionMultiBuy = "multi_buy"
+	// PromotionBuyXPayY es "lleva N paga M", ej: "3x2"
+	PromotionBuyXPayY = "buy_x_pay_y"
+	// PromotionPercentOff es un descuento porcentual, ej: "-20%"
+	PromotionPercentOff = "percent_off"
+	// PromotionMemberPrice es el precio para socios o clientes del club
+	PromotionMemberPrice = "member_price"
+)
+
+// Promotion es una promocion mostrada en el listado de la tienda
+type Promotion struct {
+	Kind string `json:"kind" dynamodbav:"Kind"`
+	// Quantity es la cantidad minima de unidades para acceder a la promocion
+	Quantity int `json:"quantity" dynamodbav:"Quantity"`
+	// Price es el precio total de Quantity unidades en multi_buy y member_price
+	Price int `json:"price,omitempty" dynamodbav:"Price,omitempty"`
+	// PayQuantity son las unidades que se pagan en buy_x_pay_y
+	PayQuantity int `json:"pay_quantity,omitempty" dynamodbav:"PayQuantity,omitempty"`
+	Percent     int `json:"percent,omitempty" dynamodbav:"Percent,omitempty"`
+	// UnitPrice es lo que cuesta cada unidad aplicando la promocion
+	UnitPrice int `json:"unit_price" dynamodbav:"UnitPrice"`
+	// Vigencia en formato 2006-01-02, solo si la tienda la muestra
+	ValidFrom  string `json:"valid_from,omitempty" dynamodbav:"ValidFrom,omitempty"`
+	ValidUntil string `json:"valid_until,omitempty" dynamodbav:"ValidUntil,omitempty"`
+}
@@ -0,0 +1,18 @@
+package models
+
+// ScrapeCheckpoint guarda donde quedo un run interrumpido: las categorias que
+// faltan y la pagina desde la que hay que seguir cada una. Invocations cuenta
+// las invocaciones del scraper que ya trabajaron en el run.
+type ScrapeCheckpoint struct {
+	RunID       string           `json:"run_id" dynamodbav:"RunID"`
+	SavedAt     string           `json:"saved_at" dynamodbav:"SavedAt"`
+	Invocations int              `json:"invocations" dynamodbav:"Invocations"`
+	Pending     []CategoryCursor `json:"pending" dynamodbav:"Pending"`
+}
+
+type CategoryCursor struct {
+	Store    string `json:"store" dynamodbav:"Store"`
+	Category string `json:"category" dynamodbav:"Category"`
+	NextPage int    `json:"next_page" dynamodbav:"NextPage"`
+	MaxPage  int    `json:"max_page,omitempty" dynamodbav:"MaxPage,omitempty"`
+}
@@ -0,0 +1,59 @@
+package models
+
+const (
+	ScrapeRunPending   = "pending"
+	ScrapeRunRunning   = "running"
+	ScrapeRunSucceeded = "succeeded"
+	ScrapeRunFailed    = "failed"
+	// ScrapeRunInterrupted es un run que se detuvo antes del timeout de la
+	// Lambda y dejo un checkpoint con las categorias pendientes
+	ScrapeRunInterrupted = "interrupted"
+)
+
+type ScrapeRun struct {
+	RunID       string        `json:"run_id" dynamodbav:"RunID"`
+	TriggeredBy string        `json:"triggered_by" dynamodbav:"TriggeredBy"`
+	StartedAt   string        `json:"started_at" dynamodbav:"StartedAt"`
+	FinishedAt  string        `json:"finished_at,omitempty" dynamodbav:"FinishedAt,omitempty"`
+	Status      string        `json:"status" dynamodbav:"Status"`
+	Error       string        `json:"error,omitempty" dynamodbav:"Error,omitempty"`
+	Products    int           `json:"products" dynamodbav:"Products"`
+	Pages       int           `json:"pages" dynamodbav:"Pages"`
+	FailedPages int           `json:"failed_pages" dynamodbav:"FailedPages"`
+	Categories  []CategoryRun `json:"categories" dynamodbav:"Categories"`
+}
+
+// FailureRatio es la proporcion de paginas fallidas sobre las visitadas
+func (r ScrapeRun) FailureRatio() float64 {
+	if r.Pages == 0 {
+		return 0
+	}
+	return float64(r.FailedPages) / float64(r.Pages)
+}
+
+type CategoryRun struct {
+	Store       string        `json:"store" dynamodbav:"Store"`
+	Category    string        `json:"category" dynamodbav:"Category"`
+	Products    int           `json:"products" dynamodbav:"Products"`
+	Pages       int           `json:"pages" dynamodbav:"Pages"`
+	FailedPages int           `json:"failed_pages" dynamodbav:"FailedPages"`
+	Errors      []ScrapeError `json:"errors,omitempty" dynamodbav:"Errors,omitempty"`
+}
+
+// ScrapeError describe una pagina que no se pudo scrapear
+type ScrapeError struct {
+	Category   string `json:"category" dynamodbav:"Category"`
+	PageURL    string `json:"page_url,omitempty" dynamodbav:"PageURL,omitempty"`
+	StatusCode int    `json:"status_code,omitempty" dynamodbav:"StatusCode,omitempty"`
+	Error      string `json:"error" dynamodbav:"Error"`
+}
+
+// ScrapeResult es lo que devuelve el scrapeo de una categoria: los productos de
+// las paginas que respondieron, cuantas paginas se visitaron y las que fallaron.
+// Si el scrapeo se interrumpio NextPage es la primera pagina que falta.
+type ScrapeResult struct {
+	Products []Product
+	Pages    int
+	Errors   []ScrapeError
+	NextPage int
+}
@@ -0,0 +1,17 @@
+package models
+
+const (
+	SearchFieldName     = "name"
+	SearchFieldCategory = "category"
+)
+
+// SearchEntry es una entrada del indice invertido de busqueda: un token de un
+// producto. Las entradas se agrupan por los primeros caracteres del token para
+// que una busqueda con errores de tipeo lea una sola particion por palabra.
+type SearchEntry struct {
+	Prefix    string `json:"prefix" dynamodbav:"Prefix"`
+	Key       string `json:"key" dynamodbav:"Key"`
+	Token     string `json:"token" dynamodbav:"Token"`
+	ProductID string `json:"product_id" dynamodbav:"ProductID"`
+	Field     string `json:"field" dynamodbav:"Field"`
+}
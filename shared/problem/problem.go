@@ -0,0 +1,212 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/awslabs/aws-lambda-go-api-proxy/core"
+	"github.com/dieg0code/shared/apperror"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+// ContentType es el tipo de las respuestas de error (RFC 7807)
+const ContentType = "application/problem+json"
+
+// RequestIDHeader es el header con el id de la request cuando no viene de API
+// Gateway, por ejemplo en local o en los tests
+const RequestIDHeader = "X-Request-Id"
+
+// Problem es el cuerpo de las respuestas de error. Code es el codigo de
+// apperror y Errors los campos que no pasaron la validacion; los dos son
+// miembros de extension del RFC.
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     string       `json:"code"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError es un campo invalido. Rule es el tag de validator que fallo.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// types es el type de cada codigo. Son relativos porque no hay una pagina
+// publica que los documente; el README los lista.
+var types = map[apperror.Code]string{
+	apperror.CodeNotFound:         "/problems/not-found",
+	apperror.CodeMethodNotAllowed: "/problems/method-not-allowed",
+	apperror.CodeConflict:         "/problems/conflict",
+	apperror.CodeValidation:       "/problems/validation-error",
+	apperror.CodeUnauthorized:     "/problems/unauthorized",
+	apperror.CodeInternal:         "/problems/internal-error",
+}
+
+// New arma el problem de err. Los errores internos usan message como detail
+// para no exponer la causa; los demas usan su propio mensaje.
+func New(err error, message string, instance string) Problem {
+	code := apperror.CodeOf(err)
+	status := apperror.HTTPStatus(err)
+
+	detail := message
+	if code != apperror.CodeInternal {
+		detail = err.Error()
+	}
+
+	return Problem{
+		Type:     types[code],
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: instance,
+		Code:     string(code),
+		Errors:   fieldErrors(err),
+	}
+}
+
+// Render responde el problem de err
+func Render(ctx *gin.Context, err error, message string) {
+	write(ctx, New(err, message, RequestID(ctx)))
+}
+
+// BadRequest responde un error de validacion con detail. err es el error del
+// binding o del validador, de donde salen los campos invalidos, y puede ser nil.
+func BadRequest(ctx *gin.Context, err error, detail string) {
+	write(ctx, New(apperror.Wrap(apperror.CodeValidation, detail, err), detail, RequestID(ctx)))
+}
+
+// NoRoute responde las rutas que no existen. Va en gin.Engine.NoRoute.
+func NoRoute(ctx *gin.Context) {
+	Render(ctx, apperror.NotFound("route not found"), "")
+}
+
+// NoMethod responde los metodos que la ruta no acepta. Va en
+// gin.Engine.NoMethod, con HandleMethodNotAllowed activo.
+func NoMethod(ctx *gin.Context) {
+	Render(ctx, apperror.MethodNotAllowed("method not allowed"), "")
+}
+
+// Recovery reemplaza a gin.Recovery: un panic en un handler responde un error
+// interno en vez de un 500 sin cuerpo
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecovery(func(ctx *gin.Context, recovered any) {
+		logrus.WithField("panic", recovered).Error("[problem.Recovery] Panic handling request")
+		Render(ctx, apperror.Internal("internal server error"), "Internal server error")
+		ctx.Abort()
+	})
+}
+
+// APIGatewayResponse es el problem de err como respuesta de API Gateway, para
+// los errores que ocurren fuera del router
+func APIGatewayResponse(err error, message string, instance string) events.APIGatewayProxyResponse {
+	problem := New(err, message, instance)
+
+	body, marshalErr := json.Marshal(problem)
+	if marshalErr != nil {
+		logrus.WithError(marshalErr).Error("[problem.APIGatewayResponse] Error marshalling problem")
+	}
+
+	return events.APIGatewayProxyResponse{
+		StatusCode: problem.Status,
+		Headers:    map[string]string{"Content-Type": ContentType},
+		Body:       string(body),
+	}
+}
+
+func write(ctx *gin.Context, problem Problem) {
+	body, err := json.Marshal(problem)
+	if err != nil {
+		logrus.WithError(err).Error("[problem.write] Error marshalling problem")
+		ctx.Status(problem.Status)
+		return
+	}
+
+	ctx.Data(problem.Status, ContentType, body)
+}
+
+// RequestID es el id de la request de API Gateway o, fuera de Lambda, el de
+// RequestIDHeader
+func RequestID(ctx *gin.Context) string {
+	if apiGwContext, ok := core.GetAPIGatewayContextFromContext(ctx.Request.Context()); ok && apiGwContext.RequestID != "" {
+		return apiGwContext.RequestID
+	}
+	return ctx.GetHeader(RequestIDHeader)
+}
+
+// RegisterTagNames hace que v nombre los campos por su tag json o form, que
+// es como los envia el cliente, en vez de por el nombre en Go
+func RegisterTagNames(v *validator.Validate) {
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"json", "form"} {
+			name := strings.Split(field.Tag.Get(tag), ",")[0]
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return field.Name
+	})
+}
+
+// fieldErrors saca los campos invalidos de un validator.ValidationErrors
+// dentro de err
+func fieldErrors(err error) []FieldError {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return nil
+	}
+
+	fields := make([]FieldError, 0, len(validationErrors))
+	for _, fieldErr := range validationErrors {
+		fields = append(fields, FieldError{
+			Field:   fieldErr.Field(),
+			Rule:    fieldErr.Tag(),
+			Message: fieldMessage(fieldErr),
+		})
+	}
+	return fields
+}
+
+// fieldMessage describe en ingles la regla que no se cumplio, igual que los
+// demas mensajes de la API
+func fieldMessage(fieldErr validator.FieldError) string {
+	param := fieldErr.Param()
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email"
+	case "min":
+		if fieldErr.Kind() == reflect.String {
+			return fmt.Sprintf("must be at least %s characters long", param)
+		}
+		return fmt.Sprintf("must be at least %s", param)
+	case "max":
+		if fieldErr.Kind() == reflect.String {
+			return fmt.Sprintf("must be at most %s characters long", param)
+		}
+		return fmt.Sprintf("must be at most %s", param)
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", strings.ReplaceAll(param, " ", ", "))
+	case "datetime":
+		return fmt.Sprintf("must use the %s format", param)
+	case "gtefield":
+		return fmt.Sprintf("must be greater than or equal to %s", param)
+	default:
+		return fmt.Sprintf("failed the %s rule", fieldErr.Tag())
+	}
+}
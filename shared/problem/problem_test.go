@@ -0,0 +1,184 @@
+package problem
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/awslabs/aws-lambda-go-api-proxy/core"
+	"github.com/dieg0code/shared/apperror"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+type testRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	PageSize int    `form:"page_size" validate:"max=100"`
+	Role     string `json:"role" validate:"oneof=admin user"`
+}
+
+func TestNew(t *testing.T) {
+	t.Run("New_NotFound", func(t *testing.T) {
+		problem := New(apperror.NotFound("product not found"), "Error getting product by ID", "request-id")
+
+		assert.Equal(t, Problem{
+			Type:     "/problems/not-found",
+			Title:    "Not Found",
+			Status:   http.StatusNotFound,
+			Detail:   "product not found",
+			Instance: "request-id",
+			Code:     "NOT_FOUND",
+		}, problem, "Expected the problem of the error")
+	})
+
+	t.Run("New_InternalHidesCause", func(t *testing.T) {
+		problem := New(errors.New("dynamodb: throttled"), "Error getting product by ID", "request-id")
+
+		assert.Equal(t, http.StatusInternalServerError, problem.Status, "Expected status code 500")
+		assert.Equal(t, "INTERNAL_ERROR", problem.Code, "Expected untyped errors to be internal")
+		assert.Equal(t, "Error getting product by ID", problem.Detail, "Expected the cause to be hidden")
+	})
+
+	t.Run("New_FieldErrors", func(t *testing.T) {
+		v := validator.New()
+		RegisterTagNames(v)
+		err := v.Struct(testRequest{Email: "invalid", PageSize: 200, Role: "root"})
+
+		problem := New(apperror.Wrap(apperror.CodeValidation, "invalid user data", err), "", "")
+
+		assert.Equal(t, http.StatusBadRequest, problem.Status, "Expected status code 400")
+		assert.Equal(t, "invalid user data", problem.Detail, "Expected the detail of the error")
+		assert.Equal(t, []FieldError{
+			{Field: "email", Rule: "email", Message: "must be a valid email"},
+			{Field: "page_size", Rule: "max", Message: "must be at most 100"},
+			{Field: "role", Rule: "oneof", Message: "must be one of: admin, user"},
+		}, problem.Errors, "Expected one error per invalid field")
+	})
+}
+
+func TestRender(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Render_ProblemJSON", func(t *testing.T) {
+		router := gin.New()
+		router.GET("/products/:productId", func(ctx *gin.Context) {
+			Render(ctx, apperror.NotFound("product not found"), "Error getting product by ID")
+		})
+
+		req, err := http.NewRequest(http.MethodGet, "/products/missing-id", nil)
+		assert.NoError(t, err, "Expected no error creating request")
+		req.Header.Set(RequestIDHeader, "request-id")
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code, "Expected status code 404")
+		assert.Equal(t, ContentType, rec.Header().Get("Content-Type"), "Expected a problem+json response")
+
+		var problem Problem
+		err = json.Unmarshal(rec.Body.Bytes(), &problem)
+		assert.NoError(t, err, "Expected no error unmarshalling problem")
+		assert.Equal(t, "request-id", problem.Instance, "Expected the request id as instance")
+	})
+
+	t.Run("Render_APIGatewayRequestID", func(t *testing.T) {
+		router := gin.New()
+		router.GET("/products", func(ctx *gin.Context) {
+			BadRequest(ctx, nil, "Invalid product filters")
+		})
+
+		accessor := core.RequestAccessor{}
+		req, err := accessor.EventToRequestWithContext(context.Background(), events.APIGatewayProxyRequest{
+			HTTPMethod: http.MethodGet,
+			Path:       "/products",
+			RequestContext: events.APIGatewayProxyRequestContext{
+				RequestID: "apigw-request-id",
+			},
+		})
+		assert.NoError(t, err, "Expected no error creating request")
+		req.Header.Set(RequestIDHeader, "header-request-id")
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		var problem Problem
+		err = json.Unmarshal(rec.Body.Bytes(), &problem)
+		assert.NoError(t, err, "Expected no error unmarshalling problem")
+		assert.Equal(t, http.StatusBadRequest, problem.Status, "Expected status code 400")
+		assert.Equal(t, "Invalid product filters", problem.Detail, "Expected the detail of the bad request")
+		assert.Equal(t, "apigw-request-id", problem.Instance, "Expected the API Gateway request id to win")
+		assert.Empty(t, problem.Errors, "Expected no field errors")
+	})
+}
+
+func TestRouterHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func() *gin.Engine {
+		router := gin.New()
+		router.Use(Recovery())
+		router.HandleMethodNotAllowed = true
+		router.NoRoute(NoRoute)
+		router.NoMethod(NoMethod)
+		router.GET("/products", func(ctx *gin.Context) {
+			ctx.JSON(http.StatusOK, gin.H{})
+		})
+		router.GET("/panic", func(ctx *gin.Context) {
+			panic("nil map")
+		})
+		return router
+	}
+
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		status int
+		code   string
+	}{
+		{name: "NoRoute_NotFound", method: http.MethodGet, path: "/missing", status: http.StatusNotFound, code: "NOT_FOUND"},
+		{name: "NoMethod_MethodNotAllowed", method: http.MethodDelete, path: "/products", status: http.StatusMethodNotAllowed, code: "METHOD_NOT_ALLOWED"},
+		{name: "Recovery_Internal", method: http.MethodGet, path: "/panic", status: http.StatusInternalServerError, code: "INTERNAL_ERROR"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(tc.method, tc.path, nil)
+			assert.NoError(t, err, "Expected no error creating request")
+			req.Header.Set(RequestIDHeader, "request-id")
+
+			rec := httptest.NewRecorder()
+			newRouter().ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.status, rec.Code, "Expected status code %d", tc.status)
+			assert.Equal(t, ContentType, rec.Header().Get("Content-Type"), "Expected a problem+json response")
+
+			var problem Problem
+			err = json.Unmarshal(rec.Body.Bytes(), &problem)
+			assert.NoError(t, err, "Expected no error unmarshalling problem")
+			assert.Equal(t, tc.code, problem.Code, "Expected the code of the problem")
+			assert.Equal(t, "request-id", problem.Instance, "Expected the request id as instance")
+		})
+	}
+}
+
+func TestAPIGatewayResponse(t *testing.T) {
+	t.Run("APIGatewayResponse_Internal", func(t *testing.T) {
+		response := APIGatewayResponse(errors.New("invalid event"), "Internal Server Error", "apigw-request-id")
+
+		assert.Equal(t, http.StatusInternalServerError, response.StatusCode, "Expected status code 500")
+		assert.Equal(t, ContentType, response.Headers["Content-Type"], "Expected a problem+json response")
+
+		var problem Problem
+		err := json.Unmarshal([]byte(response.Body), &problem)
+		assert.NoError(t, err, "Expected no error unmarshalling problem")
+		assert.Equal(t, "INTERNAL_ERROR", problem.Code, "Expected an internal error")
+		assert.Equal(t, "Internal Server Error", problem.Detail, "Expected the cause to be hidden")
+		assert.Equal(t, "apigw-request-id", problem.Instance, "Expected the request id as instance")
+	})
+}
@@ -0,0 +1,252 @@
+package search
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/dieg0code/shared/models"
+)
+
+// PrefixLength es la cantidad de caracteres del token que forman la particion
+// del indice. Los errores de tipeo solo se toleran despues de esos caracteres.
+const PrefixLength = 2
+
+// Pesos de cada tipo de coincidencia entre una palabra buscada y un token
+const (
+	exactWeight  = 1.0
+	prefixWeight = 0.8
+	typoWeight   = 0.6
+)
+
+// fieldWeights es el peso de cada campo del producto
+var fieldWeights = map[string]float64{
+	models.SearchFieldName:     1.0,
+	models.SearchFieldCategory: 0.5,
+}
+
+// accents reemplaza las letras con tilde, dieresis o enie por la letra base
+var accents = strings.NewReplacer(
+	"á", "a", "à", "a", "ä", "a", "â", "a",
+	"é", "e", "è", "e", "ë", "e", "ê", "e",
+	"í", "i", "ì", "i", "ï", "i", "î", "i",
+	"ó", "o", "ò", "o", "ö", "o", "ô", "o",
+	"ú", "u", "ù", "u", "ü", "u", "û", "u",
+	"ñ", "n", "ç", "c",
+)
+
+// stopWords son palabras que no sirven para buscar
+var stopWords = map[string]bool{
+	"de": true, "del": true, "el": true, "la": true, "las": true, "los": true,
+	"un": true, "una": true, "al": true, "en": true, "con": true, "sin": true,
+	"para": true, "por": true,
+}
+
+// Normalize pasa el texto a minusculas sin tildes y reemplaza todo lo que no
+// es letra o numero por espacios
+func Normalize(text string) string {
+	text = accents.Replace(strings.ToLower(text))
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return ' '
+	}, text)
+}
+
+// Stem reduce una palabra normalizada a una forma comun para el singular y el
+// plural: "limones" y "limon" quedan como "limon", "nueces" y "nuez" como
+// "nuec". No siempre es una palabra real, pero se aplica igual al indexar y
+// al buscar.
+func Stem(word string) string {
+	runes := []rune(word)
+	if n := len(runes); n > 3 && runes[n-1] == 's' {
+		runes = runes[:n-1]
+	}
+
+	// Plurales en -es de palabras que terminan en consonante: "panes", "flores"
+	if n := len(runes); n >= 4 && runes[n-1] == 'e' && strings.ContainsRune("lnrdzjc", runes[n-2]) {
+		runes = runes[:n-1]
+	}
+
+	// La z final se escribe c en el plural: "nuez", "nueces"
+	if n := len(runes); n > 0 && runes[n-1] == 'z' {
+		runes[n-1] = 'c'
+	}
+
+	return string(runes)
+}
+
+// Tokenize devuelve los tokens de un texto sin repetir y en orden: palabras
+// normalizadas y reducidas con Stem, sin las de un caracter ni stopWords
+func Tokenize(text string) []string {
+	var tokens []string
+	seen := make(map[string]bool)
+	for _, word := range strings.Fields(Normalize(text)) {
+		if len([]rune(word)) < 2 || stopWords[word] {
+			continue
+		}
+
+		token := Stem(word)
+		if !seen[token] {
+			seen[token] = true
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// Prefix es la particion del indice de un token
+func Prefix(token string) string {
+	runes := []rune(token)
+	if len(runes) <= PrefixLength {
+		return token
+	}
+	return string(runes[:PrefixLength])
+}
+
+// Entries arma las entradas del indice de un producto con los tokens de su
+// nombre y de su categoria. Un token del nombre y de la categoria queda solo
+// como nombre, que pesa mas.
+func Entries(product models.Product) []models.SearchEntry {
+	var entries []models.SearchEntry
+	seen := make(map[string]bool)
+	add := func(text string, field string) {
+		for _, token := range Tokenize(text) {
+			if seen[token] {
+				continue
+			}
+			seen[token] = true
+			entries = append(entries, models.SearchEntry{
+				Prefix:    Prefix(token),
+				Key:       token + "#" + product.ProductID,
+				Token:     token,
+				ProductID: product.ProductID,
+				Field:     field,
+			})
+		}
+	}
+
+	add(product.Name, models.SearchFieldName)
+	add(product.Category+" "+product.CategorySlug, models.SearchFieldCategory)
+	return entries
+}
+
+// Result es un producto encontrado. Matches es la cantidad de palabras de la
+// busqueda que coincidieron y Score la suma de sus pesos.
+type Result struct {
+	ProductID string
+	Matches   int
+	Score     float64
+}
+
+// Rank puntua los productos de entries contra los tokens de la busqueda y los
+// devuelve de mas a menos relevante: primero los que coinciden con mas
+// palabras y despues por puntaje.
+func Rank(query []string, entries []models.SearchEntry) []Result {
+	// Mejor coincidencia de cada producto con cada palabra de la busqueda
+	best := make(map[string][]float64)
+	for _, entry := range entries {
+		for i, word := range query {
+			weight := match(word, entry.Token) * fieldWeights[entry.Field]
+			if weight == 0 {
+				continue
+			}
+
+			scores, ok := best[entry.ProductID]
+			if !ok {
+				scores = make([]float64, len(query))
+				best[entry.ProductID] = scores
+			}
+			scores[i] = max(scores[i], weight)
+		}
+	}
+
+	results := make([]Result, 0, len(best))
+	for productID, scores := range best {
+		result := Result{ProductID: productID}
+		for _, score := range scores {
+			if score > 0 {
+				result.Matches++
+				result.Score += score
+			}
+		}
+		results = append(results, result)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Matches != results[j].Matches {
+			return results[i].Matches > results[j].Matches
+		}
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].ProductID < results[j].ProductID
+	})
+
+	return results
+}
+
+// match es el peso de la coincidencia de una palabra buscada con un token: la
+// misma palabra, el comienzo del token (busqueda mientras se escribe) o la
+// palabra con errores de tipeo
+func match(word string, token string) float64 {
+	switch {
+	case word == token:
+		return exactWeight
+	case len([]rune(word)) >= 3 && strings.HasPrefix(token, word):
+		return prefixWeight
+	}
+
+	typos := maxTypos(word)
+	if typos > 0 && distance(word, token, typos) <= typos {
+		return typoWeight
+	}
+	return 0
+}
+
+// maxTypos es la cantidad de errores tolerados segun el largo de la palabra
+func maxTypos(word string) int {
+	switch n := len([]rune(word)); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// distance es la distancia de Levenshtein entre a y b. Si es mayor que limit
+// devuelve limit+1 sin terminar el calculo.
+func distance(a string, b string, limit int) int {
+	ra, rb := []rune(a), []rune(b)
+	if diff := len(ra) - len(rb); diff > limit || -diff > limit {
+		return limit + 1
+	}
+
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		rowMin := current[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+			rowMin = min(rowMin, current[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(rb)]
+}
@@ -0,0 +1,117 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/dieg0code/shared/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenize(t *testing.T) {
+	t.Run("Tokenize_AccentsAndCase", func(t *testing.T) {
+		assert.Equal(t, Tokenize("cafe"), Tokenize("Café"), "Expected accents and case to be ignored")
+		assert.Equal(t, []string{"cafe", "nescafe"}, Tokenize("Café NESCAFÉ"), "Expected normalized tokens")
+		assert.Equal(t, []string{"pina", "azucar"}, Tokenize("Piña Azúcar"), "Expected ñ and accents to be replaced")
+	})
+
+	t.Run("Tokenize_Plurals", func(t *testing.T) {
+		pairs := [][2]string{
+			{"limones", "limon"},
+			{"panes", "pan"},
+			{"galletas", "galleta"},
+			{"nueces", "nuez"},
+			{"dulces", "dulce"},
+			{"leches", "leche"},
+			{"cafés", "café"},
+		}
+		for _, pair := range pairs {
+			assert.Equal(t, Tokenize(pair[1]), Tokenize(pair[0]), "Expected %q and %q to have the same token", pair[0], pair[1])
+		}
+	})
+
+	t.Run("Tokenize_StopWordsAndSymbols", func(t *testing.T) {
+		assert.Equal(t, []string{"dulc", "leche", "500", "gr"}, Tokenize("Dulce de Leche (500 gr) - dulce"), "Expected stop words, symbols and repeats to be dropped")
+		assert.Empty(t, Tokenize("de la ¡!"), "Expected no tokens")
+	})
+}
+
+func TestEntries(t *testing.T) {
+	t.Run("Entries_NameAndCategory", func(t *testing.T) {
+		entries := Entries(models.Product{
+			ProductID:    "p1",
+			Name:         "Arroz Grado 1",
+			Category:     "Arroz y Legumbres",
+			CategorySlug: "despensa",
+		})
+
+		assert.Equal(t, []models.SearchEntry{
+			{Prefix: "ar", Key: "arroc#p1", Token: "arroc", ProductID: "p1", Field: models.SearchFieldName},
+			{Prefix: "gr", Key: "grado#p1", Token: "grado", ProductID: "p1", Field: models.SearchFieldName},
+			{Prefix: "le", Key: "legumbr#p1", Token: "legumbr", ProductID: "p1", Field: models.SearchFieldCategory},
+			{Prefix: "de", Key: "despensa#p1", Token: "despensa", ProductID: "p1", Field: models.SearchFieldCategory},
+		}, entries, "Expected one entry per token, the name winning over the category")
+	})
+}
+
+func TestRank(t *testing.T) {
+	entries := func(productID string, name string, category string) []models.SearchEntry {
+		return Entries(models.Product{ProductID: productID, Name: name, Category: category})
+	}
+
+	var index []models.SearchEntry
+	index = append(index, entries("cafe-nescafe", "Café Nescafé Tradición 170 g", "Café")...)
+	index = append(index, entries("cafe-molido", "Café Molido Colombiano", "Café")...)
+	index = append(index, entries("te-verde", "Té Verde", "Té e Infusiones")...)
+	index = append(index, entries("leche", "Leche Entera 1 L", "Lácteos")...)
+	index = append(index, entries("galletas", "Galletas de Avena", "Galletas")...)
+
+	ids := func(results []Result) []string {
+		var productIDs []string
+		for _, result := range results {
+			productIDs = append(productIDs, result.ProductID)
+		}
+		return productIDs
+	}
+
+	t.Run("Rank_AccentInsensitive", func(t *testing.T) {
+		results := Rank(Tokenize("cafe"), index)
+		assert.ElementsMatch(t, []string{"cafe-nescafe", "cafe-molido"}, ids(results), "Expected both coffees")
+	})
+
+	t.Run("Rank_MoreWordsFirst", func(t *testing.T) {
+		results := Rank(Tokenize("cafe nescafe"), index)
+		assert.Equal(t, []string{"cafe-nescafe", "cafe-molido"}, ids(results), "Expected the product matching every word first")
+		assert.Equal(t, 2, results[0].Matches, "Expected two matched words")
+	})
+
+	t.Run("Rank_Typos", func(t *testing.T) {
+		assert.Equal(t, []string{"galletas"}, ids(Rank(Tokenize("galetas"), index)), "Expected one missing letter to match")
+		assert.Equal(t, []string{"leche"}, ids(Rank(Tokenize("lexhe"), index)), "Expected one wrong letter to match")
+		assert.Empty(t, Rank(Tokenize("lxcjx"), index), "Expected too many typos not to match")
+	})
+
+	t.Run("Rank_Prefix", func(t *testing.T) {
+		results := Rank(Tokenize("nesc"), index)
+		assert.Equal(t, []string{"cafe-nescafe"}, ids(results), "Expected the start of a word to match")
+		assert.Equal(t, prefixWeight, results[0].Score, "Expected a prefix match to weigh less than an exact one")
+	})
+
+	t.Run("Rank_NameOverCategory", func(t *testing.T) {
+		index := append(entries("galletas-avena", "Avena Instantánea", "Galletas"), entries("galletas", "Galletas de Avena", "Cereales")...)
+
+		results := Rank(Tokenize("galletas"), index)
+		assert.Equal(t, []string{"galletas", "galletas-avena"}, ids(results), "Expected a match in the name to rank higher")
+	})
+
+	t.Run("Rank_NoMatches", func(t *testing.T) {
+		assert.Empty(t, Rank(Tokenize("detergente"), index), "Expected no results")
+		assert.Empty(t, Rank(nil, index), "Expected no results without words")
+	})
+}
+
+func TestDistance(t *testing.T) {
+	assert.Equal(t, 0, distance("leche", "leche", 2), "Expected no edits")
+	assert.Equal(t, 1, distance("leche", "lecje", 2), "Expected one substitution")
+	assert.Equal(t, 2, distance("galletas", "galeta", 2), "Expected two deletions")
+	assert.Equal(t, 3, distance("leche", "queso", 2), "Expected limit+1 over the limit")
+}
@@ -24,6 +24,13 @@ resource "aws_api_gateway_resource" "products" {
   path_part   = "products"
 }
 
+# Resource for API Gateway /api/v1/products/search endpoint
+resource "aws_api_gateway_resource" "products_search" {
+  rest_api_id = aws_api_gateway_rest_api.api.id
+  parent_id   = aws_api_gateway_resource.products.id
+  path_part   = "search"
+}
+
 # Resource for API Gateway /api/v1/products/{productId} endpoint
 resource "aws_api_gateway_resource" "product" {
   rest_api_id = aws_api_gateway_rest_api.api.id
@@ -31,6 +38,48 @@ resource "aws_api_gateway_resource" "product" {
   path_part   = "{productId}"
 }
 
+# Resource for API Gateway /api/v1/products/{productId}/history endpoint
+resource "aws_api_gateway_resource" "product_history" {
+  rest_api_id = aws_api_gateway_rest_api.api.id
+  parent_id   = aws_api_gateway_resource.product.id
+  path_part   = "history"
+}
+
+# Resource for API Gateway /api/v1/categories endpoint
+resource "aws_api_gateway_resource" "categories" {
+  rest_api_id = aws_api_gateway_rest_api.api.id
+  parent_id   = aws_api_gateway_resource.v1.id
+  path_part   = "categories"
+}
+
+# Resource for API Gateway /api/v1/categories/{slug} endpoint
+resource "aws_api_gateway_resource" "category" {
+  rest_api_id = aws_api_gateway_rest_api.api.id
+  parent_id   = aws_api_gateway_resource.categories.id
+  path_part   = "{slug}"
+}
+
+# Resource for API Gateway /api/v1/categories/{slug}/products endpoint
+resource "aws_api_gateway_resource" "category_products" {
+  rest_api_id = aws_api_gateway_rest_api.api.id
+  parent_id   = aws_api_gateway_resource.category.id
+  path_part   = "products"
+}
+
+# Resource for API Gateway /api/v1/scrapes endpoint
+resource "aws_api_gateway_resource" "scrapes" {
+  rest_api_id = aws_api_gateway_rest_api.api.id
+  parent_id   = aws_api_gateway_resource.v1.id
+  path_part   = "scrapes"
+}
+
+# Resource for API Gateway /api/v1/scrapes/{runId} endpoint
+resource "aws_api_gateway_resource" "scrape" {
+  rest_api_id = aws_api_gateway_rest_api.api.id
+  parent_id   = aws_api_gateway_resource.scrapes.id
+  path_part   = "{runId}"
+}
+
 # Resource for API Gateway /api/v1/users endpoint
 resource "aws_api_gateway_resource" "users" {
   rest_api_id = aws_api_gateway_rest_api.api.id
@@ -60,6 +109,14 @@ resource "aws_api_gateway_method" "get_products" {
   authorization = "NONE"
 }
 
+# Method for GET /api/v1/products/search endpoint
+resource "aws_api_gateway_method" "get_products_search" {
+  rest_api_id   = aws_api_gateway_rest_api.api.id
+  resource_id   = aws_api_gateway_resource.products_search.id
+  http_method   = "GET"
+  authorization = "NONE"
+}
+
 # Method for GET /api/v1/products/{productId} endpoint
 resource "aws_api_gateway_method" "get_product" {
   rest_api_id   = aws_api_gateway_rest_api.api.id
@@ -68,6 +125,48 @@ resource "aws_api_gateway_method" "get_product" {
   authorization = "NONE"
 }
 
+# Method for GET /api/v1/products/{productId}/history endpoint
+resource "aws_api_gateway_method" "get_product_history" {
+  rest_api_id   = aws_api_gateway_rest_api.api.id
+  resource_id   = aws_api_gateway_resource.product_history.id
+  http_method   = "GET"
+  authorization = "NONE"
+}
+
+# Method for GET /api/v1/categories endpoint
+resource "aws_api_gateway_method" "get_categories" {
+  rest_api_id   = aws_api_gateway_rest_api.api.id
+  resource_id   = aws_api_gateway_resource.categories.id
+  http_method   = "GET"
+  authorization = "NONE"
+}
+
+# Method for GET /api/v1/categories/{slug}/products endpoint
+resource "aws_api_gateway_method" "get_category_products" {
+  rest_api_id   = aws_api_gateway_rest_api.api.id
+  resource_id   = aws_api_gateway_resource.category_products.id
+  http_method   = "GET"
+  authorization = "NONE"
+}
+
+# Method for GET /api/v1/scrapes endpoint
+resource "aws_api_gateway_method" "get_scrapes" {
+  rest_api_id   = aws_api_gateway_rest_api.api.id
+  resource_id   = aws_api_gateway_resource.scrapes.id
+  http_method   = "GET"
+  authorization = "CUSTOM"
+  authorizer_id = aws_api_gateway_authorizer.jwt_authorizer.id
+}
+
+# Method for GET /api/v1/scrapes/{runId} endpoint
+resource "aws_api_gateway_method" "get_scrape" {
+  rest_api_id   = aws_api_gateway_rest_api.api.id
+  resource_id   = aws_api_gateway_resource.scrape.id
+  http_method   = "GET"
+  authorization = "CUSTOM"
+  authorizer_id = aws_api_gateway_authorizer.jwt_authorizer.id
+}
+
 # Authorizer for API Gateway
 resource "aws_api_gateway_authorizer" "jwt_authorizer" {
   rest_api_id = aws_api_gateway_rest_api.api.id
@@ -75,6 +174,10 @@ resource "aws_api_gateway_authorizer" "jwt_authorizer" {
   type        = "TOKEN"
   authorizer_uri = aws_lambda_function.authorizer.invoke_arn
   identity_source = "method.request.header.Authorization"
+
+  # La politica solo permite el metodo que la pidio; con cache, el mismo token
+  # quedaria denegado en los otros metodos protegidos
+  authorizer_result_ttl_in_seconds = 0
 }
 
 # Method for POST /api/v1/products endpoint
@@ -129,6 +232,17 @@ resource "aws_api_gateway_integration" "products_lambda_integration" {
   uri                     = aws_lambda_function.api_products.invoke_arn
 }
 
+# Integration for GET /api/v1/products/search endpoint
+resource "aws_api_gateway_integration" "products_search_lambda_integration" {
+  rest_api_id = aws_api_gateway_rest_api.api.id
+  resource_id = aws_api_gateway_resource.products_search.id
+  http_method = aws_api_gateway_method.get_products_search.http_method
+
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.api_products.invoke_arn
+}
+
 # Integration for GET /api/v1/products/{productId} endpoint
 resource "aws_api_gateway_integration" "product_lambda_integration" {
   rest_api_id = aws_api_gateway_rest_api.api.id
@@ -140,6 +254,39 @@ resource "aws_api_gateway_integration" "product_lambda_integration" {
   uri                     = aws_lambda_function.api_products.invoke_arn
 }
 
+# Integration for GET /api/v1/products/{productId}/history endpoint
+resource "aws_api_gateway_integration" "product_history_lambda_integration" {
+  rest_api_id = aws_api_gateway_rest_api.api.id
+  resource_id = aws_api_gateway_resource.product_history.id
+  http_method = aws_api_gateway_method.get_product_history.http_method
+
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.api_products.invoke_arn
+}
+
+# Integration for GET /api/v1/categories endpoint
+resource "aws_api_gateway_integration" "categories_lambda_integration" {
+  rest_api_id = aws_api_gateway_rest_api.api.id
+  resource_id = aws_api_gateway_resource.categories.id
+  http_method = aws_api_gateway_method.get_categories.http_method
+
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.api_products.invoke_arn
+}
+
+# Integration for GET /api/v1/categories/{slug}/products endpoint
+resource "aws_api_gateway_integration" "category_products_lambda_integration" {
+  rest_api_id = aws_api_gateway_rest_api.api.id
+  resource_id = aws_api_gateway_resource.category_products.id
+  http_method = aws_api_gateway_method.get_category_products.http_method
+
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.api_products.invoke_arn
+}
+
 # Integration for POST /api/v1/products endpoint
 resource "aws_api_gateway_integration" "post_products_lambda_integration" {
   rest_api_id = aws_api_gateway_rest_api.api.id
@@ -151,6 +298,28 @@ resource "aws_api_gateway_integration" "post_products_lambda_integration" {
   uri                     = aws_lambda_function.api_products.invoke_arn
 }
 
+# Integration for GET /api/v1/scrapes endpoint
+resource "aws_api_gateway_integration" "scrapes_lambda_integration" {
+  rest_api_id = aws_api_gateway_rest_api.api.id
+  resource_id = aws_api_gateway_resource.scrapes.id
+  http_method = aws_api_gateway_method.get_scrapes.http_method
+
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.api_products.invoke_arn
+}
+
+# Integration for GET /api/v1/scrapes/{runId} endpoint
+resource "aws_api_gateway_integration" "scrape_lambda_integration" {
+  rest_api_id = aws_api_gateway_rest_api.api.id
+  resource_id = aws_api_gateway_resource.scrape.id
+  http_method = aws_api_gateway_method.get_scrape.http_method
+
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.api_products.invoke_arn
+}
+
 # Integration for GET /api/v1/users endpoint
 resource "aws_api_gateway_integration" "users_lambda_integration" {
   rest_api_id = aws_api_gateway_rest_api.api.id
@@ -225,8 +394,14 @@ resource "aws_lambda_permission" "api_gateway_authorizer" {
 resource "aws_api_gateway_deployment" "api_deployment" {
   depends_on = [
     aws_api_gateway_integration.products_lambda_integration,
+    aws_api_gateway_integration.products_search_lambda_integration,
     aws_api_gateway_integration.product_lambda_integration,
+    aws_api_gateway_integration.product_history_lambda_integration,
+    aws_api_gateway_integration.categories_lambda_integration,
+    aws_api_gateway_integration.category_products_lambda_integration,
     aws_api_gateway_integration.post_products_lambda_integration,
+    aws_api_gateway_integration.scrapes_lambda_integration,
+    aws_api_gateway_integration.scrape_lambda_integration,
     aws_api_gateway_integration.users_lambda_integration,
     aws_api_gateway_integration.user_lambda_integration,
     aws_api_gateway_integration.post_users_lambda_integration,
@@ -239,8 +414,14 @@ resource "aws_api_gateway_deployment" "api_deployment" {
   triggers = {
     redeployment = sha1(jsonencode([
       aws_api_gateway_integration.products_lambda_integration.id,
+      aws_api_gateway_integration.products_search_lambda_integration.id,
       aws_api_gateway_integration.product_lambda_integration.id,
+      aws_api_gateway_integration.product_history_lambda_integration.id,
+      aws_api_gateway_integration.categories_lambda_integration.id,
+      aws_api_gateway_integration.category_products_lambda_integration.id,
       aws_api_gateway_integration.post_products_lambda_integration.id,
+      aws_api_gateway_integration.scrapes_lambda_integration.id,
+      aws_api_gateway_integration.scrape_lambda_integration.id,
       aws_api_gateway_integration.users_lambda_integration.id,
       aws_api_gateway_integration.user_lambda_integration.id,
       aws_api_gateway_integration.post_users_lambda_integration.id,
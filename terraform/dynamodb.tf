@@ -7,6 +7,151 @@ resource "aws_dynamodb_table" "products_table" {
     name = "ProductID"
     type = "S"
   }
+
+  attribute {
+    name = "Listing"
+    type = "S"
+  }
+
+  attribute {
+    name = "SortName"
+    type = "S"
+  }
+
+  attribute {
+    name = "SortPrice"
+    type = "N"
+  }
+
+  attribute {
+    name = "DiscountPercent"
+    type = "N"
+  }
+
+  attribute {
+    name = "ListingCategory"
+    type = "S"
+  }
+
+  # Indices del listado de productos, uno por orden. Solo los productos
+  # activos tienen Listing, asi los descontinuados no aparecen.
+  global_secondary_index {
+    name            = "ListingNameIndex"
+    hash_key        = "Listing"
+    range_key       = "SortName"
+    projection_type = "ALL"
+  }
+
+  global_secondary_index {
+    name            = "ListingPriceIndex"
+    hash_key        = "Listing"
+    range_key       = "SortPrice"
+    projection_type = "ALL"
+  }
+
+  global_secondary_index {
+    name            = "ListingDiscountIndex"
+    hash_key        = "Listing"
+    range_key       = "DiscountPercent"
+    projection_type = "ALL"
+  }
+
+  # Indices de los productos activos de una categoria, uno por orden.
+  # ListingCategory es el slug de la categoria y, igual que Listing, solo
+  # existe en los activos.
+  global_secondary_index {
+    name            = "CategoryIndex"
+    hash_key        = "ListingCategory"
+    range_key       = "SortName"
+    projection_type = "ALL"
+  }
+
+  global_secondary_index {
+    name            = "CategoryPriceIndex"
+    hash_key        = "ListingCategory"
+    range_key       = "SortPrice"
+    projection_type = "ALL"
+  }
+
+  global_secondary_index {
+    name            = "CategoryDiscountIndex"
+    hash_key        = "ListingCategory"
+    range_key       = "DiscountPercent"
+    projection_type = "ALL"
+  }
+}
+
+resource "aws_dynamodb_table" "price_history_table" {
+  name         = "PriceHistory"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "ProductID"
+  range_key    = "Date"
+
+  attribute {
+    name = "ProductID"
+    type = "S"
+  }
+
+  attribute {
+    name = "Date"
+    type = "S"
+  }
+}
+
+resource "aws_dynamodb_table" "scrape_runs_table" {
+  name         = "ScrapeRuns"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "RunID"
+
+  attribute {
+    name = "RunID"
+    type = "S"
+  }
+}
+
+resource "aws_dynamodb_table" "scrape_checkpoints_table" {
+  name         = "ScrapeCheckpoints"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "RunID"
+
+  attribute {
+    name = "RunID"
+    type = "S"
+  }
+}
+
+resource "aws_dynamodb_table" "categories_table" {
+  name         = "Categories"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "Store"
+  range_key    = "Slug"
+
+  attribute {
+    name = "Store"
+    type = "S"
+  }
+
+  attribute {
+    name = "Slug"
+    type = "S"
+  }
+}
+
+resource "aws_dynamodb_table" "search_index_table" {
+  name         = "SearchIndex"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "Prefix"
+  range_key    = "Key"
+
+  attribute {
+    name = "Prefix"
+    type = "S"
+  }
+
+  attribute {
+    name = "Key"
+    type = "S"
+  }
 }
 
 resource "aws_dynamodb_table" "users_table" {
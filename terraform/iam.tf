@@ -19,7 +19,7 @@ resource "aws_iam_role" "lambda_role" {
 # Policy for Lambda to access DynamoDB Products table
 resource "aws_iam_policy" "lambda_policy" {
   name        = "lambda_policy"
-  description = "IAM policy for Lambda to access Products DynamoDB table"
+  description = "IAM policy for Lambda to access Products, PriceHistory, ScrapeRuns, ScrapeCheckpoints and Categories DynamoDB tables"
   policy = jsonencode({
     Version = "2012-10-17"
     Statement = [
@@ -29,11 +29,25 @@ resource "aws_iam_policy" "lambda_policy" {
           "dynamodb:GetItem",
           "dynamodb:UpdateItem",
           "dynamodb:DeleteItem",
+          "dynamodb:BatchWriteItem",
+          "dynamodb:BatchGetItem",
           "dynamodb:Scan",
           "dynamodb:Query"
         ]
+        Effect = "Allow"
+        Resource = [
+          aws_dynamodb_table.products_table.arn,
+          aws_dynamodb_table.price_history_table.arn,
+          aws_dynamodb_table.scrape_runs_table.arn,
+          aws_dynamodb_table.scrape_checkpoints_table.arn,
+          aws_dynamodb_table.categories_table.arn,
+          aws_dynamodb_table.search_index_table.arn
+        ]
+      },
+      {
+        Action   = "dynamodb:Query"
         Effect   = "Allow"
-        Resource = aws_dynamodb_table.products_table.arn
+        Resource = "${aws_dynamodb_table.products_table.arn}/index/*"
       }
     ]
   })
@@ -29,7 +29,10 @@ resource "aws_lambda_function" "scraper" {
 
   environment {
     variables = {
-      TABLE_NAME = aws_dynamodb_table.products_table.name
+      TABLE_NAME         = aws_dynamodb_table.products_table.name
+      STORES_CONFIG      = var.scraper_stores_config
+      SCRAPER_USER_AGENT = var.scraper_user_agent
+      RESPECT_ROBOTS_TXT = tostring(var.scraper_respect_robots_txt)
     }
   }
 }
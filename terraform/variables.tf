@@ -0,0 +1,17 @@
+variable "scraper_stores_config" {
+  description = "YAML or JSON list of stores with their selectors. Empty uses the config embedded in the scraper."
+  type        = string
+  default     = ""
+}
+
+variable "scraper_user_agent" {
+  description = "User agent sent by the scraper. Empty uses the scraper default."
+  type        = string
+  default     = ""
+}
+
+variable "scraper_respect_robots_txt" {
+  description = "Whether the scraper skips URLs disallowed by the store robots.txt."
+  type        = bool
+  default     = true
+}